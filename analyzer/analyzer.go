@@ -0,0 +1,241 @@
+// Package analyzer defines an analysis that checks the use of enumeration
+// types generated by enumgen.
+//
+// The analysis reports:
+//
+//   - A switch statement on a sealed enumeration type that does not list all
+//     of its enumerators and has no default case.
+//   - A switch statement on an extensible enumeration type (one whose doc
+//     comment has the gen.ExtensibleDirective) that has no default case.
+//   - A composite literal of an enumeration type outside the package that
+//     defines it. Such a literal can only denote the zero enumerator, which
+//     is clearer written as a named zero enumerator or a zero-valued variable.
+//
+// Enumeration types are recognized by their declarations in files generated
+// by enumgen, and the results are shared across packages as analysis facts,
+// so the analysis must also be run on the packages that define the types.
+// To run it with go vet, build the enumgen-vet command and pass it as the
+// vet tool:
+//
+//	go install github.com/creachadair/enumgen/analyzer/enumgen-vet@latest
+//	go vet -vettool=$(which enumgen-vet) ./...
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/creachadair/enumgen/gen"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer checks switches on and literals of enumeration types generated by
+// enumgen.
+var Analyzer = &analysis.Analyzer{
+	Name:      "enumgen",
+	Doc:       "check switches on and literals of enumeration types generated by enumgen",
+	URL:       "https://pkg.go.dev/github.com/creachadair/enumgen/analyzer",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(enumFact)},
+}
+
+// generatedMarker is the prefix of the first line of a file generated by
+// enumgen.
+const generatedMarker = "// Code generated by enumgen. DO NOT EDIT."
+
+// An enumFact records that a named type is a generated enumeration.
+type enumFact struct {
+	Members    []string // the names of the non-zero enumerators, in order
+	Extensible bool     // whether the enumeration is marked as extensible
+}
+
+// AFact marks enumFact as an analysis fact.
+func (*enumFact) AFact() {}
+
+func (f *enumFact) String() string {
+	var ext string
+	if f.Extensible {
+		ext = " (extensible)"
+	}
+	return "enum" + ext + ": " + strings.Join(f.Members, ", ")
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, f := range pass.Files {
+		if isGenerated(f) {
+			exportFacts(pass, f)
+		}
+	}
+
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodes := []ast.Node{(*ast.SwitchStmt)(nil), (*ast.CompositeLit)(nil)}
+	ins.Preorder(nodes, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.SwitchStmt:
+			checkSwitch(pass, n)
+		case *ast.CompositeLit:
+			checkLiteral(pass, n)
+		}
+	})
+	return nil, nil
+}
+
+// isGenerated reports whether f was generated by enumgen.
+func isGenerated(f *ast.File) bool {
+	return len(f.Comments) != 0 && len(f.Comments[0].List) != 0 &&
+		f.Comments[0].List[0].Text == generatedMarker
+}
+
+// exportFacts exports an enumFact for each enumeration type declared in the
+// generated file f.
+func exportFacts(pass *analysis.Pass, f *ast.File) {
+	enums := make(map[*types.TypeName]*enumFact)
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok || len(st.Fields.List) != 1 || len(st.Fields.List[0].Names) != 1 ||
+				st.Fields.List[0].Names[0].Name != "_"+ts.Name.Name {
+				continue
+			}
+			tn, ok := pass.TypesInfo.Defs[ts.Name].(*types.TypeName)
+			if !ok {
+				continue
+			}
+			enums[tn] = &enumFact{Extensible: hasDirective(gd.Doc) || hasDirective(ts.Doc)}
+		}
+	}
+
+	// The enumerators are the package-level variables initialized with
+	// non-empty, non-zero literals of an enumeration type.
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, val := range vs.Values {
+				lit, ok := val.(*ast.CompositeLit)
+				if !ok || i >= len(vs.Names) || isZeroLiteral(lit) {
+					continue
+				}
+				if fact := enums[namedType(pass.TypesInfo.TypeOf(lit))]; fact != nil {
+					fact.Members = append(fact.Members, vs.Names[i].Name)
+				}
+			}
+		}
+	}
+	for tn, fact := range enums {
+		pass.ExportObjectFact(tn, fact)
+	}
+}
+
+// hasDirective reports whether doc contains the extensible directive.
+func hasDirective(doc *ast.CommentGroup) bool {
+	return doc != nil && slices.ContainsFunc(doc.List, func(c *ast.Comment) bool {
+		return c.Text == gen.ExtensibleDirective
+	})
+}
+
+// isZeroLiteral reports whether lit is a literal of the zero enumerator.
+func isZeroLiteral(lit *ast.CompositeLit) bool {
+	if len(lit.Elts) == 0 {
+		return true
+	}
+	b, ok := lit.Elts[0].(*ast.BasicLit)
+	return ok && b.Value == "0"
+}
+
+// namedType returns the type name of t if it is a named type, otherwise nil.
+func namedType(t types.Type) *types.TypeName {
+	if n, ok := types.Unalias(t).(*types.Named); ok {
+		return n.Obj()
+	}
+	return nil
+}
+
+// enumOf returns the type name and fact of the enumeration type t, or nil if
+// t is not an enumeration type.
+func enumOf(pass *analysis.Pass, t types.Type) (*types.TypeName, *enumFact) {
+	tn := namedType(t)
+	if tn == nil {
+		return nil, nil
+	}
+	var fact enumFact
+	if !pass.ImportObjectFact(tn, &fact) {
+		return nil, nil
+	}
+	return tn, &fact
+}
+
+// checkSwitch reports a switch on an enumeration type that is missing cases.
+func checkSwitch(pass *analysis.Pass, sw *ast.SwitchStmt) {
+	if sw.Tag == nil {
+		return
+	}
+	tn, fact := enumOf(pass, pass.TypesInfo.TypeOf(sw.Tag))
+	if fact == nil {
+		return
+	}
+	seen := make(map[string]bool)
+	for _, stmt := range sw.Body.List {
+		cc := stmt.(*ast.CaseClause)
+		if cc.List == nil {
+			return // a default case handles the rest
+		}
+		for _, expr := range cc.List {
+			if v := usedVar(pass, expr); v != nil && v.Pkg() == tn.Pkg() {
+				seen[v.Name()] = true
+			}
+		}
+	}
+	if fact.Extensible {
+		pass.Reportf(sw.Pos(), "switch on extensible enumeration %s has no default case", tn.Name())
+		return
+	}
+	var missing []string
+	for _, m := range fact.Members {
+		if !seen[m] {
+			missing = append(missing, m)
+		}
+	}
+	if len(missing) != 0 {
+		pass.Reportf(sw.Pos(), "switch on %s is missing cases for %s", tn.Name(), strings.Join(missing, ", "))
+	}
+}
+
+// usedVar returns the package-level variable denoted by expr, or nil.
+func usedVar(pass *analysis.Pass, expr ast.Expr) *types.Var {
+	var id *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	default:
+		return nil
+	}
+	v, _ := pass.TypesInfo.Uses[id].(*types.Var)
+	return v
+}
+
+// checkLiteral reports a literal of an enumeration type outside the package
+// that defines it.
+func checkLiteral(pass *analysis.Pass, lit *ast.CompositeLit) {
+	tn, fact := enumOf(pass, pass.TypesInfo.TypeOf(lit))
+	if fact == nil || tn.Pkg() == pass.Pkg {
+		return
+	}
+	pass.Reportf(lit.Pos(), "literal of enumeration %s outside its package", tn.Name())
+}
@@ -0,0 +1,12 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"github.com/creachadair/enumgen/analyzer"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), analyzer.Analyzer, "colors", "use")
+}
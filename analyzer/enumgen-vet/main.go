@@ -0,0 +1,14 @@
+// Program enumgen-vet checks the use of enumeration types generated by
+// enumgen. It can be run directly on packages, or as a vet tool:
+//
+//	go vet -vettool=$(which enumgen-vet) ./...
+//
+// See the analyzer package for a description of the checks.
+package main
+
+import (
+	"github.com/creachadair/enumgen/analyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() { singlechecker.Main(analyzer.Analyzer) }
@@ -0,0 +1,12 @@
+package colors
+
+// Literals inside the defining package are fine.
+var Default = Color{}
+
+func isWarm(c Color) bool {
+	switch c { // want `switch on Color is missing cases for Blue`
+	case Red, Green:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package use
+
+import "colors"
+
+var zero = colors.Color{} // want `literal of enumeration Color outside its package`
+
+var all = []colors.Color{colors.Red, {}} // want `literal of enumeration Color outside its package`
+
+func name(c colors.Color) string {
+	switch c { // want `switch on Color is missing cases for Green, Blue`
+	case colors.Red:
+		return "red"
+	}
+	return "?"
+}
+
+func complete(c colors.Color) int {
+	switch c {
+	case colors.Red:
+		return 1
+	case colors.Green, colors.Blue:
+		return 2
+	}
+	return 0
+}
+
+func withDefault(c colors.Color) int {
+	switch c {
+	case colors.Red:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func mood(m colors.Mood) bool {
+	switch m { // want `switch on extensible enumeration Mood has no default case`
+	case colors.Happy, colors.Sad:
+		return true
+	}
+	return false
+}
+
+func moodDefault(m colors.Mood) bool {
+	switch m {
+	case colors.Happy:
+		return true
+	default:
+		return false
+	}
+}
+
+func notEnum(s string) int {
+	switch s {
+	case "a":
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,139 @@
+// Package enum provides generic support for enumeration types generated by
+// enumgen with the runtime option.
+//
+// With the runtime option, the generated parsing and decoding functions for a
+// type delegate to the functions of this package instead of defining their
+// own loops over the string table, which reduces the size of the generated
+// code. The functions are also useful to write generic code over enumerations,
+// for example:
+//
+//	for _, c := range enum.Values[Color]() { ... }
+package enum
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Enum is the interface satisfied by enumeration types generated with the
+// runtime option. The type parameter T is the enumeration type itself.
+type Enum[T any] interface {
+	comparable
+
+	// Enum returns the name of the enumeration type.
+	Enum() string
+
+	// String returns the string representation of the enumerator.
+	String() string
+
+	// Valid reports whether the enumerator is a valid non-zero value.
+	Valid() bool
+
+	// Enumerators returns the valid enumerators of the type, in order.
+	// The caller must not modify the result.
+	Enumerators() []T
+}
+
+// Values returns a new slice of the valid enumerators of T, in order.
+func Values[T Enum[T]]() []T {
+	var zero T
+	return slices.Clone(zero.Enumerators())
+}
+
+// Lookup returns the first enumerator of T whose string is a case-insensitive
+// match for s. If no enumerator matches, it returns the zero enumerator.
+func Lookup[T Enum[T]](s string) T {
+	var zero T
+	for _, v := range zero.Enumerators() {
+		if strings.EqualFold(v.String(), s) {
+			return v
+		}
+	}
+	return zero
+}
+
+// Parse returns the first enumerator of T whose string is a case-insensitive
+// match for s. If no enumerator matches, it reports an error.
+func Parse[T Enum[T]](s string) (T, error) {
+	if v := Lookup[T](s); v.Valid() {
+		return v, nil
+	}
+	var zero T
+	return zero, fmt.Errorf("invalid value for %s: %q", zero.Enum(), s)
+}
+
+// Must returns the first enumerator of T whose string is a case-insensitive
+// match for s. If no enumerator matches, it panics.
+func Must[T Enum[T]](s string) T {
+	v, err := Parse[T](s)
+	if err != nil {
+		panic(err.Error())
+	}
+	return v
+}
+
+// UnmarshalText sets *v to the enumerator of T whose string exactly matches
+// data. An empty slice, or the string of the zero enumerator, decodes to the
+// zero value. It reports an error if data does not encode a known enumerator.
+func UnmarshalText[T Enum[T]](data []byte, v *T) error {
+	var zero T
+	*v = zero
+	text := string(data)
+	if text == "" || text == zero.String() {
+		return nil
+	}
+	for _, e := range zero.Enumerators() {
+		if e.String() == text {
+			*v = e
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value for %s: %q", zero.Enum(), text)
+}
+
+// MarshalJSON encodes v as a JSON string containing its string representation.
+func MarshalJSON[T Enum[T]](v T) ([]byte, error) { return json.Marshal(v.String()) }
+
+// UnmarshalJSON decodes a JSON string from data into *v, as UnmarshalText.
+func UnmarshalJSON[T Enum[T]](data []byte, v *T) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return UnmarshalText([]byte(text), v)
+}
+
+// ParseList parses s as a list of enumerators of T separated by sep, or by ","
+// if sep == "". Each element is trimmed of surrounding whitespace, and empty
+// elements are skipped. Each remaining element must exactly match the string
+// representation of a non-zero enumerator. If unique is true, it is an error
+// for an enumerator to occur more than once.
+func ParseList[T Enum[T]](s, sep string, unique bool) ([]T, error) {
+	if sep == "" {
+		sep = ","
+	}
+	var zero T
+	var out []T
+	seen := make(map[T]bool)
+next:
+	for _, elt := range strings.Split(s, sep) {
+		elt = strings.TrimSpace(elt)
+		if elt == "" {
+			continue
+		}
+		for _, v := range zero.Enumerators() {
+			if v.String() == elt {
+				if unique && seen[v] {
+					return nil, fmt.Errorf("duplicate value for %s: %q", zero.Enum(), elt)
+				}
+				seen[v] = true
+				out = append(out, v)
+				continue next
+			}
+		}
+		return nil, fmt.Errorf("invalid value for %s: %q", zero.Enum(), elt)
+	}
+	return out, nil
+}
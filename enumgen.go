@@ -2,53 +2,1066 @@
 // the "go generate" tool.
 //
 // The generator reads a configuration file in YAML format (see gen.Config).
+// A config whose path ends in ".json" is read as JSON, with the same keys.
 // To generate types, add:
 //
 //	//go:generate -command enumgen go run github.com/creachadair/enumgen@latest
 //	//go:generate enumgen -config enums.yml -output generated.go
+//
+// To start a new config, use the init subcommand. It writes a config with an
+// example enumeration and comments listing the available options, and prints
+// the matching go:generate directives. If the -config path ends in ".go", the
+// config is written as a Go source file with an enumgen:type comment, which
+// includes the directives:
+//
+//	enumgen init -package color -config enums.yml -output generated.go
+//
+// To migrate an existing enumeration defined as a named integer type with
+// iota-based constants, use -import-const to print an equivalent config:
+//
+//	enumgen -import-const ./path/to/pkg:Color > enums.yml
+//
+// To summarize the semantic differences between two versions of a config,
+// use the diff subcommand (or equivalently, the -diff-config flag):
+//
+//	enumgen diff old.yml new.yml
+//
+// With -check, diff exits with a non-zero status if any of the changes are
+// backward-incompatible, meaning that an enumerator was removed or renamed,
+// or that an index was changed or reused. This is useful in presubmit checks:
+//
+//	enumgen diff -check old.yml new.yml
+//
+// To export a table of the type, name, index, string, and doc of every
+// enumerator, for example to share the authoritative list of values with
+// other teams, use the export subcommand with -format csv or tsv:
+//
+//	enumgen export -config enums.yml -format csv > enums.csv
+//
+// To check configs for errors without generating code, use the vet
+// subcommand. Each config is checked against the schema of the config format,
+// and then validated, and all the problems found are reported with their
+// positions. With -schema, vet prints the schema instead, as a JSON Schema
+// document for use by editors and other tools (see gen.ConfigSchema):
+//
+//	enumgen vet enums.yml
+//	enumgen vet -schema > enumgen.schema.json
+//
+// To generate the outputs of every config in a module with one command, rather
+// than with go:generate directives scattered among its packages, list the
+// configs and their outputs in an enumgen.work manifest at the module root
+// (see gen.Manifest), and use the build subcommand. The relative paths in the
+// manifest are resolved relative to its directory, and build uses the nearest
+// manifest in the working directory or its parents unless -manifest is given.
+// Build also accepts -verify, -incremental, and -keep-going:
+//
+//	enumgen build
+//	enumgen build -verify
+//
+// To generate enumerations for every package in a tree that contains Go files
+// with enumgen:type or enumgen:package comments (or YAML configs marked with
+// "merge: true"), use -recursive. The -output flag gives the name of the file
+// to generate in each package directory:
+//
+//	enumgen -recursive -outdir . -output enums_generated.go
+//
+// To also export the enumerations as a JSON Schema or OpenAPI document, use
+// -schema with a path ending in .json or .yaml respectively:
+//
+//	enumgen -config enums.yml -output generated.go -schema openapi.yaml
+//
+// Similarly, to write SQL data definitions for the enumerations, use -sql-ddl
+// with the name of a file, and -sql-dialect to choose the SQL dialect:
+//
+//	enumgen -config enums.yml -output generated.go -sql-ddl enums.sql -sql-dialect postgres
+//
+// To write TypeScript definitions matching the strings of the enumerations,
+// for example to share them with a web frontend, use -ts with the name of a
+// file, and -ts-style to choose union types (the default) or const objects:
+//
+//	enumgen -config enums.yml -output generated.go -ts enums.ts -ts-style const
+//
+// A config may also list other packages, each with its own output path
+// relative to the config file. In that case, an output file is written for
+// each package, and -output is only required if the config also defines
+// enumerations at the top level.
+//
+// To remove the gaps from the indices of the enumerators in a config, use
+// -remap to print a copy of the config with compacted indices. The former
+// indices are recorded so that a MigrateOld* function is generated for each
+// enumeration whose indices changed:
+//
+//	enumgen -remap enums.yml -output compacted.yml
+//
+// Enumerators may be included conditionally, using when conditions that refer
+// to tags given by the -tags flag, or to the name of the -profile:
+//
+//	enumgen -config enums.yml -output generated.go -tags experimental
+//
+// To apply a stricter style than gofmt to the output, use -format-cmd to name
+// a command that reads source on stdin and writes the result to stdout:
+//
+//	enumgen -config enums.yml -output generated.go -format-cmd gofumpt
+//
+// To debug templates or the generator itself, add -format=off to write the
+// source exactly as the templates produce it, without go/format or the
+// -format-cmd, rather than only when formatting fails:
+//
+//	enumgen -config enums.yml -output - -format=off
+//
+// To also record the version of the generator and the path of the config in
+// the header of the output, add -provenance. Tools can read this record with
+// gen.ReadProvenance.
+//
+// Every generated file records the content hash of its config in its header
+// (see gen.Config.ContentHash). To check that the outputs are up to date with
+// their configs without writing them, for example in a presubmit check, add
+// -verify. It reports an error for each output that is stale or missing:
+//
+//	enumgen -config enums.yml -output generated.go -verify
+//
+// To print metrics about the size of the generated code for each enumeration,
+// including an estimate of its contribution to the size of a binary, add
+// -size-report. The report is written to stderr.
+//
+// To use enumgen in a pipeline, use "-" as the -config path to read a YAML
+// config from stdin, and as the -output path to write the generated code to
+// stdout:
+//
+//	enumgen -config - -output - < enums.yml > generated.go
+//
+// If any enumeration enables quickcheck, property tests for its parsing
+// functions are also written to a test file beside the output, for example
+// generated_test.go for generated.go. Likewise, if any enumeration enables
+// tests, or the -gentest flag is set, table-driven tests of the methods and
+// encodings of the enumerations are written to the same file, and if any
+// enumeration enables examples, or the -genexamples flag is set, so are
+// runnable examples of printing and parsing each exported enumeration, for
+// the package documentation. If any enumeration enables json-v2, its
+// encoding/json/v2 methods are written to a file beside the output that
+// builds only with GOEXPERIMENT=jsonv2, for example generated_jsonv2.go for
+// generated.go.
+//
+// Unknown fields in a config, such as misspelled option names, are reported as
+// errors with their line and column. To ignore them instead, for example to
+// use a config written for a newer version of enumgen, add -lenient.
+//
+// Every problem found by validating a config is reported at once. To also
+// keep going after errors in generating the code of an enumeration, or in
+// one package of a config with packages or of a tree with -recursive, add
+// -keep-going. All the errors are reported together, and only the outputs
+// that have no errors are written.
+//
+// Multiple packages, whether listed in a config or found with -recursive, are
+// generated concurrently, up to -parallel at a time (by default, the number
+// of CPUs). To skip generating the outputs whose recorded content hash matches
+// their config, as for -verify, add -incremental. The content hash does not
+// cover the version of enumgen, so after updating it, regenerate without
+// -incremental:
+//
+//	enumgen -recursive -outdir . -output enums_generated.go -incremental
+//
+// To regenerate the outputs whenever the inputs change, for example while
+// iterating on a config, add -watch. The files in the directory of the config
+// (or the package directory, or the tree with -recursive) and the -template-dir
+// are polled for changes at the -watch-delay interval, and the outputs are
+// regenerated once the changes have settled for one interval:
+//
+//	enumgen -config enums.yml -output generated.go -watch
 package main
 
 import (
+	"bytes"
 	"errors"
 	"flag"
+	"fmt"
 	"log"
+	"maps"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
 
 	"github.com/creachadair/enumgen/gen"
 )
 
 var (
-	configPath = flag.String("config", "", "Configuration file path")
-	outputPath = flag.String("output", "", "Output file path (required)")
+	configPath  = flag.String("config", "", "Configuration file path (- for stdin)")
+	outputPath  = flag.String("output", "", "Output file path, or - for stdout (required)")
+	tmplDir     = flag.String("template-dir", "", "Directory of code templates (*.tmpl) to apply")
+	importPath  = flag.String("import-const", "", "Print a config for the const enum dir:Type and exit")
+	remapPath   = flag.String("remap", "", "Print a copy of this config with compacted indices and exit")
+	diffConfig  = flag.Bool("diff-config", false, "Print the differences between two configs (old new) and exit")
+	recursive   = flag.Bool("recursive", false, "Generate an -output file for every package in the tree at -outdir")
+	outDir      = flag.String("outdir", ".", "Root directory of the tree to process (with -recursive)")
+	schemaPath  = flag.String("schema", "", "Also write a JSON Schema (.json) or OpenAPI (.yaml) file")
+	profile     = flag.String("profile", "", "Apply the named config profile before generating")
+	sqlDDLPath  = flag.String("sql-ddl", "", "Also write SQL data definitions for the enumerations to this file")
+	sqlDialect  = flag.String("sql-dialect", "postgres", "SQL dialect for -sql-ddl (postgres, mysql, sqlite)")
+	tsPath      = flag.String("ts", "", "Also write TypeScript definitions for the enumerations to this file")
+	tsStyle     = flag.String("ts-style", "union", "Style of TypeScript definitions for -ts (union, const)")
+	formatCmd   = flag.String("format-cmd", "", "Command to reformat the generated source from stdin to stdout (e.g., gofumpt)")
+	formatMode  = flag.String("format", "on", "Format the generated source (on), or write it as the templates produce it (off)")
+	provenance  = flag.Bool("provenance", false, "Record the generator version and config path in the output")
+	sizeReport  = flag.Bool("size-report", false, "Print size metrics for each generated enumeration to stderr")
+	whenTags    = flag.String("tags", "", "Comma-separated tags for the when conditions of enumerators")
+	incRoot     = flag.String("include-root", "", "Root directory for config includes beginning with / (default: config directory)")
+	lenient     = flag.Bool("lenient", false, "Ignore unknown fields in configs rather than reporting errors")
+	watch       = flag.Bool("watch", false, "Regenerate the outputs whenever the input files change")
+	genTests    = flag.Bool("gentest", false, "Also write table-driven tests for every enumeration beside the output")
+	genExamples = flag.Bool("genexamples", false, "Also write documentation examples for every exported enumeration beside the output")
+	keepGoing   = flag.Bool("keep-going", false, "Report all errors rather than the first, and write only outputs without errors")
+	verify      = flag.Bool("verify", false, "Report an error if the outputs are stale with respect to the config, rather than writing them")
+	incFlag     = flag.Bool("incremental", false, "Skip outputs whose recorded config hash matches the config")
+	parallel    = flag.Int("parallel", runtime.GOMAXPROCS(0), "Maximum number of packages to generate concurrently")
+	watchDelay  = flag.Duration("watch-delay", 500*time.Millisecond, "Polling interval and quiet period for -watch")
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		runDiff(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "init" {
+		runInit(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		runExport(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "vet" {
+		runVet(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "build" {
+		runBuild(os.Args[2:])
+		return
+	}
 	flag.Parse()
+	if *diffConfig {
+		if err := diffConfigs(flag.Args(), false); err != nil {
+			log.Fatalf("Diff: %v", err)
+		}
+		return
+	}
+	if *importPath != "" {
+		if err := importConst(*importPath); err != nil {
+			log.Fatalf("Import: %v", err)
+		}
+		return
+	}
+	if *remapPath != "" {
+		if err := remapConfig(*remapPath); err != nil {
+			log.Fatalf("Remap: %v", err)
+		}
+		return
+	}
+	if *watch {
+		if *configPath == "-" || *outputPath == "-" {
+			log.Fatal("You cannot use -watch with stdin or stdout")
+		}
+		if err := watchAndGenerate(*watchDelay); err != nil {
+			log.Fatalf("Watch: %v", err)
+		}
+		return
+	}
+	if err := generate(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate generates the outputs selected by the flags.
+func generate() error {
+	if *recursive {
+		if *outputPath == "" {
+			return errors.New("You must specify an -output file path")
+		}
+		if err := generateTree(*outDir, *outputPath); err != nil {
+			return fmt.Errorf("Generate: %w", err)
+		}
+		return nil
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("Reading config: %w", err)
+	}
+	if len(cfg.Packages) != 0 {
+		if err := generatePackages(cfg); err != nil {
+			return fmt.Errorf("Generate: %w", err)
+		}
+	}
+	if len(cfg.Enum) != 0 || len(cfg.Packages) == 0 {
+		if *outputPath == "" {
+			return errors.New("You must specify an -output file path")
+		}
+		if err := generateFile(cfg, *outputPath); err != nil {
+			return fmt.Errorf("Generate: %w", err)
+		}
+	}
+	if *verify {
+		return nil // only the Go outputs record a content hash
+	}
+	if *schemaPath != "" {
+		if err := writeSchema(cfg, *schemaPath); err != nil {
+			return fmt.Errorf("Schema: %w", err)
+		}
+	}
+	if *sqlDDLPath != "" {
+		f, err := createOutput(*sqlDDLPath)
+		if err != nil {
+			return fmt.Errorf("SQL DDL: %w", err)
+		}
+		if err := errors.Join(cfg.GenerateSQLDDL(f, *sqlDialect), f.Close()); err != nil {
+			return fmt.Errorf("SQL DDL: %w", err)
+		}
+	}
+	if *tsPath != "" {
+		if err := writeTS(cfg, *tsPath, *tsStyle); err != nil {
+			return fmt.Errorf("TypeScript: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeTS writes TypeScript definitions for the enumerations in cfg to path,
+// in the named style.
+func writeTS(cfg *gen.Config, path, style string) error {
+	var opts gen.TSOptions
+	switch style {
+	case "union":
+		opts.Style = gen.TSUnion
+	case "const":
+		opts.Style = gen.TSConst
+	default:
+		return fmt.Errorf("unknown style %q (want union or const)", style)
+	}
+	f, err := createOutput(path)
+	if err != nil {
+		return err
+	}
+	return errors.Join(cfg.GenerateTS(f, opts), f.Close())
+}
+
+// writeSchema writes a schema for the enumerations in cfg to path. The format
+// of the schema is chosen by the extension of path.
+func writeSchema(cfg *gen.Config, path string) error {
+	var format gen.SchemaFormat
+	switch filepath.Ext(path) {
+	case ".json":
+		format = gen.JSONSchema
+	case ".yaml", ".yml":
+		format = gen.OpenAPI
+	default:
+		return fmt.Errorf("unknown schema format for %q (want .json, .yaml, or .yml)", path)
+	}
+	f, err := createOutput(path)
+	if err != nil {
+		return err
+	}
+	return errors.Join(cfg.EmitSchema(f, format), f.Close())
+}
+
+// generateFile generates the enumerations defined by cfg into the file at
+// path, applying the profile specified by the -profile flag, any templates
+// specified by the -template-dir flag, and the formatter specified by the
+// -format-cmd flag.
+func generateFile(cfg *gen.Config, path string) error {
+	if *profile != "" {
+		if err := cfg.ApplyProfile(*profile); err != nil {
+			return err
+		}
+	}
+	switch *formatMode {
+	case "on":
+	case "off":
+		cfg.NoFormat = true
+	default:
+		return fmt.Errorf("unknown -format %q (want on or off)", *formatMode)
+	}
+	if *formatCmd != "" {
+		cfg.Format = runFormatter
+	}
+	if *genTests {
+		for _, e := range cfg.Enum {
+			e.Tests = true
+		}
+	}
+	if *genExamples {
+		for _, e := range cfg.Enum {
+			e.Examples = e.Examples || !e.Unexported
+		}
+	}
+	if *provenance {
+		cfg.Provenance = &gen.Provenance{Version: gen.GeneratorVersion(), Config: *configPath}
+	}
+	if *tmplDir != "" {
+		tmpls, err := gen.ReadTemplates(*tmplDir)
+		if err != nil {
+			return fmt.Errorf("reading templates: %w", err)
+		}
+		if cfg.Templates == nil {
+			cfg.Templates = tmpls
+		} else {
+			maps.Copy(cfg.Templates, tmpls)
+		}
+	}
+	if *verify {
+		return verifyFile(cfg, path)
+	}
+	if *incFlag && path != "-" {
+		if stale, err := staleOutputs(cfg, path); err == nil && len(stale) == 0 {
+			log.Printf("Skipping %s, which is up to date", path)
+			return nil
+		}
+	}
+	log.Printf("Generating %d enumerations for package %q", len(cfg.Enum), cfg.Package)
+	if *sizeReport {
+		if err := printSizeReport(cfg); err != nil {
+			return err
+		}
+	}
+	if path == "-" {
+		if cfg.HasTests() {
+			return errors.New("cannot write tests when the output is stdout")
+		} else if cfg.HasJSONv2() {
+			return errors.New("cannot write json-v2 methods when the output is stdout")
+		}
+		if *keepGoing {
+			return cfg.GenerateAll(os.Stdout)
+		}
+		return cfg.Generate(os.Stdout)
+	}
+	if *keepGoing {
+		// Generate the complete output before creating the file, so that it is
+		// not replaced in case of error.
+		var buf bytes.Buffer
+		if err := cfg.GenerateAll(&buf); err != nil {
+			return err
+		}
+		if err := writeOutput(path, buf.Bytes()); err != nil {
+			return err
+		}
+	} else {
+		f, err := createOutput(path)
+		if err != nil {
+			return err
+		}
+		if err := errors.Join(cfg.Generate(f), f.Close()); err != nil {
+			return err
+		}
+	}
+	if cfg.HasJSONv2() {
+		// Write the json/v2 methods alongside the output, e.g., enums.go →
+		// enums_jsonv2.go.
+		jf, err := createOutput(gen.JSONv2Path(path))
+		if err != nil {
+			return err
+		}
+		if err := errors.Join(cfg.GenerateJSONv2(jf), jf.Close()); err != nil {
+			return err
+		}
+	}
+	if !cfg.HasTests() {
+		return nil
+	}
+
+	// Write tests alongside the output, e.g., enums.go → enums_test.go.
+	tf, err := createOutput(gen.TestPath(path))
+	if err != nil {
+		return err
+	}
+	return errors.Join(cfg.GenerateTests(tf), tf.Close())
+}
+
+// verifyFile reports an error if the generated file at path, or the test or
+// json/v2 file beside it if cfg has them, is stale with respect to cfg.
+func verifyFile(cfg *gen.Config, path string) error {
+	if path == "-" {
+		return errors.New("cannot verify the output when it is stdout")
+	}
+	stale, err := staleOutputs(cfg, path)
+	if err != nil {
+		return err
+	} else if len(stale) != 0 {
+		return fmt.Errorf("%s is stale; regenerate it from the config", strings.Join(stale, ", "))
+	}
+	log.Printf("Output %s is up to date", path)
+	return nil
+}
+
+// staleOutputs returns the paths of the outputs generated from cfg, the file
+// at path and the test and json/v2 files beside it if cfg has them, that are
+// missing or stale, meaning that the content hash recorded in their headers
+// does not match the current content hash of cfg.
+func staleOutputs(cfg *gen.Config, path string) ([]string, error) {
+	hash, err := cfg.ContentHash()
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{path}
+	if cfg.HasTests() {
+		paths = append(paths, gen.TestPath(path))
+	}
+	if cfg.HasJSONv2() {
+		paths = append(paths, gen.JSONv2Path(path))
+	}
+	var stale []string
+	for _, p := range paths {
+		src, err := os.ReadFile(p)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if gen.IsStale(hash, src) {
+			stale = append(stale, p)
+		}
+	}
+	return stale, nil
+}
+
+// forEach calls f for each integer in [0, n), running up to -parallel calls
+// concurrently, and returns the errors they report in order. Unless
+// -keep-going is set, no new calls are started once a call fails, and only
+// the first error is reported.
+func forEach(n int, f func(i int) error) error {
+	errs := make([]error, n)
+	sem := make(chan struct{}, max(*parallel, 1))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failed := false
+	for i := range n {
+		sem <- struct{}{}
+		mu.Lock()
+		stop := failed && !*keepGoing
+		mu.Unlock()
+		if stop {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			if err := f(i); err != nil {
+				mu.Lock()
+				errs[i], failed = err, true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if !*keepGoing {
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// printSizeReport prints a table of size metrics for the enumerations of cfg
+// to stderr.
+func printSizeReport(cfg *gen.Config) error {
+	sizes, err := cfg.SizeReport()
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stderr, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "TYPE\tLINES\tSYMBOLS\tFUNCS\tSTRINGS\tDATA\tEST. SIZE\t")
+	for _, s := range sizes {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t\n",
+			s.Type, s.Lines, s.Symbols, s.Funcs, s.StringBytes, s.DataBytes, s.EstimatedSize)
+	}
+	return tw.Flush()
+}
+
+// runFormatter runs the command specified by the -format-cmd flag with src as
+// its standard input, and returns its standard output.
+func runFormatter(src []byte) ([]byte, error) {
+	args := strings.Fields(*formatCmd)
+	if len(args) == 0 {
+		return nil, errors.New("empty -format-cmd")
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(src)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w\n%s", args[0], err, stderr.Bytes())
+	}
+	return out, nil
+}
+
+// generatePackages generates an output file for each of the packages listed
+// in cfg. Relative output paths are resolved relative to the directory of the
+// config file, and missing directories are created. All the packages are
+// validated before any output is written, including a check that outputs in
+// the same directory, including the -output file of the top-level
+// enumerations, do not declare the same names. Then the packages are
+// generated concurrently.
+func generatePackages(cfg *gen.Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	base := "."
+	if *configPath != "" {
+		base = filepath.Dir(*configPath)
+	}
+	pcs := cfg.PackageConfigs()
+	for _, pc := range pcs {
+		if !filepath.IsAbs(pc.Output) {
+			pc.Output = filepath.Join(base, pc.Output)
+		}
+	}
+	check := pcs
+	if len(cfg.Enum) != 0 && *outputPath != "" && *outputPath != "-" {
+		check = append(slices.Clip(pcs), &gen.PackageConfig{Output: *outputPath, Config: *cfg})
+	}
+	if err := gen.CheckOutputs(check); err != nil {
+		return err
+	}
+	return forEach(len(pcs), func(i int) error {
+		pc := pcs[i]
+		path := pc.Output
+		if !*verify {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+		}
+		if err := generateFile(&pc.Config, path); err != nil {
+			return fmt.Errorf("package %q: %w", pc.Package, err)
+		}
+		return nil
+	})
+}
+
+// generateTree generates an output file with the specified base name in each
+// package directory of the tree rooted at root whose Go source files contain
+// enumeration configs. The packages are generated concurrently.
+func generateTree(root, outName string) error {
+	if filepath.Base(outName) != outName {
+		return fmt.Errorf("with -recursive, -output must be a file name, not %q", outName)
+	}
+	dirs, err := gen.FindPackages(root)
+	if err != nil {
+		return err
+	}
+	err = forEach(len(dirs), func(i int) error {
+		cfg, err := loadPackageDir(dirs[i])
+		if err == nil {
+			err = generateFile(cfg, filepath.Join(dirs[i], outName))
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", dirs[i], err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Generated %d packages under %q", len(dirs), root)
+	return nil
+}
+
+// importConst prints a YAML config equivalent to the iota-based enumeration
+// described by spec, which has the form "dir:Type", to the output file, or to
+// stdout if no output file is specified.
+func importConst(spec string) error {
+	i := strings.LastIndex(spec, ":")
+	if i < 0 {
+		return fmt.Errorf("invalid import spec %q, want dir:Type", spec)
+	}
+	dir, typeName := spec[:i], spec[i+1:]
+	if dir == "" {
+		dir = "."
+	}
+	cfg, err := gen.ImportConstEnum(dir, typeName)
+	if err != nil {
+		return err
+	}
 	if *outputPath == "" {
-		log.Fatal("You must specify an -output file path")
+		return cfg.WriteYAML(os.Stdout)
+	}
+	f, err := os.Create(*outputPath)
+	if err != nil {
+		return err
+	}
+	return errors.Join(cfg.WriteYAML(f), f.Close())
+}
+
+// remapConfig prints a copy of the config at path with compacted indices to
+// the output file, or to stdout if no output file is specified, and logs the
+// indices that were changed. Includes are not resolved, so that the output
+// has the same structure as the input, but for that reason configs that use
+// values-from or base are not supported.
+func remapConfig(path string) error {
+	var cfg *gen.Config
+	var err error
+	if strings.HasSuffix(path, ".go") {
+		cfg, err = gen.ConfigFromGoFile(path)
+	} else if strings.HasSuffix(path, ".json") {
+		cfg, err = gen.ConfigFromJSON(path)
+	} else {
+		cfg, err = gen.ConfigFromYAML(path)
+	}
+	if err != nil {
+		return err
+	} else if err := checkFields(cfg); err != nil {
+		return err
+	}
+	enums := slices.Clip(cfg.Enum)
+	for _, p := range cfg.Packages {
+		enums = append(enums, p.Enum...)
+	}
+	for _, e := range enums {
+		if e.ValuesFrom != "" {
+			return fmt.Errorf("enum %q: values-from is not supported", e.Type)
+		} else if e.Base != "" {
+			return fmt.Errorf("enum %q: base is not supported", e.Type)
+		}
+	}
+	for _, c := range cfg.CompactIndexes() {
+		log.Print(c)
+	}
+	if *outputPath == "" {
+		return cfg.WriteYAML(os.Stdout)
+	}
+	f, err := os.Create(*outputPath)
+	if err != nil {
+		return err
+	}
+	return errors.Join(cfg.WriteYAML(f), f.Close())
+}
+
+// runDiff implements the diff subcommand with the given arguments.
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	check := fs.Bool("check", false, "Exit with an error if any changes are backward-incompatible")
+	fs.BoolVar(lenient, "lenient", false, "Ignore unknown fields in configs")
+	fs.StringVar(incRoot, "include-root", "", "Root directory for config includes beginning with /")
+	fs.StringVar(whenTags, "tags", "", "Comma-separated tags for the when conditions of enumerators")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: enumgen diff [-check] old.yml new.yml")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := diffConfigs(fs.Args(), *check); err != nil {
+		log.Fatalf("Diff: %v", err)
+	}
+}
+
+// runInit implements the init subcommand with the given arguments.
+func runInit(args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	pkg := fs.String("package", "", "Package name (default: the name of the current directory)")
+	force := fs.Bool("force", false, "Overwrite the config file if it exists")
+	fs.StringVar(configPath, "config", "enums.yml", "Config file path to write (.yml, .yaml, or .go)")
+	fs.StringVar(outputPath, "output", "generated.go", "Output file path for the go:generate directive")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: enumgen init [-package name] [-config path] [-output path]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := initConfig(*pkg, *force); err != nil {
+		log.Fatalf("Init: %v", err)
+	}
+}
+
+// initConfig writes a starter config for package pkg to the config file, and
+// prints the go:generate directives to use it if they are not included in
+// the file. Unless force is true, an existing file is not replaced.
+func initConfig(pkg string, force bool) error {
+	if pkg == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		pkg = filepath.Base(wd)
+	}
+	s := gen.Starter{Package: pkg, Path: *configPath, Output: *outputPath}
+	var buf bytes.Buffer
+	if err := s.Write(&buf); err != nil {
+		return err
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !force {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(s.Path, flags, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	} else if err := f.Close(); err != nil {
+		return err
+	}
+	log.Printf("Wrote starter config for package %q to %s", pkg, s.Path)
+	if !s.IsGo() {
+		fmt.Printf("Add these directives to a Go file in package %s:\n\n%s", pkg, s.Directives())
+	}
+	return nil
+}
+
+// runExport implements the export subcommand with the given arguments.
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "csv", "Output format (csv, tsv)")
+	fs.BoolVar(lenient, "lenient", false, "Ignore unknown fields in configs")
+	fs.StringVar(configPath, "config", "", "Configuration file path (- for stdin)")
+	fs.StringVar(outputPath, "output", "", "Output file path (default stdout)")
+	fs.StringVar(incRoot, "include-root", "", "Root directory for config includes beginning with /")
+	fs.StringVar(whenTags, "tags", "", "Comma-separated tags for the when conditions of enumerators")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: enumgen export [-format csv|tsv] [-config path] [-output path]")
+		fs.PrintDefaults()
 	}
+	fs.Parse(args)
+	if err := exportConfig(*format); err != nil {
+		log.Fatalf("Export: %v", err)
+	}
+}
 
+// exportConfig writes a table of the enumerators of the config to the output
+// file, or to stdout if no output file is specified, in the named format.
+func exportConfig(format string) error {
+	var ef gen.ExportFormat
+	switch format {
+	case "csv":
+		ef = gen.ExportCSV
+	case "tsv":
+		ef = gen.ExportTSV
+	default:
+		return fmt.Errorf("unknown format %q (want csv or tsv)", format)
+	}
 	cfg, err := loadConfig()
 	if err != nil {
-		log.Fatalf("Reading config: %v", err)
+		return err
+	}
+	if *outputPath == "" || *outputPath == "-" {
+		return cfg.Export(os.Stdout, ef)
 	}
 	f, err := os.Create(*outputPath)
 	if err != nil {
-		log.Fatalf("Output: %v", err)
+		return err
 	}
-	log.Printf("Generating %d enumerations for package %q", len(cfg.Enum), cfg.Package)
-	if err := errors.Join(cfg.Generate(f), f.Close()); err != nil {
-		log.Fatalf("Generate: %v", err)
+	return errors.Join(cfg.Export(f, ef), f.Close())
+}
+
+// runVet implements the vet subcommand with the given arguments.
+func runVet(args []string) {
+	fs := flag.NewFlagSet("vet", flag.ExitOnError)
+	printSchema := fs.Bool("schema", false, "Print the JSON Schema for configs and exit")
+	fs.StringVar(incRoot, "include-root", "", "Root directory for config includes beginning with /")
+	fs.StringVar(whenTags, "tags", "", "Comma-separated tags for the when conditions of enumerators")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: enumgen vet [-schema] config.yml ...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *printSchema {
+		os.Stdout.Write(gen.ConfigSchema())
+		return
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	var errs []error
+	for _, path := range fs.Args() {
+		if err := vetConfig(path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		log.Fatalf("Vet:\n%v", err)
+	}
+}
+
+// vetConfig reports the problems with the config at path: the values that do
+// not match the config schema, followed by the unknown fields and validation
+// errors of the config. A Go source file is not checked against the schema.
+func vetConfig(path string) error {
+	if !strings.HasSuffix(path, ".go") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := gen.CheckConfigSchema(path, data); err != nil {
+			return err // the config would not decode as intended
+		}
+	}
+	cfg, err := readConfig(path)
+	if err != nil {
+		return err
+	}
+	return cfg.Validate()
+}
+
+// runBuild implements the build subcommand with the given arguments.
+func runBuild(args []string) {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	manifest := fs.String("manifest", "", "Manifest file path (default: nearest "+gen.ManifestFile+")")
+	fs.StringVar(tmplDir, "template-dir", "", "Directory of code templates (*.tmpl) to apply")
+	fs.StringVar(incRoot, "include-root", "", "Root directory for config includes beginning with /")
+	fs.StringVar(whenTags, "tags", "", "Comma-separated tags for the when conditions of enumerators")
+	fs.BoolVar(lenient, "lenient", false, "Ignore unknown fields in configs rather than reporting errors")
+	fs.BoolVar(keepGoing, "keep-going", false, "Report all errors rather than the first, and write only outputs without errors")
+	fs.BoolVar(verify, "verify", false, "Report an error if the outputs are stale with respect to the configs, rather than writing them")
+	fs.BoolVar(incFlag, "incremental", false, "Skip outputs whose recorded config hash matches the config")
+	fs.IntVar(parallel, "parallel", runtime.GOMAXPROCS(0), "Maximum number of packages to generate concurrently")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: enumgen build [-manifest path] [-verify]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if err := buildManifest(*manifest); err != nil {
+		log.Fatalf("Build: %v", err)
 	}
 }
 
+// buildManifest generates the outputs of every config listed by the manifest
+// at path, or by the nearest manifest to the working directory if path is "".
+// The configs are generated in order. With -keep-going, the remaining configs
+// are generated after an error, and all the errors are reported together.
+func buildManifest(path string) error {
+	if path == "" {
+		var err error
+		path, err = gen.FindManifest(".")
+		if err != nil {
+			return err
+		}
+	}
+	m, err := gen.ReadManifest(path)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, e := range m.Configs {
+		*configPath, *outputPath = e.Config, e.Output
+		if err := generate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.Config, err))
+			if !*keepGoing {
+				break
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	if !*verify {
+		log.Printf("Generated %d configs from %q", len(m.Configs), path)
+	}
+	return nil
+}
+
+// diffConfigs prints a report of the differences between the configs named by
+// args, which must have the form [old, new]. If check is true, it reports an
+// error if any of the changes are backward-incompatible.
+func diffConfigs(args []string, check bool) error {
+	if len(args) != 2 {
+		return errors.New("usage: enumgen diff [-check] old.yml new.yml")
+	}
+	oldCfg, err := readConfig(args[0])
+	if err != nil {
+		return err
+	}
+	newCfg, err := readConfig(args[1])
+	if err != nil {
+		return err
+	}
+	r := gen.DiffConfigs(oldCfg, newCfg)
+	fmt.Print(r)
+	if b := r.Breaking(); check && len(b) != 0 {
+		return fmt.Errorf("found %d backward-incompatible changes", len(b))
+	}
+	return nil
+}
+
 func loadConfig() (*gen.Config, error) {
 	if *configPath == "" {
 		log.Print("Loading configuration from package source")
-		return gen.LoadPackage()
-	} else if strings.HasSuffix(*configPath, ".go") {
-		return gen.ConfigFromGoFile(*configPath)
+		return loadPackageDir(".")
+	}
+	return readConfig(*configPath)
+}
+
+// loadPackageDir loads the config from the Go source files in dir, resolves
+// its includes, and selects its enumerators.
+func loadPackageDir(dir string) (*gen.Config, error) {
+	cfg, err := gen.LoadPackageDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.ResolveIncludes(dir, *incRoot); err != nil {
+		return nil, err
+	}
+	if err := checkFields(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, selectValues(cfg)
+}
+
+// readConfig reads a config from the specified path, which may be a Go source
+// file, a JSON file (ending in .json), or a YAML file, resolves its includes,
+// and selects its enumerators. If path is "-", a YAML config is read from
+// stdin, and its includes are resolved relative to the working directory.
+func readConfig(path string) (*gen.Config, error) {
+	var cfg *gen.Config
+	var err error
+	if path == "-" {
+		cfg, err = gen.ParseConfig(os.Stdin)
+		path = "."
+	} else if strings.HasSuffix(path, ".go") {
+		cfg, err = gen.ConfigFromGoFile(path)
+	} else if strings.HasSuffix(path, ".json") {
+		cfg, err = gen.ConfigFromJSON(path)
+	} else {
+		cfg, err = gen.ConfigFromYAML(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.ResolveIncludes(path, *incRoot); err != nil {
+		return nil, err
+	}
+	if err := checkFields(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, selectValues(cfg)
+}
+
+// checkFields reports an error for the unknown fields of cfg, unless the
+// -lenient flag is set.
+func checkFields(cfg *gen.Config) error {
+	if *lenient {
+		return nil
+	}
+	return cfg.UnknownFields()
+}
+
+// selectValues selects the enumerators of cfg whose when conditions are
+// satisfied by the tags given by the -tags flag and the name of the profile
+// given by the -profile flag, if any.
+func selectValues(cfg *gen.Config) error {
+	var tags []string
+	if *whenTags != "" {
+		tags = strings.Split(*whenTags, ",")
+	}
+	if *profile != "" {
+		tags = append(tags, *profile)
 	}
-	return gen.ConfigFromYAML(*configPath)
+	return cfg.SelectValues(tags...)
 }
@@ -0,0 +1,11 @@
+package main
+
+import "testing"
+
+func TestRunFormatterEmpty(t *testing.T) {
+	defer func(old string) { *formatCmd = old }(*formatCmd)
+	*formatCmd = " \t "
+	if out, err := runFormatter([]byte("package foo\n")); err == nil {
+		t.Errorf("runFormatter: got %q, want error", out)
+	}
+}
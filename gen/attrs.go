@@ -0,0 +1,133 @@
+package gen
+
+import (
+	"fmt"
+	"go/token"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// attrTypes maps the Go types supported for enumerator attributes to the
+// literal of their zero values.
+var attrTypes = map[string]string{
+	"bool": "false", "float64": "0", "int": "0", "int64": "0", "string": `""`,
+}
+
+// attrReserved lists method names generated for every enumeration, which
+// attribute accessors must not use.
+var attrReserved = []string{"Code", "Display", "Enum", "Index", "String", "Valid"}
+
+// AttrData is the data model for an enumerator attribute.
+type AttrData struct {
+	Name   string   // the attribute name, as configured
+	Method string   // the name of the accessor method
+	Type   string   // the Go type of the attribute
+	Field  string   // the name of the attribute table (in the grouped variable)
+	Table  string   // an expression denoting the attribute table
+	Values []string // Go literals for the attribute of each enumerator, by ordinal
+}
+
+// attrMethod returns the name of the accessor method for the attribute name.
+func attrMethod(name string) string { return mapFirst(name, unicode.ToUpper) }
+
+// attrData constructs the template data model for the attributes of e, given
+// its zero enumerator and the rest of its enumerators.
+func (e *Enum) attrData(zero *Value, rest []*Value) []*AttrData {
+	var out []*AttrData
+	for _, name := range slices.Sorted(maps.Keys(e.Attrs)) {
+		ad := &AttrData{
+			Name:   name,
+			Method: attrMethod(name),
+			Type:   e.Attrs[name],
+			Field:  "attr_" + name,
+			Table:  e.tableName("attr_" + name),
+		}
+		for _, v := range append([]*Value{zero}, rest...) {
+			var val any
+			if v != nil {
+				val = v.Attrs[name]
+			}
+			lit, _ := attrLiteral(ad.Type, val) // validated
+			ad.Values = append(ad.Values, lit)
+		}
+		out = append(out, ad)
+	}
+	return out
+}
+
+// attrLiteral returns a Go literal for the value v of an attribute of the
+// specified type. A nil value denotes the zero value of the type.
+func attrLiteral(typ string, v any) (string, error) {
+	zero, ok := attrTypes[typ]
+	if !ok {
+		return "", fmt.Errorf("unsupported attribute type %q", typ)
+	} else if v == nil {
+		return zero, nil
+	}
+	switch typ {
+	case "string":
+		if s, ok := v.(string); ok {
+			return strconv.Quote(s), nil
+		}
+	case "bool":
+		if b, ok := v.(bool); ok {
+			return strconv.FormatBool(b), nil
+		}
+	case "int", "int64":
+		switch n := v.(type) {
+		case int:
+			return strconv.Itoa(n), nil
+		case int64:
+			return strconv.FormatInt(n, 10), nil
+		case float64:
+			if n == math.Trunc(n) && math.Abs(n) < 1<<53 {
+				return strconv.FormatFloat(n, 'f', -1, 64), nil
+			}
+		}
+	case "float64":
+		switch n := v.(type) {
+		case int:
+			return strconv.Itoa(n), nil
+		case int64:
+			return strconv.FormatInt(n, 10), nil
+		case float64:
+			if !math.IsInf(n, 0) && !math.IsNaN(n) {
+				return strconv.FormatFloat(n, 'g', -1, 64), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("invalid %s value %v", typ, v)
+}
+
+// validateAttrs reports the problems with the attribute settings of e and
+// its enumerators.
+func validateAttrs(e *Enum, report func(ValidationError, string, ...any), at func(string) ValidationError) {
+	for _, name := range slices.Sorted(maps.Keys(e.Attrs)) {
+		if !token.IsIdentifier(name) || strings.HasPrefix(name, "_") {
+			report(at("attrs"), "invalid attribute name %q", name)
+		} else if m := attrMethod(name); slices.Contains(attrReserved, m) || (e.CodeConsts && m == "Value") {
+			report(at("attrs"), "attribute %q conflicts with method %s", name, m)
+		}
+		if _, ok := attrTypes[e.Attrs[name]]; !ok {
+			report(at("attrs"), "attribute %q has unsupported type %q", name, e.Attrs[name])
+		}
+	}
+	for j, v := range e.Values {
+		pos := at("attrs")
+		pos.Value = j + 1
+		for _, name := range slices.Sorted(maps.Keys(v.Attrs)) {
+			typ, ok := e.Attrs[name]
+			if !ok {
+				report(pos, "unknown attribute %q for %q", name, v.Name)
+			} else if _, ok := attrTypes[typ]; !ok {
+				continue // reported above
+			} else if _, err := attrLiteral(typ, v.Attrs[name]); err != nil {
+				report(pos, "attribute %q of %q: %v", name, v.Name, err)
+			}
+		}
+	}
+}
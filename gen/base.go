@@ -0,0 +1,79 @@
+package gen
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ExpandBases expands the base fields of the enumerations of c and of the
+// packages listed by c. The values of the base of each enumeration are
+// prepended to its own values, so that enumerations can share a common set
+// of enumerators, and the base field is cleared.
+//
+// A base names either an entry of the bases field of the config, or another
+// enumeration of the same config (whose own base is expanded first). The
+// packages listed by c may use the bases of c, unless they define a base of
+// the same name. An enumeration that extends another enumeration inherits its
+// zero enumerator, if it does not set its own.
+//
+// ResolveIncludes calls ExpandBases after resolving the includes of c, so
+// enumerations in included files may use the bases of the including config.
+func (c *Config) ExpandBases() error {
+	if err := expandBases(c, c.Bases); err != nil {
+		return err
+	}
+	for _, p := range c.Packages {
+		if err := expandBases(&p.Config, inherit(c.Bases, p.Bases)); err != nil {
+			return fmt.Errorf("package %q: %w", p.Package, err)
+		}
+	}
+	return nil
+}
+
+func expandBases(c *Config, bases map[string][]*Value) error {
+	done := make(map[*Enum]bool)
+	var expand func(e *Enum, chain []string) error
+	expand = func(e *Enum, chain []string) error {
+		if done[e] || e.Base == "" {
+			return nil
+		}
+		chain = append(chain, e.Type)
+		var vals []*Value
+		if bv, ok := bases[e.Base]; ok {
+			vals = bv
+		} else if i := slices.IndexFunc(c.Enum, func(b *Enum) bool { return b.Type == e.Base }); i >= 0 {
+			b := c.Enum[i]
+			if slices.Contains(chain, b.Type) {
+				return fmt.Errorf("enum %q: base cycle %v", e.Type, append(chain, b.Type))
+			} else if err := expand(b, chain); err != nil {
+				return err
+			} else if b.ValuesFrom != "" {
+				return fmt.Errorf("enum %q: base %q has unresolved values-from", e.Type, b.Type)
+			}
+			vals = b.Values
+			if e.Zero == "" {
+				e.Zero = b.Zero
+			}
+		} else {
+			return fmt.Errorf("enum %q: unknown base %q", e.Type, e.Base)
+		}
+
+		// Copy the base values, so that changes to the values of one
+		// enumeration (e.g., by CompactIndexes) do not affect the others.
+		ext := make([]*Value, 0, len(vals)+len(e.Values))
+		for _, v := range vals {
+			cp := *v
+			ext = append(ext, &cp)
+		}
+		e.Values = append(ext, e.Values...)
+		e.Base = ""
+		done[e] = true
+		return nil
+	}
+	for _, e := range c.Enum {
+		if err := expand(e, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
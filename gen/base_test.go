@@ -0,0 +1,115 @@
+package gen_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestExpandBases(t *testing.T) {
+	cfg, err := gen.ParseConfig(strings.NewReader(`package: status
+bases:
+  Lifecycle:
+    - name: Unknown
+    - name: Active
+      text: active
+enum:
+  - type: User
+    prefix: User
+    base: Lifecycle
+    zero: Unknown
+    values: [{name: Suspended}]
+  - type: Admin
+    prefix: Admin
+    base: User
+    values: [{name: Root}]
+  - type: Plain
+    values: [{name: X}]
+packages:
+  - package: billing
+    output: billing/enums.go
+    enum:
+      - type: Account
+        base: Lifecycle
+        values: [{name: Closed}]
+`))
+	if err != nil {
+		t.Fatalf("ParseConfig: unexpected error: %v", err)
+	}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "base is not expanded") {
+		t.Errorf("Validate: got %v, want base is not expanded", err)
+	}
+	if err := cfg.ExpandBases(); err != nil {
+		t.Fatalf("ExpandBases: unexpected error: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: unexpected error: %v", err)
+	}
+
+	names := func(e *gen.Enum) []string {
+		var out []string
+		for _, v := range e.Values {
+			out = append(out, v.Name)
+		}
+		return out
+	}
+	tests := []struct {
+		e     *gen.Enum
+		zero  string
+		names []string
+	}{
+		{cfg.Enum[0], "Unknown", []string{"Unknown", "Active", "Suspended"}},
+		{cfg.Enum[1], "Unknown", []string{"Unknown", "Active", "Suspended", "Root"}},
+		{cfg.Enum[2], "", []string{"X"}},
+		{cfg.Packages[0].Enum[0], "", []string{"Unknown", "Active", "Closed"}},
+	}
+	for _, tc := range tests {
+		if got := names(tc.e); !slices.Equal(got, tc.names) {
+			t.Errorf("Enum %q: got values %q, want %q", tc.e.Type, got, tc.names)
+		}
+		if tc.e.Zero != tc.zero {
+			t.Errorf("Enum %q: got zero %q, want %q", tc.e.Type, tc.e.Zero, tc.zero)
+		}
+		if tc.e.Base != "" {
+			t.Errorf("Enum %q: base %q was not cleared", tc.e.Type, tc.e.Base)
+		}
+	}
+
+	// The expanded values are copies, not shared with the base.
+	cfg.Enum[0].Values[1].Text = "changed"
+	if got := cfg.Enum[1].Values[1].Text; got != "active" {
+		t.Errorf("Admin value text: got %q, want %q", got, "active")
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			config, want string
+		}{
+			{`package: x
+enum:
+  - type: A
+    base: Nonesuch
+    values: [{name: X}]
+`, `enum "A": unknown base "Nonesuch"`},
+			{`package: x
+enum:
+  - type: A
+    base: B
+    values: [{name: X}]
+  - type: B
+    base: A
+    values: [{name: Y}]
+`, `enum "B": base cycle [A B A]`},
+		} {
+			cfg, err := gen.ParseConfig(strings.NewReader(tc.config))
+			if err != nil {
+				t.Fatalf("ParseConfig: unexpected error: %v", err)
+			}
+			if err := cfg.ExpandBases(); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("ExpandBases: got %v, want %q", err, tc.want)
+			}
+		}
+	})
+}
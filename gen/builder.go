@@ -0,0 +1,59 @@
+package gen
+
+import (
+	"io"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// NewConfig returns a new config for the specified package, containing the
+// given enumerations. Together with NewEnum, this allows other programs to
+// compose configs without writing YAML, for example:
+//
+//	cfg := gen.NewConfig("colors",
+//	   gen.NewEnum("Color").
+//	      Value("Red", gen.Text("fire-engine-red")).
+//	      Value("Green", gen.Doc("The colour of grass.")),
+//	)
+func NewConfig(pkg string, enums ...*Enum) *Config {
+	return &Config{Package: pkg, Enum: enums}
+}
+
+// NewEnum returns a new enumeration with the given type name and no values.
+// Use the Value method to add enumerators. Other options may be set directly
+// on the fields of the result.
+func NewEnum(typeName string) *Enum { return &Enum{Type: typeName} }
+
+// Value adds an enumerator with the given name and options to the values of
+// e, and returns e to permit chaining.
+func (e *Enum) Value(name string, opts ...ValueOption) *Enum {
+	v := &Value{Name: name}
+	for _, opt := range opts {
+		opt(v)
+	}
+	e.Values = append(e.Values, v)
+	return e
+}
+
+// A ValueOption sets an option of an enumerator added by the Value method.
+type ValueOption func(*Value)
+
+// Text sets the string representation of an enumerator.
+func Text(text string) ValueOption { return func(v *Value) { v.Text = text } }
+
+// Doc sets the documentation text of an enumerator.
+func Doc(doc string) ValueOption { return func(v *Value) { v.Doc = doc } }
+
+// Index sets the integer index of an enumerator.
+func Index(index int) ValueOption { return func(v *Value) { v.Index = &index } }
+
+// WriteYAML writes c to w in YAML format, suitable for checking in as a config
+// file. The output can be read back with ParseConfig.
+func (c *Config) WriteYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	return enc.Close()
+}
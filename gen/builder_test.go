@@ -0,0 +1,57 @@
+package gen_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestBuilder(t *testing.T) {
+	color := gen.NewEnum("Color").
+		Value("Unknown").
+		Value("Red", gen.Text("fire-engine-red"), gen.Doc("The colour of fire engines.")).
+		Value("Blue", gen.Index(5))
+	color.Zero = "Unknown"
+	cfg := gen.NewConfig("colors", color, gen.NewEnum("Size").Value("Small"))
+
+	var buf bytes.Buffer
+	if err := cfg.WriteYAML(&buf); err != nil {
+		t.Fatalf("WriteYAML: %v", err)
+	}
+	const want = `package: colors
+enum:
+  - type: Color
+    values:
+      - name: Unknown
+      - name: Red
+        doc: The colour of fire engines.
+        text: fire-engine-red
+      - name: Blue
+        index: 5
+    zero: Unknown
+  - type: Size
+    values:
+      - name: Small
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteYAML: got\n%s\nwant\n%s", got, want)
+	}
+
+	// The YAML should round-trip to an equivalent config.
+	rt, err := gen.ParseConfig(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+	var got, orig bytes.Buffer
+	if err := rt.Generate(&got); err != nil {
+		t.Fatalf("Generate round-trip: %v", err)
+	}
+	if err := cfg.Generate(&orig); err != nil {
+		t.Fatalf("Generate original: %v", err)
+	}
+	if got.String() != orig.String() {
+		t.Errorf("Round-trip output differs: got\n%s\nwant\n%s", got.String(), orig.String())
+	}
+}
@@ -2,55 +2,184 @@ package gen
 
 import (
 	"bytes"
+	"cmp"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
 
-	"github.com/creachadair/mds/mapset"
 	yaml "gopkg.in/yaml.v3"
 )
 
+// ErrNoConfig is reported by LoadPackage and LoadPackageDir if the package
+// directory does not contain any Go source files or marked YAML files with
+// enumeration configs.
+var ErrNoConfig = errors.New("no matching .go files found")
+
 // LoadPackage reads and parses a combined YAML configuration from the Go files
 // stored in the current working directory.
 //
 // An error is reported if the current working directory does not contain any
 // Go source files with enumeration configurations in them, or if the files
 // match multiple package names.
-func LoadPackage() (*Config, error) {
-	des, err := os.ReadDir(".")
+func LoadPackage() (*Config, error) { return LoadPackageDir(".") }
+
+// LoadPackageDir reads and parses a combined YAML configuration from the Go
+// files stored in the specified directory, as LoadPackage does for the current
+// working directory. If dir does not contain any Go source files with
+// enumeration configurations, the error wraps ErrNoConfig.
+//
+// YAML files (*.yml and *.yaml) in dir whose configs set the Merge field are
+// also combined, so that a package may define some of its enumerations in
+// YAML and others in Go comments. The files are combined in lexical order,
+// and must all have the same package name. At most one file, via an
+// enumgen:package comment, may define settings other than enumerations and
+// includes. The includes of the YAML files are not resolved. If the combined
+// config sets StructTags, the enumerations defined by the struct tags of all
+// the Go files are added after the others.
+func LoadPackageDir(dir string) (*Config, error) { return loadPackageDir(localFS, dir) }
+
+// LoadPackageFS reads and parses a combined YAML configuration from the files
+// in the directory dir of fsys, as LoadPackageDir does for a directory of the
+// local file system. The dir is a slash-separated path, as for the [fs.FS]
+// interface, and "." denotes the root of fsys.
+func LoadPackageFS(fsys fs.FS, dir string) (*Config, error) {
+	return loadPackageDir(fileSys{fsys: fsys}, dir)
+}
+
+func loadPackageDir(fsys fileSys, dir string) (*Config, error) {
+	des, err := fsys.readDir(dir)
 	if err != nil {
 		return nil, err
 	}
 	var cfg *Config
+	var settingsFrom string // the file whose config provides the settings
 	for _, de := range des {
-		if filepath.Ext(de.Name()) != ".go" || strings.HasSuffix(de.Name(), "_test.go") {
+		var c *Config
+		switch ext := filepath.Ext(de.Name()); {
+		case ext == ".go" && !strings.HasSuffix(de.Name(), "_test.go"):
+			c, err = readGoFile(fsys, fsys.join(dir, de.Name()))
+		case ext == ".yml" || ext == ".yaml":
+			c, err = mergeConfigFromYAML(fsys, fsys.join(dir, de.Name()))
+		default:
 			continue
 		}
-		c, err := ConfigFromGoFile(de.Name())
 		if errors.Is(err, errNoComment) {
 			continue // OK, skip this file
 		} else if err != nil {
 			return nil, err
 		}
+		if c.hasSettings() {
+			if settingsFrom != "" {
+				return nil, fmt.Errorf("file %q has settings, but they are already defined by %q", de.Name(), settingsFrom)
+			}
+			settingsFrom = de.Name()
+		}
 		if cfg == nil {
 			cfg = c
 			continue
 		} else if cfg.Package != c.Package {
 			return nil, fmt.Errorf("file %q has package %q, want %q", de.Name(), c.Package, cfg.Package)
 		}
+		tagged, err := appendTagged(cfg.tagged, c.tagged...)
+		if err != nil {
+			return nil, err
+		}
+		if settingsFrom == de.Name() {
+			// Keep the settings of c, with the enumerations of both.
+			c.Enum = append(cfg.Enum, c.Enum...)
+			c.Include = append(cfg.Include, c.Include...)
+			c.unknown = append(cfg.unknown, c.unknown...)
+			c.tagged = tagged
+			cfg = c
+			continue
+		}
 		cfg.Enum = append(cfg.Enum, c.Enum...)
+		cfg.Include = append(cfg.Include, c.Include...)
+		cfg.unknown = append(cfg.unknown, c.unknown...)
+		cfg.tagged = tagged
+	}
+	if cfg != nil {
+		cfg.useStructTags()
 	}
 	if cfg == nil || len(cfg.Enum) == 0 {
-		return nil, errors.New("no matching .go files found")
+		return nil, fmt.Errorf("%s: %w", dir, ErrNoConfig)
 	}
 	return cfg, nil
 }
 
+// FindPackages returns the directories in the tree rooted at root that contain
+// Go source files with enumeration configs, in lexical order.  Directories
+// named "testdata" or "vendor", directories whose names begin with "." or "_",
+// and nested modules are skipped, as the go tool does.
+func FindPackages(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if !d.IsDir() {
+			return nil
+		}
+		if path != root {
+			name := d.Name()
+			if name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir // a nested module
+			}
+		}
+		if _, err := LoadPackageDir(path); err == nil {
+			dirs = append(dirs, path)
+		} else if !errors.Is(err, ErrNoConfig) {
+			return err
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+// mergeConfigFromYAML reads the YAML file specified by path, and returns its
+// config if it is marked to be merged by LoadPackageDir. If the file is not a
+// marked config, it reports errNoComment.
+func mergeConfigFromYAML(fsys fileSys, path string) (*Config, error) {
+	data, err := fsys.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var mark struct {
+		Merge bool `yaml:"merge"`
+	}
+	if yaml.Unmarshal(data, &mark) != nil || !mark.Merge {
+		return nil, errNoComment // not a config to merge
+	}
+	c, err := ParseConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	c.setSource(path)
+	if c.hasSettings() {
+		return nil, fmt.Errorf("%s: a merged config may set only package, enum, and include", path)
+	}
+	if c.Package == "" {
+		return nil, fmt.Errorf("%s: package name not defined", path)
+	}
+	return c, nil
+}
+
 // ConfigFromYAML reads and parses the YAML config file specified by path.
 func ConfigFromYAML(path string) (*Config, error) {
 	f, err := os.Open(path)
@@ -58,25 +187,134 @@ func ConfigFromYAML(path string) (*Config, error) {
 		return nil, err
 	}
 	defer f.Close()
-	return ParseConfig(f)
+	c, err := ParseConfig(f)
+	if err != nil {
+		return nil, err
+	}
+	c.setSource(path)
+	return c, nil
+}
+
+// ConfigFromJSON reads and parses the JSON config file specified by path.
+// The keys of a JSON config are the same as those of a YAML config, as given
+// by the json tags of the Config type. As for YAML, unknown keys are recorded
+// rather than reported, and can be checked with the UnknownFields method.
+func ConfigFromJSON(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := parseJSONConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	c.setSource(path)
+	return c, nil
+}
+
+// parseJSONConfig parses a JSON configuration text. Since JSON is a subset of
+// YAML, a valid JSON text is decoded as YAML, so that unknown keys and the
+// positions of enumerations are recorded in the same way.
+func parseJSONConfig(data []byte) (*Config, error) {
+	if err := json.Unmarshal(data, new(any)); err != nil {
+		var serr *json.SyntaxError
+		if errors.As(err, &serr) {
+			line := 1 + bytes.Count(data[:serr.Offset], []byte("\n"))
+			return nil, fmt.Errorf("line %d: invalid JSON: %w", line, err)
+		}
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return ParseConfig(bytes.NewReader(data))
 }
 
 // ConfigFromGoFile reads and parses the Go file specified by path, and
 // extracts a YAML config from each first comment block tagged enumgen:type
 // found in the file.  An error results if no such comment is found.
-func ConfigFromGoFile(path string) (*Config, error) {
-	src, err := os.ReadFile(path)
+func ConfigFromGoFile(path string) (*Config, error) { return configFromGoFile(localFS, path) }
+
+func configFromGoFile(fsys fileSys, path string) (*Config, error) {
+	c, err := readGoFile(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+	c.useStructTags()
+	return c, nil
+}
+
+// readGoFile reads and parses the Go file specified by path, without adding
+// the enumerations defined by its struct tags.
+func readGoFile(fsys fileSys, path string) (*Config, error) {
+	src, err := fsys.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseGoSource(path, src)
+}
+
+// ConfigFromFS reads and parses the config file specified by path in fsys,
+// which is a slash-separated path, as for the [fs.FS] interface. If path ends
+// in ".go", the config is read from the comments of a Go source file, as
+// ConfigFromGoFile does; if it ends in ".json", the file must be JSON, as for
+// ConfigFromJSON; otherwise the file must be YAML. To resolve the includes of
+// the config from fsys, use ResolveIncludesFS.
+func ConfigFromFS(fsys fs.FS, path string) (*Config, error) {
+	if strings.HasSuffix(path, ".go") {
+		return configFromGoFile(fileSys{fsys: fsys}, path)
+	}
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+	var c *Config
+	if strings.HasSuffix(path, ".json") {
+		c, err = parseJSONConfig(data)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", path, err)
+		}
+	} else {
+		c, err = ParseConfig(bytes.NewReader(data))
+	}
 	if err != nil {
 		return nil, err
 	}
-	return ConfigFromSource(path, src)
+	c.setSource(path)
+	return c, nil
+}
+
+// hasSettings reports whether c sets anything other than its package name,
+// enumerations, includes, and merge flag.
+func (c *Config) hasSettings() bool {
+	rest := *c
+	rest.Package, rest.Enum, rest.Include, rest.Merge, rest.unknown, rest.tagged = "", nil, nil, false, nil, nil
+	return !reflect.ValueOf(rest).IsZero()
 }
 
 var errNoComment = errors.New("no config comment")
 
 // ConfigFromSource parses a config from the text of a Go source file.
-// The path is used to for diagnostics.
+// The path is used to for diagnostics. Errors parsing the config, unknown
+// fields, and validation errors report the positions of the problems in
+// the comments of the source file.
+//
+// Each comment group tagged enumgen:type defines a single enumeration. A
+// file may also contain one comment group tagged enumgen:package, whose
+// content is a complete config that may define several enumerations and
+// any other settings. The enumerations are listed in the order of their
+// comments in the source. If the config sets StructTags, the enumerations
+// defined by the struct tags of the file follow the others.
 func ConfigFromSource(path string, text []byte) (*Config, error) {
+	c, err := parseGoSource(path, text)
+	if err != nil {
+		return nil, err
+	}
+	c.useStructTags()
+	return c, nil
+}
+
+// parseGoSource parses a config from the text of a Go source file, as
+// ConfigFromSource does, but records the enumerations defined by its struct
+// tags in the config without adding them.
+func parseGoSource(path string, text []byte) (*Config, error) {
 	const flags = parser.ParseComments | parser.SkipObjectResolution
 	fset := token.NewFileSet()
 	f, err := parser.ParseFile(fset, path, text, flags)
@@ -84,116 +322,369 @@ func ConfigFromSource(path string, text []byte) (*Config, error) {
 		return nil, err
 	}
 
+	// Each line of text in a block records the line of the source file it
+	// was taken from, and the offset of its columns from those of the source.
 	type enumBlock struct {
 		name string
+		pos  token.Position
 		text []string
+		src  []srcLine
+	}
+	addText := func(b *enumBlock, text string, line, col int) {
+		b.text = append(b.text, text)
+		for range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+			b.src = append(b.src, srcLine{line, col})
+			line, col = line+1, 1
+		}
 	}
 	var enumBlocks []enumBlock
+	var pkgBlock *enumBlock
 	for _, cg := range f.Comments {
 		first := cg.List[0] // guaranteed to exist
+		pos := fset.Position(first.Pos())
 
+		var cur *enumBlock
 		if rest, ok := strings.CutPrefix(first.Text, "/*enumgen:type"); ok {
 			// Found a tagged comment group beginning with a block comment.
 			name, rest, _ := strings.Cut(rest, "\n")
-			enumBlocks = append(enumBlocks, enumBlock{
-				name: strings.TrimSpace(name),
-				text: []string{cleanMulti(rest)},
-			})
+			enumBlocks = append(enumBlocks, enumBlock{name: strings.TrimSpace(name), pos: pos})
+			cur = &enumBlocks[len(enumBlocks)-1]
+			line, col := textStart(rest, pos.Line+1, 1)
+			addText(cur, cleanMulti(rest), line, col)
 		} else if rest, ok := strings.CutPrefix(first.Text, "//enumgen:type"); ok {
 			enumBlocks = append(enumBlocks, enumBlock{
 				name: strings.TrimSpace(rest), // must be validated later
+				pos:  pos,
 			})
+			cur = &enumBlocks[len(enumBlocks)-1]
 			// lines are filled below
+		} else if rest, ok := cutPackageTag(first.Text); ok {
+			if pkgBlock != nil {
+				return nil, fmt.Errorf("%s: multiple enumgen:package comments (first at line %d)", pos, pkgBlock.pos.Line)
+			}
+			tag, rest, _ := strings.Cut(rest, "\n")
+			if strings.TrimSpace(tag) != "" {
+				return nil, fmt.Errorf("%s: unexpected text %q after enumgen:package", pos, strings.TrimSpace(tag))
+			}
+			pkgBlock = &enumBlock{pos: pos}
+			cur = pkgBlock
+			if strings.HasPrefix(first.Text, "/*") {
+				line, col := textStart(rest, pos.Line+1, 1)
+				addText(cur, cleanMulti(rest), line, col)
+			}
 		} else {
 			continue // not a relevant comment block
 		}
 
 		// Run through the rest of the group accumulating comments.
-		// Reaching this point, the latest block already has the name extracted.
-		cur := &enumBlocks[len(enumBlocks)-1]
+		// Reaching this point, the current block already has the name extracted.
 		for _, com := range cg.List[1:] {
+			cpos := fset.Position(com.Pos())
 			if rest, ok := strings.CutPrefix(com.Text, "//"); ok {
-				cur.text = append(cur.text, cleanSingle(rest))
+				col := cpos.Column + 2
+				if strings.HasPrefix(rest, " ") {
+					col++
+				}
+				addText(cur, cleanSingle(rest), cpos.Line, col)
 			} else if rest, ok := strings.CutPrefix(com.Text, "/*"); ok {
-				cur.text = append(cur.text, cleanMulti(rest))
+				line, col := textStart(rest, cpos.Line, cpos.Column+2)
+				addText(cur, cleanMulti(rest), line, col)
 			}
 		}
 	}
+	tagged, err := structTagEnums(fset, f)
+	if err != nil {
+		return nil, err
+	}
+	if len(enumBlocks) == 0 && pkgBlock == nil && len(tagged) == 0 {
+		return nil, fmt.Errorf("%w found in %q", errNoComment, path)
+	}
+
+	// Synthesize a YAML config from the blocks, recording the source of each
+	// line. The text of each block is indented two columns.
 	var buf bytes.Buffer
+	var lines []srcLine
 	fmt.Fprintf(&buf, "package: %s\nenum:\n", f.Name.Name)
+	lines = append(lines, srcLine{}, srcLine{})
 	for _, enum := range enumBlocks {
 		fmt.Fprintf(&buf, "- type: %s\n", enum.name)
 		fmt.Fprintln(&buf, indentLines("  ", enum.text))
+		lines = append(lines, srcLine{enum.pos.Line, enum.pos.Column})
+		for _, s := range enum.src {
+			lines = append(lines, srcLine{s.line, s.col - 2})
+		}
 	}
-	if buf.Len() == 0 {
-		return nil, fmt.Errorf("%w found in %q", errNoComment, path)
+	c, err := parseSource(path, &buf, lines)
+	if err != nil {
+		return nil, err
+	}
+	for i, e := range c.Enum {
+		e.pos = enumBlocks[i].pos
 	}
-	return ParseConfig(&buf)
+
+	// The config of the package comment, if any, provides the settings, and
+	// the enumerations of both are combined in source order.
+	if pkgBlock != nil {
+		pc, err := parseSource(path, strings.NewReader(indentLines("", pkgBlock.text)), pkgBlock.src)
+		if err != nil {
+			return nil, err
+		}
+		if pc.Package == "" {
+			pc.Package = f.Name.Name
+		} else if pc.Package != f.Name.Name {
+			return nil, fmt.Errorf("%s: enumgen:package comment has package %q, want %q",
+				pkgBlock.pos, pc.Package, f.Name.Name)
+		}
+		pc.Enum = append(pc.Enum, c.Enum...)
+		pc.unknown = append(pc.unknown, c.unknown...)
+		slices.SortStableFunc(pc.Enum, func(a, b *Enum) int { return cmp.Compare(a.pos.Line, b.pos.Line) })
+		c = pc
+	}
+	c.tagged = tagged
+	c.setSource(path)
+	return c, nil
 }
 
+// structTagEnums returns the enumerations defined by the fields of the struct
+// types declared in f that have tags of the form enum:"a,b,c", in source
+// order. The type of each tagged field names its enumeration, possibly as the
+// element of a pointer, slice, or array type, and the tag lists the strings of
+// its enumerators. Fields of the same type must list the same strings.
+func structTagEnums(fset *token.FileSet, f *ast.File) ([]*Enum, error) {
+	var out []*Enum
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			for _, fld := range st.Fields.List {
+				if fld.Tag == nil {
+					continue
+				}
+				tag, _ := strconv.Unquote(fld.Tag.Value) // the parser checked the syntax
+				list, ok := reflect.StructTag(tag).Lookup("enum")
+				if !ok {
+					continue
+				}
+				pos := fset.Position(fld.Tag.Pos())
+				name := tagFieldType(fld.Type)
+				if name == "" {
+					return nil, fmt.Errorf("%s: enum tag on a field of type %s, which is not a defined type name",
+						pos, types.ExprString(fld.Type))
+				}
+				field := name // an embedded field
+				if len(fld.Names) != 0 {
+					field = fld.Names[0].Name
+				}
+				e := &Enum{
+					Type:   name,
+					Prefix: name,
+					Doc:    fmt.Sprintf("{name} is the type of the %s.%s field.", ts.Name.Name, field),
+					pos:    pos,
+				}
+				for _, text := range strings.Split(list, ",") {
+					text = strings.TrimSpace(text)
+					e.Values = append(e.Values, &Value{Name: tagValueName(text), Text: text})
+				}
+				var err error
+				if out, err = appendTagged(out, e); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+// tagFieldType returns the name of the enumeration denoted by the type of a
+// tagged struct field, or "" if it does not denote a type to be defined.
+func tagFieldType(x ast.Expr) string {
+	switch t := x.(type) {
+	case *ast.Ident:
+		if types.Universe.Lookup(t.Name) != nil {
+			return "" // a predeclared type
+		}
+		return t.Name
+	case *ast.StarExpr:
+		return tagFieldType(t.X)
+	case *ast.ArrayType:
+		return tagFieldType(t.Elt)
+	}
+	return ""
+}
+
+// tagValueName returns the base name of the enumerator for the string text
+// listed in a struct tag, by capitalizing each run of letters and digits in
+// text and joining them, so that "dry-run" gives "DryRun".
+func tagValueName(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = mapFirst(w, unicode.ToUpper)
+	}
+	return strings.Join(words, "")
+}
+
+// appendTagged appends to tagged the enumerations of more that are not
+// already present. It reports an error if an enumeration of more has the same
+// type as one already present, but lists different strings.
+func appendTagged(tagged []*Enum, more ...*Enum) ([]*Enum, error) {
+	texts := func(e *Enum) []string {
+		out := make([]string, len(e.Values))
+		for i, v := range e.Values {
+			out[i] = v.Text
+		}
+		return out
+	}
+nextEnum:
+	for _, e := range more {
+		for _, old := range tagged {
+			if old.Type != e.Type {
+				continue
+			} else if !slices.Equal(texts(old), texts(e)) {
+				return nil, fmt.Errorf("%s: enum tag for type %q lists %q, but the tag at %s lists %q",
+					e.pos, e.Type, texts(e), old.pos, texts(old))
+			}
+			continue nextEnum
+		}
+		tagged = append(tagged, e)
+	}
+	return tagged, nil
+}
+
+// useStructTags adds the enumerations defined by struct tags to c, if
+// c.StructTags is set. In either case, it discards the tagged enumerations.
+func (c *Config) useStructTags() {
+	if c.StructTags {
+		c.Enum = append(c.Enum, c.tagged...)
+	}
+	c.tagged = nil
+}
+
+// cutPackageTag reports whether text is a comment beginning with the
+// enumgen:package tag, and if so returns the text following the tag.
+func cutPackageTag(text string) (string, bool) {
+	if rest, ok := strings.CutPrefix(text, "/*enumgen:package"); ok {
+		return rest, true
+	}
+	return strings.CutPrefix(text, "//enumgen:package")
+}
+
+// parseSource parses a config synthesized from the comments of the Go source
+// file at path, and maps the positions of its errors, its unknown fields, and
+// its enumerations to their positions in the source, given the source of each
+// line of the config.
+func parseSource(path string, r io.Reader, lines []srcLine) (*Config, error) {
+	c, err := ParseConfig(r)
+	if err != nil {
+		return nil, &sourceError{msg: path + ": " + mapErrorLines(err.Error(), lines), err: err}
+	}
+	for _, e := range c.unknown {
+		if i := e.Line - 1; i >= 0 && i < len(lines) {
+			e.Line, e.Column = lines[i].line, e.Column+lines[i].col-1
+		}
+	}
+	for _, e := range c.Enum {
+		if i := e.pos.Line - 1; i >= 0 && i < len(lines) {
+			e.pos.Line, e.pos.Column = lines[i].line, e.pos.Column+lines[i].col-1
+		}
+	}
+	return c, nil
+}
+
+// A srcLine records the line of a source file from which a line of a config
+// was taken, and the column of the source at which the line begins.
+type srcLine struct{ line, col int }
+
+// textStart returns the line and column of the first non-space character of
+// text, which begins at the given line and column of a source file.
+func textStart(text string, line, col int) (int, int) {
+	for _, r := range text {
+		switch {
+		case r == '\n':
+			line, col = line+1, 1
+		case unicode.IsSpace(r):
+			col++
+		default:
+			return line, col
+		}
+	}
+	return line, col
+}
+
+// lineRef matches a line number in the message of a YAML error.
+var lineRef = regexp.MustCompile(`\bline (\d+)\b`)
+
+// mapErrorLines returns msg with each line number of the synthesized config
+// replaced by the corresponding line number of the source file.
+func mapErrorLines(msg string, lines []srcLine) string {
+	return lineRef.ReplaceAllStringFunc(msg, func(s string) string {
+		n, _ := strconv.Atoi(strings.TrimPrefix(s, "line "))
+		if n < 1 || n > len(lines) || lines[n-1].line == 0 {
+			return s
+		}
+		return fmt.Sprintf("line %d", lines[n-1].line)
+	})
+}
+
+// A sourceError is an error whose message has been rewritten to refer to the
+// positions of a source file. It wraps the original error.
+type sourceError struct {
+	msg string
+	err error
+}
+
+func (e *sourceError) Error() string { return e.msg }
+func (e *sourceError) Unwrap() error { return e.err }
+
 // ParseConfig parses a YAML configuration text from r.
+//
+// Keys of the config that do not match any setting are ignored, but they are
+// recorded and can be checked with the UnknownFields method.
 func ParseConfig(r io.Reader) (*Config, error) {
 	dec := yaml.NewDecoder(r)
+	var node yaml.Node
+	if err := dec.Decode(&node); err != nil {
+		return nil, err
+	}
 	var cfg Config
-	if err := dec.Decode(&cfg); err != nil {
+	if err := node.Decode(&cfg); err != nil {
 		return nil, err
 	}
+	cfg.unknown = unknownFields(&node, reflect.TypeOf(cfg))
+	setEnumPositions(&node, &cfg)
 	return &cfg, nil
 }
 
-func (c *Config) checkValid() error {
-	if c.Package == "" {
-		return errors.New("package name not defined")
+// setEnumPositions records the positions of the enumerations of cfg, and of
+// the packages it lists, from the YAML document node it was decoded from.
+func setEnumPositions(node *yaml.Node, cfg *Config) {
+	if node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+		node = node.Content[0]
 	}
-	if len(c.Enum) == 0 {
-		return errors.New("no enumerations defined")
+	if node.Kind != yaml.MappingNode {
+		return
 	}
-	enumSeen := mapset.New[string]()
-	valueSeen := make(map[string]string)
-	for i, e := range c.Enum {
-		if e.Type == "" {
-			return fmt.Errorf("enum %d: type name not defined", i+1)
-		} else if enumSeen.Has(e.Type) {
-			return fmt.Errorf("enum %d: duplicate type name %q", i+1, e.Type)
-		}
-		enumSeen.Add(e.Type)
-		if len(e.Values) == 0 {
-			return fmt.Errorf("enum %d: no enumerators defined", i+1)
-		}
-		if zero := e.Prefix + e.Zero; e.Zero != "" {
-			if valueSeen[zero] != "" && valueSeen[zero] != e.Type {
-				return fmt.Errorf("enum %q default %q duplicated in %q",
-					e.Type, zero, valueSeen[zero])
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, val := node.Content[i].Value, node.Content[i+1]
+		switch {
+		case key == "enum" && val.Kind == yaml.SequenceNode && len(val.Content) == len(cfg.Enum):
+			for j, e := range cfg.Enum {
+				e.pos = token.Position{Line: val.Content[j].Line, Column: val.Content[j].Column}
 			}
-			valueSeen[zero] = e.Type
-		}
-
-		// It is OK for the zero enumerator to be duplicated in its own value
-		// list, but other names must not be duplicated within the list. This map
-		// keeps track of just the names in this group to prevent that.
-
-		var thisName mapset.Set[string]
-		for j, v := range e.Values {
-			if v.Name == "" {
-				return fmt.Errorf("enum %q value %d: name not defined", e.Type, j+1)
-			} else if thisName.Has(v.Name) {
-				return fmt.Errorf("enum %q value %d: name %q duplicated in %q", e.Type, j+1, v.Name, e.Type)
-			}
-			thisName.Add(v.Name)
-
-			full := e.Prefix + v.Name
-			if valueSeen[full] != "" {
-				// If this enumerator is "my" zero value, it's OK to repeat it in
-				// the values list to provide text and documentation.
-				if valueSeen[full] != e.Type || e.Zero == "" || e.Zero != v.Name {
-					return fmt.Errorf("enum %q value %d: name %q duplicated in %q",
-						e.Type, j+1, full, valueSeen[full])
-				}
+		case key == "packages" && val.Kind == yaml.SequenceNode && len(val.Content) == len(cfg.Packages):
+			for j, p := range cfg.Packages {
+				setEnumPositions(val.Content[j], &p.Config)
 			}
-			valueSeen[full] = e.Type
 		}
 	}
-	return nil
 }
 
 func indentLines(pfx string, text []string) string {
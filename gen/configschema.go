@@ -0,0 +1,237 @@
+package gen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ConfigSchema returns a JSON Schema (draft 2020-12) describing the structure
+// of a config: the keys of each setting and the types of their values. Editors
+// and other tools can use it to check configs as they are written, in YAML or
+// JSON. The schema does not express the other constraints checked by
+// Validate, such as the names of enumerators or conflicts between options.
+//
+// The schema is derived from the yaml tags of the Config type, so it always
+// matches the settings understood by this version of the generator.
+func ConfigSchema() []byte { return slices.Clone(configSchema()) }
+
+var configSchema = sync.OnceValue(func() []byte {
+	defs := make(map[string]any)
+	root := typeSchema(reflect.TypeFor[Config](), defs)
+	out, err := json.MarshalIndent(map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   "enumgen config",
+		"$ref":    root["$ref"],
+		"$defs":   defs,
+	}, "", "  ")
+	if err != nil {
+		panic(fmt.Sprintf("encoding config schema: %v", err)) // should not be possible
+	}
+	return append(out, '\n')
+})
+
+// typeSchema returns the schema for values of type t, adding the definitions
+// of the struct types it refers to to defs, keyed by type name.
+func typeSchema(t reflect.Type, defs map[string]any) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), defs)}
+	case reflect.Map:
+		s := map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem(), defs)}
+		if t.Key().Kind() != reflect.String {
+			s["propertyNames"] = map[string]any{"pattern": "^-?[0-9]+$"}
+		}
+		return s
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := defs[name]; !ok {
+			defs[name] = nil // mark in progress, for recursive types
+			props := make(map[string]any)
+			for key, ft := range yamlFields(t) {
+				props[key] = typeSchema(ft, defs)
+			}
+			defs[name] = map[string]any{
+				"type":                 "object",
+				"properties":           props,
+				"additionalProperties": false,
+			}
+		}
+		return map[string]any{"$ref": "#/$defs/" + name}
+	}
+	return map[string]any{} // any value
+}
+
+// A SchemaError reports a value of a config that does not match the schema
+// returned by ConfigSchema.
+type SchemaError struct {
+	Path   string // the path of the file containing the value, if known
+	Line   int    // the line number of the value, 1-based
+	Column int    // the column number of the value, 1-based
+	Key    string // the location of the value in the config, e.g., "enum[1].flag-value"
+	Msg    string // a description of the problem
+}
+
+// Error satisfies the error interface.
+func (e *SchemaError) Error() string {
+	pos := fmt.Sprintf("line %d, column %d", e.Line, e.Column)
+	if e.Path != "" {
+		pos = fmt.Sprintf("%s:%d:%d", e.Path, e.Line, e.Column)
+	}
+	if e.Key == "" {
+		return fmt.Sprintf("%s: %s", pos, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s: %s", pos, e.Key, e.Msg)
+}
+
+// CheckConfigSchema checks the YAML or JSON text of a config against the
+// schema returned by ConfigSchema, and reports an error for each value that
+// does not match it, or nil if they all do. The path is used for diagnostics.
+// Unless the text cannot be parsed, the error wraps a *SchemaError for each
+// problem, giving its position in the text.
+//
+// Because YAML decodes any scalar as a string where a string is expected, a
+// number or boolean is accepted for a string setting. A null value is
+// accepted for any setting, and leaves it unset.
+func CheckConfigSchema(path string, data []byte) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(configSchema(), &schema); err != nil {
+		panic(fmt.Sprintf("decoding config schema: %v", err)) // should not be possible
+	}
+	sc := &schemaChecker{path: path, defs: schema["$defs"].(map[string]any)}
+	for _, doc := range node.Content {
+		sc.check(doc, schema, "")
+	}
+	return errors.Join(sc.errs...)
+}
+
+// A schemaChecker checks the nodes of a YAML document against a schema.
+type schemaChecker struct {
+	path string
+	defs map[string]any
+	errs []error
+}
+
+func (sc *schemaChecker) report(node *yaml.Node, key, msg string, args ...any) {
+	sc.errs = append(sc.errs, &SchemaError{
+		Path:   sc.path,
+		Line:   node.Line,
+		Column: node.Column,
+		Key:    key,
+		Msg:    fmt.Sprintf(msg, args...),
+	})
+}
+
+// check reports the problems with node, located at key, for the schema s.
+func (sc *schemaChecker) check(node *yaml.Node, s map[string]any, key string) {
+	if ref, ok := s["$ref"].(string); ok {
+		s = sc.defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+	}
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return // OK, the setting is unset
+	}
+	want, _ := s["type"].(string)
+	switch want {
+	case "":
+		return // any value is OK
+	case "object":
+		if node.Kind != yaml.MappingNode {
+			sc.report(node, key, "got %s, want an object", nodeKind(node))
+			return
+		}
+		props, _ := s["properties"].(map[string]any)
+		extra, _ := s["additionalProperties"].(map[string]any)
+		pattern, _ := s["propertyNames"].(map[string]any)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			if k.Value == "<<" {
+				continue // a merge key
+			}
+			sub := k.Value
+			if key != "" {
+				sub = key + "." + k.Value
+			}
+			if props != nil {
+				ps, ok := props[k.Value].(map[string]any)
+				if !ok {
+					sc.report(k, key, "unknown key %q", k.Value)
+					continue
+				}
+				sc.check(v, ps, sub)
+				continue
+			}
+			if pattern != nil && k.Tag != "!!int" {
+				sc.report(k, key, "key %q is not an integer", k.Value)
+			}
+			if extra != nil {
+				sc.check(v, extra, sub)
+			}
+		}
+	case "array":
+		if node.Kind != yaml.SequenceNode {
+			sc.report(node, key, "got %s, want an array", nodeKind(node))
+			return
+		}
+		items, _ := s["items"].(map[string]any)
+		for i, elt := range node.Content {
+			sc.check(elt, items, fmt.Sprintf("%s[%d]", key, i))
+		}
+	default:
+		if node.Kind != yaml.ScalarNode {
+			sc.report(node, key, "got %s, want %s", nodeKind(node), want)
+			return
+		}
+		switch {
+		case want == "string":
+		case want == "boolean" && node.Tag == "!!bool":
+		case want == "integer" && node.Tag == "!!int":
+		case want == "number" && (node.Tag == "!!int" || node.Tag == "!!float"):
+		default:
+			sc.report(node, key, "got %s %q, want %s", nodeKind(node), node.Value, want)
+		}
+	}
+}
+
+// nodeKind returns a description of the kind of value denoted by node.
+func nodeKind(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "an object"
+	case yaml.SequenceNode:
+		return "an array"
+	}
+	switch node.Tag {
+	case "!!bool":
+		return "boolean"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	}
+	return "string"
+}
@@ -0,0 +1,233 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A ChangeKind identifies the kind of a Change between two configs.
+type ChangeKind int
+
+// Constants defining the kinds of changes reported by DiffConfigs.
+const (
+	EnumAdded    ChangeKind = iota + 1 // an enumeration type was added
+	EnumRemoved                        // an enumeration type was removed
+	ValueAdded                         // an enumerator was added
+	ValueRemoved                       // an enumerator was removed
+	ValueRenamed                       // an enumerator was renamed (same index)
+	IndexChanged                       // the index of an enumerator changed
+	TextChanged                        // the string of an enumerator changed
+)
+
+var kindNames = [...]string{
+	EnumAdded:    "enum added",
+	EnumRemoved:  "enum removed",
+	ValueAdded:   "added",
+	ValueRemoved: "removed",
+	ValueRenamed: "renamed",
+	IndexChanged: "index changed",
+	TextChanged:  "text changed",
+}
+
+// String returns a human-readable description of k.
+func (k ChangeKind) String() string {
+	if k <= 0 || int(k) >= len(kindNames) {
+		return fmt.Sprintf("ChangeKind(%d)", k)
+	}
+	return kindNames[k]
+}
+
+// A Change describes a single semantic difference between two configs.
+type Change struct {
+	Kind ChangeKind
+	Type string // the enumeration type name
+	Name string // the enumerator name in the new config, or "" for enum changes
+
+	// For ValueRenamed, the old name of the enumerator.
+	// For ValueRemoved, the name of the removed enumerator.
+	// For ValueAdded, the name of the old enumerator whose index was reused, or
+	// "" if the index was not previously in use.
+	OldName string
+
+	// For index changes, the old and new indices of the enumerator.
+	// For added and removed enumerators, only the relevant one is set.
+	OldIndex, NewIndex int
+
+	// For TextChanged, the old and new strings of the enumerator.
+	OldText, NewText string
+}
+
+// String returns a human-readable description of the change.
+func (c Change) String() string {
+	switch c.Kind {
+	case EnumAdded, EnumRemoved:
+		return fmt.Sprintf("%s: %v", c.Type, c.Kind)
+	case ValueAdded:
+		if c.OldName != "" {
+			return fmt.Sprintf("%s.%s: added (index %d, reused from %s)", c.Type, c.Name, c.NewIndex, c.OldName)
+		}
+		return fmt.Sprintf("%s.%s: added (index %d)", c.Type, c.Name, c.NewIndex)
+	case ValueRemoved:
+		return fmt.Sprintf("%s.%s: removed (index %d)", c.Type, c.OldName, c.OldIndex)
+	case ValueRenamed:
+		return fmt.Sprintf("%s.%s: renamed from %s (index %d)", c.Type, c.Name, c.OldName, c.NewIndex)
+	case IndexChanged:
+		return fmt.Sprintf("%s.%s: index changed from %d to %d", c.Type, c.Name, c.OldIndex, c.NewIndex)
+	case TextChanged:
+		return fmt.Sprintf("%s.%s: text changed from %q to %q", c.Type, c.Name, c.OldText, c.NewText)
+	default:
+		return fmt.Sprintf("%s.%s: %v", c.Type, c.Name, c.Kind)
+	}
+}
+
+// Breaking reports whether c is a backward-incompatible change, namely one
+// that removes or renames an enumeration or an enumerator, changes the index
+// of an existing enumerator, or reuses the index of an old enumerator for a
+// new one.
+func (c Change) Breaking() bool {
+	switch c.Kind {
+	case EnumRemoved, ValueRemoved, ValueRenamed, IndexChanged:
+		return true
+	case ValueAdded:
+		return c.OldName != ""
+	default:
+		return false
+	}
+}
+
+// A Report is a list of changes between two configs, as reported by
+// DiffConfigs.
+type Report []Change
+
+// String renders the report as text, one change per line.
+func (r Report) String() string {
+	var sb strings.Builder
+	for _, c := range r {
+		fmt.Fprintln(&sb, c.String())
+	}
+	return sb.String()
+}
+
+// Breaking returns the changes in r that are backward-incompatible, or nil if
+// there are none. See [Change.Breaking].
+func (r Report) Breaking() Report {
+	var out Report
+	for _, c := range r {
+		if c.Breaking() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// DiffConfigs reports the semantic differences between the enumerations
+// defined by the old and new configs. Enumerations are matched by type name,
+// and enumerators are matched by name. An enumerator that is removed and
+// replaced by another with the same index is reported as a rename.
+//
+// Changes are reported in order of the enumerations and enumerators in the new
+// config, followed by enumerations and enumerators that were removed.
+func DiffConfigs(old, new *Config) Report {
+	var out Report
+	oldEnums := make(map[string]*Enum)
+	for _, e := range old.Enum {
+		oldEnums[e.Type] = e
+	}
+	for _, ne := range new.Enum {
+		oe, ok := oldEnums[ne.Type]
+		if !ok {
+			out = append(out, Change{Kind: EnumAdded, Type: ne.Type})
+			continue
+		}
+		delete(oldEnums, ne.Type)
+		out = append(out, diffEnums(oe, ne)...)
+	}
+	for _, oe := range old.Enum {
+		if _, ok := oldEnums[oe.Type]; ok {
+			out = append(out, Change{Kind: EnumRemoved, Type: oe.Type})
+		}
+	}
+	return out
+}
+
+// enumValue records the effective settings of an enumerator.
+type enumValue struct {
+	name  string
+	index int
+	text  string
+}
+
+// effectiveValues returns the effective settings of the enumerators of e,
+// including the zero enumerator if it is named.
+func (e *Enum) effectiveValues() []enumValue {
+	zero, rest := e.extractZero()
+	var out []enumValue
+	if e.Zero != "" {
+		out = append(out, enumValue{name: e.Zero, index: e.indexBase() - 1, text: e.zeroLabel(zero)})
+	}
+	idx, _ := e.indices(rest)
+	for i, v := range rest {
+		out = append(out, enumValue{name: v.Name, index: idx[i], text: v.label()})
+	}
+	return out
+}
+
+func diffEnums(oe, ne *Enum) []Change {
+	var out []Change
+	oldVals := make(map[string]enumValue)
+	oldByIndex := make(map[int]enumValue)
+	for _, v := range oe.effectiveValues() {
+		oldVals[v.name] = v
+		oldByIndex[v.index] = v
+	}
+	newVals := ne.effectiveValues()
+	newNames := make(map[string]bool)
+	for _, v := range newVals {
+		newNames[v.name] = true
+	}
+
+	renamed := make(map[string]bool) // old names accounted for by renames
+	for _, nv := range newVals {
+		ov, ok := oldVals[nv.name]
+		if !ok {
+			// If an enumerator with the same index was removed, treat this as a
+			// rename rather than an addition.
+			if rv, ok := oldByIndex[nv.index]; ok && !newNames[rv.name] {
+				renamed[rv.name] = true
+				out = append(out, Change{
+					Kind: ValueRenamed, Type: ne.Type, Name: nv.name,
+					OldName: rv.name, OldIndex: rv.index, NewIndex: nv.index,
+				})
+				continue
+			}
+			var reused string
+			if rv, ok := oldByIndex[nv.index]; ok {
+				reused = rv.name
+			}
+			out = append(out, Change{
+				Kind: ValueAdded, Type: ne.Type, Name: nv.name, OldName: reused, NewIndex: nv.index,
+			})
+			continue
+		}
+		if ov.index != nv.index {
+			out = append(out, Change{
+				Kind: IndexChanged, Type: ne.Type, Name: nv.name,
+				OldIndex: ov.index, NewIndex: nv.index,
+			})
+		}
+		if ov.text != nv.text {
+			out = append(out, Change{
+				Kind: TextChanged, Type: ne.Type, Name: nv.name,
+				OldText: ov.text, NewText: nv.text,
+			})
+		}
+	}
+	for _, ov := range oe.effectiveValues() {
+		if !newNames[ov.name] && !renamed[ov.name] {
+			out = append(out, Change{
+				Kind: ValueRemoved, Type: ne.Type, OldName: ov.name, OldIndex: ov.index,
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,126 @@
+package gen_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	old, err := gen.ParseConfig(strings.NewReader(`package: foo
+enum:
+  - type: Color
+    zero: Unknown
+    values:
+      - name: Red
+      - name: Green
+      - name: Blue
+        text: azure
+      - name: Puce
+  - type: Size
+    values:
+      - name: Small
+`))
+	if err != nil {
+		t.Fatalf("Parse old config: %v", err)
+	}
+	new, err := gen.ParseConfig(strings.NewReader(`package: foo
+enum:
+  - type: Color
+    zero: Unknown
+    values:
+      - name: Crimson
+      - name: Green
+        index: 5
+      - name: Blue
+        text: sky
+      - name: Orange
+  - type: Shape
+    values:
+      - name: Round
+`))
+	if err != nil {
+		t.Fatalf("Parse new config: %v", err)
+	}
+
+	got := gen.DiffConfigs(old, new).String()
+	const want = `Color.Crimson: renamed from Red (index 1)
+Color.Green: index changed from 2 to 5
+Color.Blue: index changed from 3 to 6
+Color.Blue: text changed from "azure" to "sky"
+Color.Orange: added (index 7)
+Color.Puce: removed (index 4)
+Shape: enum added
+Size: enum removed
+`
+	if got != want {
+		t.Errorf("DiffConfigs: got\n%s\nwant\n%s", got, want)
+	}
+
+	if r := gen.DiffConfigs(old, old); len(r) != 0 {
+		t.Errorf("DiffConfigs(old, old): got %v, want empty", r)
+	}
+
+	t.Run("Breaking", func(t *testing.T) {
+		got := gen.DiffConfigs(old, new).Breaking().String()
+		const want = `Color.Crimson: renamed from Red (index 1)
+Color.Green: index changed from 2 to 5
+Color.Blue: index changed from 3 to 6
+Color.Puce: removed (index 4)
+Size: enum removed
+`
+		if got != want {
+			t.Errorf("Breaking: got\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("Compatible", func(t *testing.T) {
+		ext, err := gen.ParseConfig(strings.NewReader(`package: foo
+enum:
+  - type: Color
+    zero: Unknown
+    values:
+      - name: Red
+      - name: Green
+      - name: Blue
+        text: azure
+      - name: Puce
+      - name: Orange
+  - type: Size
+    values:
+      - name: Small
+`))
+		if err != nil {
+			t.Fatalf("Parse config: %v", err)
+		}
+		r := gen.DiffConfigs(old, ext)
+		if got := r.String(); got != "Color.Orange: added (index 5)\n" {
+			t.Errorf("DiffConfigs: got %q", got)
+		}
+		if b := r.Breaking(); b != nil {
+			t.Errorf("Breaking: got %v, want nil", b)
+		}
+	})
+
+	t.Run("IndexReused", func(t *testing.T) {
+		re, err := gen.ParseConfig(strings.NewReader(`package: foo
+enum:
+  - type: Size
+    values:
+      - name: Tiny
+      - name: Small
+`))
+		if err != nil {
+			t.Fatalf("Parse config: %v", err)
+		}
+		got := gen.DiffConfigs(old, re).Breaking().String()
+		const want = `Size.Tiny: added (index 1, reused from Small)
+Size.Small: index changed from 1 to 2
+Color: enum removed
+`
+		if got != want {
+			t.Errorf("Breaking: got\n%s\nwant\n%s", got, want)
+		}
+	})
+}
@@ -34,6 +34,7 @@ func Example() {
 	}
 	// Output:
 	// // Code generated by enumgen. DO NOT EDIT.
+	// //enumgen:provenance {"hash":"sha256:c249a23e896f1349fd32ec1964b1a7846d24c070235a709ba316c00d909d293f"}
 	//
 	// package example
 	//
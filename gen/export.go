@@ -0,0 +1,63 @@
+package gen
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// An ExportFormat identifies an output format for Export.
+type ExportFormat int
+
+// Constants defining the supported export formats.
+const (
+	// ExportCSV emits comma-separated values, as defined by RFC 4180.
+	ExportCSV ExportFormat = iota
+
+	// ExportTSV emits tab-separated values.
+	ExportTSV
+)
+
+// String returns a human-readable name for f.
+func (f ExportFormat) String() string {
+	switch f {
+	case ExportCSV:
+		return "csv"
+	case ExportTSV:
+		return "tsv"
+	default:
+		return fmt.Sprintf("ExportFormat(%d)", f)
+	}
+}
+
+// Export writes a table of the valid enumerators of each enumeration in c to
+// w, in the specified format. The first row is a header, and each following
+// row gives the type name of an enumeration and the name, index, string, and
+// doc of one of its enumerators, in order. Docs are joined into a single line.
+func (c *Config) Export(w io.Writer, format ExportFormat) error {
+	if format != ExportCSV && format != ExportTSV {
+		return fmt.Errorf("unknown export format %v", format)
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if format == ExportTSV {
+		cw.Comma = '\t'
+	}
+	cw.Write([]string{"type", "name", "index", "text", "doc"})
+	for _, e := range c.Enum {
+		_, rest := e.extractZero()
+		idx, _ := e.indices(rest)
+		for i, v := range rest {
+			cw.Write([]string{
+				e.Type, v.Name, strconv.Itoa(idx[i]), v.label(),
+				strings.Join(strings.Fields(injectName(v.Doc, e.valueName(v.Name))), " "),
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,60 @@
+package gen_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestExport(t *testing.T) {
+	idx := 5
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{{
+			Type: "Color",
+			Zero: "None",
+			Values: []*gen.Value{
+				{Name: "Red", Text: "red", Doc: "{name} is like\na rose."},
+				{Name: "None"},
+				{Name: "Blue", Text: "blue, true", Index: &idx},
+			},
+		}, {
+			Type:   "Mood",
+			Prefix: "M",
+			Values: []*gen.Value{{Name: "Happy", Doc: "Quite \"happy\"."}},
+		}},
+	}
+
+	tests := []struct {
+		format gen.ExportFormat
+		want   string
+	}{
+		{gen.ExportCSV, `type,name,index,text,doc
+Color,Red,1,red,Red is like a rose.
+Color,Blue,5,"blue, true",
+Mood,Happy,1,Happy,"Quite ""happy""."
+`},
+		{gen.ExportTSV, "type\tname\tindex\ttext\tdoc\n" +
+			"Color\tRed\t1\tred\tRed is like a rose.\n" +
+			"Color\tBlue\t5\tblue, true\t\n" +
+			"Mood\tHappy\t1\tHappy\t\"Quite \"\"happy\"\".\"\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.format.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := cfg.Export(&buf, tc.format); err != nil {
+				t.Fatalf("Export: unexpected error: %v", err)
+			}
+			if got := buf.String(); got != tc.want {
+				t.Errorf("Export: got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("BadFormat", func(t *testing.T) {
+		if err := cfg.Export(new(bytes.Buffer), gen.ExportFormat(99)); err == nil {
+			t.Error("Export: got nil, want error")
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// An UnknownFieldError reports a key of a YAML config that does not match any
+// setting, for example because it is misspelled.
+type UnknownFieldError struct {
+	Path   string // the path of the file containing the key, if known
+	Line   int    // the line number of the key, 1-based
+	Column int    // the column number of the key, 1-based
+	Key    string // the unknown key
+	Type   string // the type of the setting containing the key, e.g., "gen.Enum"
+}
+
+// Error satisfies the error interface.
+func (e *UnknownFieldError) Error() string {
+	pos := fmt.Sprintf("line %d, column %d", e.Line, e.Column)
+	if e.Path != "" {
+		pos = fmt.Sprintf("%s:%d:%d", e.Path, e.Line, e.Column)
+	}
+	return fmt.Sprintf("%s: unknown field %q in %s", pos, e.Key, e.Type)
+}
+
+// UnknownFields reports the keys of the YAML configs from which c was read
+// that do not match any setting, including those of included files, or nil
+// if there are none. The error wraps an *UnknownFieldError for each key.
+//
+// The functions that read configs ignore unknown keys, so that configs remain
+// usable by older versions of the generator. Use UnknownFields to check for
+// keys that are ignored by mistake, such as misspelled option names.
+func (c *Config) UnknownFields() error {
+	errs := make([]error, len(c.unknown))
+	for i, e := range c.unknown {
+		errs[i] = e
+	}
+	return errors.Join(errs...)
+}
+
+// setSource records path as the file containing the unknown fields and the
+// enumerations of c whose file is not already known.
+func (c *Config) setSource(path string) {
+	for _, e := range c.unknown {
+		if e.Path == "" {
+			e.Path = path
+		}
+	}
+	setPath := func(enums []*Enum) {
+		for _, e := range enums {
+			if e.pos.Filename == "" && e.pos.Line > 0 {
+				e.pos.Filename = path
+			}
+		}
+	}
+	setPath(c.Enum)
+	for _, p := range c.Packages {
+		setPath(p.Enum)
+	}
+}
+
+// unknownFields returns an error for each key of the YAML document node that
+// does not correspond to a field of a value of type t.
+func unknownFields(node *yaml.Node, t reflect.Type) []*UnknownFieldError {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		var out []*UnknownFieldError
+		for _, n := range node.Content {
+			out = append(out, unknownFields(n, t)...)
+		}
+		return out
+
+	case yaml.SequenceNode:
+		if t.Kind() != reflect.Slice {
+			return nil // reported by the decoder
+		}
+		var out []*UnknownFieldError
+		for _, n := range node.Content {
+			out = append(out, unknownFields(n, t.Elem())...)
+		}
+		return out
+
+	case yaml.MappingNode:
+		var out []*UnknownFieldError
+		switch t.Kind() {
+		case reflect.Map:
+			for i := 1; i < len(node.Content); i += 2 {
+				out = append(out, unknownFields(node.Content[i], t.Elem())...)
+			}
+		case reflect.Struct:
+			fields := yamlFields(t)
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i]
+				if key.Value == "<<" {
+					continue // a merge key
+				}
+				ft, ok := fields[key.Value]
+				if !ok {
+					out = append(out, &UnknownFieldError{
+						Line:   key.Line,
+						Column: key.Column,
+						Key:    key.Value,
+						Type:   t.String(),
+					})
+					continue
+				}
+				out = append(out, unknownFields(node.Content[i+1], ft)...)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// yamlFields returns a map from the YAML keys of the fields of the struct type
+// t to their types, including the fields of inlined structs.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	out := make(map[string]reflect.Type)
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if strings.Contains(","+opts+",", ",inline,") {
+			maps.Copy(out, yamlFields(f.Type))
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		out[name] = f.Type
+	}
+	return out
+}
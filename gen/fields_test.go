@@ -0,0 +1,142 @@
+package gen_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestUnknownFields(t *testing.T) {
+	t.Run("Known", func(t *testing.T) {
+		cfg, err := gen.ConfigFromYAML("testdata/gentest.yml")
+		if err != nil {
+			t.Fatalf("ConfigFromYAML: %v", err)
+		}
+		if err := cfg.UnknownFields(); err != nil {
+			t.Errorf("UnknownFields: unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		dir := t.TempDir()
+		main := filepath.Join(dir, "main.yml")
+		more := filepath.Join(dir, "more.yml")
+		if err := os.WriteFile(main, []byte(`package: p
+regsitry: true
+include: [more.yml]
+enum:
+  - type: T
+    flagvalue: true
+    values:
+      - name: A
+        txt: a
+packages:
+  - package: q
+    output: q/q.go
+    enum:
+      - type: U
+        values: [{name: X, attrs: {any: thing}}]
+`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(more, []byte("enum:\n  - type: V\n    proto: {type: pb.V, valus: {}}\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := gen.ConfigFromYAML(main)
+		if err != nil {
+			t.Fatalf("ConfigFromYAML: %v", err)
+		}
+		if err := cfg.ResolveIncludes(main, ""); err != nil {
+			t.Fatalf("ResolveIncludes: %v", err)
+		}
+		err = cfg.UnknownFields()
+		if err == nil {
+			t.Fatal("UnknownFields: got nil, want error")
+		}
+		want := []string{
+			main + `:2:1: unknown field "regsitry" in gen.Config`,
+			main + `:6:5: unknown field "flagvalue" in gen.Enum`,
+			main + `:9:9: unknown field "txt" in gen.Value`,
+			more + `:3:25: unknown field "valus" in gen.ProtoEnum`,
+		}
+		if got := strings.Split(err.Error(), "\n"); !slices.Equal(got, want) {
+			t.Errorf("UnknownFields:\ngot:  %q\nwant: %q", got, want)
+		}
+		var ufe *gen.UnknownFieldError
+		if !errors.As(err, &ufe) || ufe.Key != "regsitry" || ufe.Line != 2 || ufe.Column != 1 {
+			t.Errorf("UnknownFields: got %+v, want *UnknownFieldError for regsitry", ufe)
+		}
+	})
+}
+
+func TestSourcePositions(t *testing.T) {
+	dir := t.TempDir()
+	write := func(t *testing.T, name, text string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(text), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("Unknown", func(t *testing.T) {
+		path := write(t, "unknown.go", `package p
+
+/*enumgen:type Color
+
+flagvalue: true
+values:
+  - name: Red
+*/
+
+//enumgen:type Shape
+// values:
+//   - name: Square
+//     txt: sq
+`)
+		cfg, err := gen.ConfigFromGoFile(path)
+		if err != nil {
+			t.Fatalf("ConfigFromGoFile: %v", err)
+		}
+		want := []string{
+			path + `:5:1: unknown field "flagvalue" in gen.Enum`,
+			path + `:13:8: unknown field "txt" in gen.Value`,
+		}
+		if got := strings.Split(cfg.UnknownFields().Error(), "\n"); !slices.Equal(got, want) {
+			t.Errorf("UnknownFields:\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+
+	t.Run("Syntax", func(t *testing.T) {
+		path := write(t, "syntax.go", "package p\n\n// Text.\n\n//enumgen:type T\n// values:\n//   - name: [\n")
+		_, err := gen.ConfigFromGoFile(path)
+		if err == nil || !strings.Contains(err.Error(), "line 7") {
+			t.Errorf("ConfigFromGoFile: got %v, want error at line 7", err)
+		}
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		path := write(t, "invalid.go", "package p\n\n/*enumgen:type T\nvalues:\n  - name: A\n  - name: A\n*/\n")
+		cfg, err := gen.ConfigFromGoFile(path)
+		if err != nil {
+			t.Fatalf("ConfigFromGoFile: %v", err)
+		}
+		var ve *gen.ValidationError
+		if err := cfg.Validate(); !errors.As(err, &ve) {
+			t.Fatalf("Validate: got %v, want *ValidationError", err)
+		}
+		if ve.Pos.Filename != path || ve.Pos.Line != 3 {
+			t.Errorf("Pos: got %v, want %s:3", ve.Pos, path)
+		}
+		if !strings.HasPrefix(ve.Error(), path+":3:1: ") {
+			t.Errorf("Error: got %q, want prefix %s:3:1", ve.Error(), path)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package gen
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// A fileSys is the file system from which configs and the files they refer to
+// are read. The zero value reads the local file system, with paths in the
+// local syntax; otherwise files are read from fsys, with the slash-separated
+// paths of fs.FS.
+type fileSys struct {
+	fsys fs.FS
+}
+
+var localFS fileSys
+
+func (f fileSys) readFile(name string) ([]byte, error) {
+	if f.fsys == nil {
+		return os.ReadFile(name)
+	}
+	return fs.ReadFile(f.fsys, name)
+}
+
+func (f fileSys) readDir(name string) ([]fs.DirEntry, error) {
+	if f.fsys == nil {
+		return os.ReadDir(name)
+	}
+	return fs.ReadDir(f.fsys, name)
+}
+
+func (f fileSys) stat(name string) (fs.FileInfo, error) {
+	if f.fsys == nil {
+		return os.Stat(name)
+	}
+	return fs.Stat(f.fsys, name)
+}
+
+// join joins slash-separated path elements into a path for f.
+func (f fileSys) join(elem ...string) string {
+	if f.fsys == nil {
+		for i, e := range elem {
+			elem[i] = filepath.FromSlash(e)
+		}
+		return filepath.Join(elem...)
+	}
+	return path.Join(elem...)
+}
+
+// dir returns all but the last element of a path for f.
+func (f fileSys) dir(p string) string {
+	if f.fsys == nil {
+		return filepath.Dir(p)
+	}
+	return path.Dir(p)
+}
+
+// clean returns the shortest path for f equivalent to p.
+func (f fileSys) clean(p string) string {
+	if f.fsys == nil {
+		return filepath.Clean(p)
+	}
+	return path.Clean(p)
+}
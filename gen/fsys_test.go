@@ -0,0 +1,106 @@
+package gen_test
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"cfg/main.yml": {Data: []byte(`package: p
+include: [sub/more.yml, /common.yml]
+enum:
+  - type: T
+    doc-file: t.md
+    values-from: vals.yml
+`)},
+		"cfg/t.md":         {Data: []byte("The T type.\n")},
+		"cfg/vals.yml":     {Data: []byte("- name: A\n- name: B\n")},
+		"cfg/sub/more.yml": {Data: []byte("enum:\n  - type: U\n    values: [{name: X}]\n")},
+		"common.yml":       {Data: []byte("enum:\n  - type: V\n    values: [{name: Y}]\n")},
+		"cfg/bad.yml":      {Data: []byte("package: p\ninclude: [missing.yml]\n")},
+		"cfg/main.json":    {Data: []byte(`{"package": "p", "enum": [{"type": "J", "values": [{"name": "A"}]}]}`)},
+
+		"pkg/types.go": {Data: []byte("package q\n\n/*enumgen:type W\nvalues:\n  - name: Z\n*/\n")},
+		"pkg/more.yml": {Data: []byte("merge: true\npackage: q\nenum:\n  - type: S\n    values: [{name: R}]\n")},
+	}
+	typeNames := func(cfg *gen.Config) []string {
+		var out []string
+		for _, e := range cfg.Enum {
+			out = append(out, e.Type)
+		}
+		return out
+	}
+
+	t.Run("Config", func(t *testing.T) {
+		cfg, err := gen.ConfigFromFS(fsys, "cfg/main.yml")
+		if err != nil {
+			t.Fatalf("ConfigFromFS: unexpected error: %v", err)
+		}
+		if err := cfg.ResolveIncludesFS(fsys, "cfg/main.yml", "."); err != nil {
+			t.Fatalf("ResolveIncludesFS: unexpected error: %v", err)
+		}
+		if got, want := typeNames(cfg), []string{"T", "U", "V"}; !slices.Equal(got, want) {
+			t.Errorf("Types: got %q, want %q", got, want)
+		}
+		if e := cfg.Enum[0]; e.Doc != "The T type." || len(e.Values) != 2 {
+			t.Errorf("Enum T: got doc %q, %d values; want doc and 2 values", e.Doc, len(e.Values))
+		}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Validate: unexpected error: %v", err)
+		}
+	})
+
+	t.Run("GoFile", func(t *testing.T) {
+		cfg, err := gen.ConfigFromFS(fsys, "pkg/types.go")
+		if err != nil {
+			t.Fatalf("ConfigFromFS: unexpected error: %v", err)
+		}
+		if got, want := typeNames(cfg), []string{"W"}; cfg.Package != "q" || !slices.Equal(got, want) {
+			t.Errorf("ConfigFromFS: got package %q, types %q; want q, %q", cfg.Package, got, want)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		cfg, err := gen.ConfigFromFS(fsys, "cfg/main.json")
+		if err != nil {
+			t.Fatalf("ConfigFromFS: unexpected error: %v", err)
+		}
+		if got, want := typeNames(cfg), []string{"J"}; cfg.Package != "p" || !slices.Equal(got, want) {
+			t.Errorf("ConfigFromFS: got package %q, types %q; want p, %q", cfg.Package, got, want)
+		}
+	})
+
+	t.Run("Package", func(t *testing.T) {
+		cfg, err := gen.LoadPackageFS(fsys, "pkg")
+		if err != nil {
+			t.Fatalf("LoadPackageFS: unexpected error: %v", err)
+		}
+		if got, want := typeNames(cfg), []string{"S", "W"}; cfg.Package != "q" || !slices.Equal(got, want) {
+			t.Errorf("LoadPackageFS: got package %q, types %q; want q, %q", cfg.Package, got, want)
+		}
+		if _, err := gen.LoadPackageFS(fsys, "."); !errors.Is(err, gen.ErrNoConfig) {
+			t.Errorf("LoadPackageFS(.): got %v, want %v", err, gen.ErrNoConfig)
+		}
+	})
+
+	t.Run("MissingInclude", func(t *testing.T) {
+		cfg, err := gen.ConfigFromFS(fsys, "cfg/bad.yml")
+		if err != nil {
+			t.Fatalf("ConfigFromFS: unexpected error: %v", err)
+		}
+		err = cfg.ResolveIncludesFS(fsys, "cfg/bad.yml", "")
+		var ie *gen.IncludeError
+		if !errors.As(err, &ie) || !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("ResolveIncludesFS: got %v, want *IncludeError for a missing file", err)
+		}
+		if want := []string{"cfg/bad.yml", "cfg/missing.yml"}; !slices.Equal(ie.Chain, want) {
+			t.Errorf("Chain: got %q, want %q", ie.Chain, want)
+		}
+	})
+}
@@ -6,10 +6,12 @@
 // # Type Structure
 //
 // The generated type is a struct containing an unexported small integer index
-// to the string representation of the enumerator.  Enumerators of the type can
-// be compared for equality by value, and can be used as map keys. The zero
-// value represents an unknown (invalid) enumerator; the Valid method reports
-// whether an enumerator is valid (i.e., non-zero).
+// to the string representation of the enumerator, or with the "pointer"
+// representation, an unexported pointer to an interned entry holding the
+// string.  Enumerators of the type can be compared for equality by value, and
+// can be used as map keys. The zero value represents an unknown (invalid)
+// enumerator; the Valid method reports whether an enumerator is valid (i.e.,
+// non-zero).
 //
 // The String method returns a string representation for each enumerator, which
 // defaults to the enumerator's base name.  The Enum method returns the name of
@@ -24,9 +26,11 @@
 //	   Valid() bool    // report whether the receiver is a valid nonzero enumerator
 //	}
 //
-// Callers wishing to accept arbitrary enumerations may define this interface.
+// Callers wishing to accept arbitrary enumerations may define this interface,
+// or set the emit-interface option to generate it in the output package.
 // It is not exported by the gen package to discourage inappropriate dependency
-// on the code generator.
+// on the code generator. The methods option of an enumeration can omit or
+// rename these methods, in which case it does not satisfy the interface.
 //
 // # Configuration
 //
@@ -34,26 +38,126 @@
 // package. The general structure of a config in YAML is:
 //
 //	package: "name"        # the name of the output package (required)
+//	registry: true         # (optional) generate an Enums map of all the enumerations
+//	emit-interface: true   # (optional) generate the interface satisfied by all the enumerations
+//	interface-name: "Enum" # (optional) name of the generated interface (default "EnumType")
+//	map-threshold: 32      # (optional) decode strings with maps for enumerations this large
+//	build-tags: "linux"    # (optional) build constraint for the generated files
+//	header: "text"         # (optional) comment text for the top of the generated files
+//	package-doc: "text"    # (optional) package doc comment listing the enumerations
+//	merge: true            # (optional) merge with the Go comment configs of the package directory
+//	struct-tags: true      # (optional) define enumerations from enum:"..." tags of struct fields
+//	name-policy:           # (optional) rules for the names of the enumerators
+//	  style: go-exported   # ... style of the variable names (go-identifier, mixed-caps, go-exported)
+//	  pattern: "[A-Z]\\w*" # ... regular expression the names must match
+//	  reserved: [None]     # ... names the enumerators may not have
 //
 //	enum:                  # a list of enumeration types to generate
 //
 //	  - type: "Name"       # the type name for this enum
 //	    prefix: "x"        # (optional) prefix to append to each enumerator name
+//	    suffix: "x"        # (optional) suffix to append to each enumerator name
+//	    name-style: camel  # (optional) style of enumerator names (exported, camel, screaming-snake, or a template)
+//	    name-policy: {...} # (optional) rules for the enumerator names, replacing the config policy
+//	    unexported: true   # (optional) make the type, enumerators, and functions unexported
+//	    receiver: "c"      # (optional) name of the method receiver (default "v")
 //	    zero: "Bad"        # (optional) name of zero enumerator
+//	    invalid-text: "?"  # (optional) string for the zero value (default "<invalid>")
+//	    allow-duplicate-text: true # (optional) allow enumerators to share a string
 //
 //	    doc: "text"        # (optional) documentation comment for the enum type
+//	    doc-file: "f.md"   # (optional) Markdown file of documentation for the enum type
+//	    extensible: true   # (optional) mark the enum as open to new enumerators
+//	    fingerprint: true  # (optional) generate a digest of the enumerators
+//	    strings-func: true # construct a *Strings function listing the enumerator strings
+//	    names-func: true   # construct a *Names function listing the enumerator names
+//	    completions: true  # construct a *Completions function for shell completion
+//	    choices: true      # construct a *Choices function for rendering form controls
+//	    by-name: true      # construct a Name method and a *ByName function for Go identifiers
+//	    docs: true         # construct *Docs and *Deprecations functions for runtime metadata
+//	    methods:           # (optional) omit (false) or rename the Enum, Index, String, and Valid methods
+//	      index: false
+//	      string: Label
+//	    groups:            # (optional) named groups of enumerators, with Is* predicates
+//	      Warm: [A, B]
+//	    predicates: true   # (optional) construct an Is* predicate method for each enumerator
+//	    attrs:             # (optional) names and types of enumerator attributes, with accessors
+//	      hex: string
+//	    display-default: en # (optional) fallback language for display names
 //	    val-doc: "text"    # (optional) aggregate documentation for the values
+//	    fixed-width: uint8 # (optional) fix the underlying integer type of the enum
+//	    representation: pointer # (optional) represent enumerators as pointers to interned strings
+//	    string-table: packed # (optional) pack the strings into a single string with offsets
+//	    code-type: int16   # (optional) integer type of enumerator codes (default int)
+//	    code-consts: true  # (optional) generate typed code constants, and Value/FromValue aliases for codes
+//	    lazy: true         # (optional) build lookup tables on first use
+//	    runtime: true      # (optional) delegate parsing to the enumgen/enum support package
+//	    parse-error: struct # (optional) kind of parse errors to report (sentinel, struct)
 //
 //	    constructor: true  # construct a New* function to convert strings to enumerators
+//	    constructors:      # (optional) select functions to convert strings to enumerators
+//	      new: true        # ... New* returns the zero enumerator if there is no match
+//	      parse: true      # ... Parse* reports an error if there is no match
+//	      must: true       # ... Must* panics if there is no match
+//	    quickcheck: true   # generate property tests for the parsing functions (see GenerateTests)
+//	    tests: true        # generate table-driven tests for the methods and encodings
+//	    examples: true     # generate documentation examples for printing and parsing
+//	    providers:         # (optional) generate dependency injection providers
+//	      env: "VAR"       # ... Provide*FromEnv parses the value of this environment variable
+//	      default: A       # ... ProvideDefault* returns this enumerator
+//	    index-base: 0      # (optional) index of the first enumerator, 0 or 1 (default 1)
+//	    sort: text         # (optional) order of the generated enumerators (name, text, index, none)
 //	    from-index: true   # construct a *FromIndex function to convert integers to enumerators
+//	    migrate:           # (optional) map former indices to enumerator names (see CompactIndexes)
+//	      5: B
+//	    array-index: true  # construct a Num* constant and an AsArrayIndex method for dense arrays
 //	    flag-value: true   # implement the flag.Value interface on this enum
-//	    text-marshal: true # implement the TextMarshaler/Unmarshaler interfaces on this enum
+//	    flag-func: true    # construct a *Flag function to define flags (requires flag-value)
+//	    text-marshal: true # implement the TextMarshaler/Unmarshaler/Appender interfaces on this enum
+//	    query: true        # construct helpers to encode the enum in URL query parameters (requires text-marshal)
+//	    xml: true          # implement the XML marshaling interfaces on this enum
+//	    cbor: true         # implement the CBOR marshaling interfaces on this enum
+//	    cbor-code: true    # (optional) encode CBOR as the integer code rather than the string
+//	    json-v2: true      # implement the encoding/json/v2 interfaces (see GenerateJSONv2)
+//	    unmarshal-unknown: zero # (optional) decoding of unknown strings (error, zero, keep-text)
+//	    formatter: true    # implement the fmt.Formatter interface on this enum
+//	    log-value: true    # implement the slog.LogValuer interface on this enum
+//	    log-group: true    # (optional) log the type name and string as a group
+//	    context: true      # construct With* and *FromContext functions for contexts
+//	    json-schema: true  # construct a *JSONSchemaFragment function returning a JSON Schema
+//	    proto:             # (optional) generate conversions to and from a protobuf enum
+//	      type: pb.Name    # the Go type of the protobuf enum (required)
+//	      import: "path"   # the import path of the package defining the type
+//	      values:          # (optional) map enumerator names to protobuf constant names
+//	        A: Name_ALPHA
 //
+//	    sql: true          # implement the sql.Scanner and driver.Valuer interfaces on this enum
+//	    gorm: true         # implement the GORM data type interfaces on this enum (implies sql)
+//	    db-type: "name"    # (optional) native Postgres enum type name for GORM
+//	    parse-list: true   # construct a Parse*List function to parse a delimited list of enumerators
+//	    list-unique: true  # (optional) reject duplicate enumerators in Parse*List
+//	    naming: "grouped"  # (optional) naming scheme for unexported tables (default, hashed, grouped)
+//	    extra-imports:     # (optional) packages to import for extra-code
+//	      - strconv
+//	    extra-code: |      # (optional) Go declarations to add after the enum
+//	      func (v Name) Quoted() string { return strconv.Quote(v.String()) }
+//
+//	    base: Common       # (optional) name of a base or enum whose values come first (see ExpandBases)
+//	    values-from: "f"   # (optional) file listing additional values (see ResolveIncludes)
 //	    values:
 //	      - name: A        # the name of the first enumerator (required)
 //	        doc: "text"    # (optional) documentation for this enumerator
+//	        doc-file: "f"  # (optional) Markdown file of documentation for this enumerator
 //	        text: "aaa"    # (optional) string text for the enumerator
 //	        index: 25      # (optional) integer index for the enumerator
+//	        code: 404      # (optional) integer code for the enumerator (see code-type)
+//	        grpc-code: "c" # (optional) name of the gRPC status code for the enumerator
+//	        attrs:         # (optional) values of the attributes of the enumerator
+//	          hex: "#f00"
+//	        display:       # (optional) display names for the enumerator, by language
+//	          en: "Alpha"
+//	        when: beta     # (optional) include the enumerator only if the tags satisfy this condition
+//	        deprecated: "t" # (optional) mark the enumerator deprecated, with this explanation
 //
 //	      - name: B        # ... additional enumerators
 //	      - name: C
@@ -62,42 +166,293 @@
 //	    values:
 //	      - name: X
 //	      - name: Y
+//
+//	packages:              # (optional) configs for other packages, each with its own output
+//	  - package: "other"   # ... the same settings as at the top level, plus
+//	    output: "path.go"  # ... the output path, relative to the config file
+//	    enum:
+//	      - type: "Kind"
+//	        values:
+//	          - name: K
+//
+//	include:               # (optional) other files whose enumerations are included
+//	  - "common/enums.yml" # ... relative to this file, or to the root if it starts with /
+//
+//	bases:                 # (optional) named lists of common values for the base option
+//	  Common:
+//	    - name: Unknown
+//
+//	templates:             # (optional) replacement or supplemental code templates
+//	  enum-extra: "text"   # ... map from template name to template text
+//
+//	profiles:              # (optional) named sets of option overrides (see Profile)
+//	  release:             # ... selected by name, e.g., with the -profile flag
+//	    enum:              # ... overrides for the options of every enum
+//	      naming: grouped
+//	    config:            # ... overrides for the top-level settings
+//	      templates: {}
+//
+// # Templates
+//
+// The generated code is produced by executing a collection of named
+// [text/template] templates. Each template is stored in a file with the same
+// name in the templates directory of this package. The "file" template is
+// executed with a [FileData] value, and invokes "header" to produce the
+// comments at the top of the file, then the "enum" template for each
+// enumeration with an [EnumData] value. The "enum" template in turn invokes one
+// template for each section of the output: "methods", "index", "strings",
+// "choices", "by-name", "docs", "fingerprint", "groups", "predicates", "codes", "attrs",
+// "display", "parse-error", "parse", "find", "constructors", "providers",
+// "parse-list", "from-index", "migrate", "array-index", "flag-value",
+// "text-marshal", "query", "xml", "cbor", "formatter", "log-value",
+// "context", "json-schema", "proto", "grpc", "sql", "gorm", "entry",
+// "unmarshal-unknown", and "values", the last of which invokes "enumerator" with a [ValueData] value
+// for each enumerator. Templates use the Elem, Make, and Ord
+// methods of [EnumData] to construct and inspect enumerators independently of
+// their representation.
+//
+// The [Config.GenerateTests] method instead executes the "test-file" template
+// with a [FileData] value, which invokes "quickcheck", "tests", and
+// "examples" for each enumeration that enables them.
+//
+// The [Config.GenerateJSONv2] method executes the "jsonv2-file" template with
+// a [FileData] value, which invokes "json-v2" for each enumeration that
+// enables it.
+//
+// If the registry option is set, the "file" template also invokes "registry".
+// If the emit-interface option is set, it invokes "interface".
+// If any enumeration sets parse-error to "struct", it invokes "invalid-error"
+// to define the InvalidEnumError type.
+//
+// The "file-extra" and "enum-extra" templates are empty by default, and can be
+// replaced to supplement the output for the file and each enumeration.
+//
+// A config may replace any of these templates, or define new ones, by setting
+// the templates field to a map of template names to template text:
+//
+//	templates:
+//	  enum-extra: |
+//	    func ({{.Type}}) Count() int { return {{len .Enumerators}} }
+//
+// The doc comment of each generated method is defined by a template named
+// "doc-" and the method name, such as "doc-String" or "doc-MarshalText",
+// which is executed with a [DocData] value. A config may replace these to
+// change the wording of the comments, which must include the comment
+// markers. Attribute accessors, group predicates, and enumerator predicates
+// use the "doc-attr", "doc-group", and "doc-predicate" templates.
+//
+// In addition to the built-in template functions, templates may call "quote"
+// to quote a string as a Go string literal, and "doc" to format a string as a
+// Go doc comment. The output of the templates is formatted with go/format.
 package gen
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"go/format"
+	"go/token"
 	"io"
+	"slices"
 	"strings"
-
-	"github.com/creachadair/mds/mapset"
 )
 
+// ExtensibleDirective is the directive comment added to the doc comment of
+// the generated type for an extensible enumeration. Tools that consume the
+// generated code can check for it to decide whether switches over the values
+// of the type must be exhaustive, or should have a default case.
+const ExtensibleDirective = "//enumgen:extensible"
+
 // A Config specifies a collection of enumerations in a single package.
+//
+// A Config may also list the configs for other packages in its Packages field,
+// in which case Package and Enum may be omitted. Generate produces output only
+// for the enumerations in Enum; use PackageConfigs to obtain the others.
 type Config struct {
-	Package string  // package name for the generated file (required)
-	Enum    []*Enum // enumerations to generate (at least one is required)
+	Package string  `json:"package,omitempty" yaml:"package,omitempty"` // package name for the generated file (required)
+	Enum    []*Enum `json:"enum,omitempty" yaml:"enum,omitempty"`       // enumerations to generate (at least one is required)
+
+	// If set, a build constraint expression, such as "linux && amd64", that is
+	// added to the generated files as a //go:build line.
+	BuildTags string `json:"build-tags,omitempty" yaml:"build-tags,omitempty"`
+
+	// If set, text added as comment lines to the top of the generated files,
+	// after the "Code generated" marker. The text should not contain comment
+	// markers.
+	Header string `json:"header,omitempty" yaml:"header,omitempty"`
+
+	// If true, generate a package-level Enums variable mapping the name of
+	// each enumeration type to the strings of its valid enumerators.
+	Registry bool `json:"registry,omitempty" yaml:"registry,omitempty"`
+
+	// If true, generate the interface satisfied by all the enumeration types
+	// (see "Type Structure"), so that code accepting any of the enumerations
+	// in the package need not define it. The interface is named by
+	// InterfaceName, or "EnumType" if that is empty.
+	EmitInterface bool   `json:"emit-interface,omitempty" yaml:"emit-interface,omitempty"`
+	InterfaceName string `json:"interface-name,omitempty" yaml:"interface-name,omitempty"`
+
+	// If set, the text of a package doc comment for the generated file,
+	// followed by a table of the enumerators of each enumeration. By
+	// convention the text should begin "Package <name>". This is useful when
+	// the generated file is the only file in its package.
+	PackageDoc string `json:"package-doc,omitempty" yaml:"package-doc,omitempty"`
+
+	// If set, the policy that the enumerator names of every enumeration must
+	// follow, unless the enumeration sets its own. Packages listed by the
+	// config inherit it unless they set their own.
+	NamePolicy *NamePolicy `json:"name-policy,omitempty" yaml:"name-policy,omitempty"`
+
+	// If positive, enumerations with at least this many enumerators decode
+	// strings with generated map lookups rather than by scanning the string
+	// table, which is faster for large enumerations. This does not apply to
+	// enumerations that use the runtime option.
+	MapThreshold int `json:"map-threshold,omitempty" yaml:"map-threshold,omitempty"`
+
+	// If set, configs for additional packages, each with its own output file.
+	Packages []*PackageConfig `json:"packages,omitempty" yaml:"packages,omitempty"`
+
+	// If set, paths of other YAML files whose enumerations are included in
+	// this config. Includes must be resolved by ResolveIncludes.
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+
+	// If set, named lists of common values, which enumerations can extend by
+	// setting their Base fields. Bases must be expanded by ExpandBases.
+	Bases map[string][]*Value `json:"bases,omitempty" yaml:"bases,omitempty"`
+
+	// If true, LoadPackageDir merges this config, read from a YAML file in a
+	// package directory, with the configs of the Go files and the other
+	// marked YAML files of the directory. A merged config may set only the
+	// package name, enumerations, and includes. Other YAML files in the
+	// directory are ignored.
+	Merge bool `json:"merge,omitempty" yaml:"merge,omitempty"`
+
+	// If true, each field of a struct type declared in a Go source file of the
+	// config whose tag has the form enum:"a,b,c" defines an enumeration. The
+	// type of the field, which must not otherwise be defined, names the
+	// enumeration, and the tag lists the strings of its enumerators. The name
+	// of each enumerator is the type name followed by its string in camel
+	// case, so a field of type Mode tagged enum:"dry-run" gives ModeDryRun.
+	// This setting applies to configs read from Go files, and may be set by an
+	// enumgen:package comment.
+	StructTags bool `json:"struct-tags,omitempty" yaml:"struct-tags,omitempty"`
+
+	// If set, each entry replaces or supplements the template of the given
+	// name used to generate code. See "Templates" in the package docs.
+	Templates map[string]string `json:"templates,omitempty" yaml:"templates,omitempty"`
+
+	// If set, named profiles of option overrides that can be selected by
+	// calling ApplyProfile before generating code.
+	Profiles map[string]*Profile `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+
+	// If set, a function applied to the generated source after it has been
+	// formatted by go/format, for example to apply the stricter style of a
+	// formatter such as gofumpt, or to regroup imports. If it reports an
+	// error, the source formatted by go/format is written to the output
+	// before reporting the error. This field cannot be set in a config file.
+	Format func(src []byte) ([]byte, error) `json:"-" yaml:"-"`
+
+	// If true, the generated source is written exactly as the templates
+	// produce it, without go/format or the Format function, for debugging
+	// templates and the generator itself. Such output is not gofmt-clean, and
+	// may not compile if a template is in error, but no formatting error is
+	// reported. This field cannot be set in a config file.
+	NoFormat bool `json:"-" yaml:"-"`
+
+	// If set, a function called by Generate with c and the complete generated
+	// source, after the source has been written to the output. This allows
+	// other generators to consume the config and the generated code in the
+	// same process, for example to write matching database migrations. If it
+	// reports an error, Generate reports that error. This field cannot be set
+	// in a config file.
+	AfterGenerate func(c *Config, src []byte) error `json:"-" yaml:"-"`
+
+	// If set, the version of the generator and the path of the config are
+	// recorded in the headers of the generated files, alongside the content
+	// hash of the config that is always recorded, so that tools can use
+	// ReadProvenance to identify their source. This field cannot be set in a
+	// config file.
+	Provenance *Provenance `json:"-" yaml:"-"`
+
+	unknown []*UnknownFieldError // keys ignored when reading the config
+	tagged  []*Enum              // enumerations defined by struct tags (see StructTags)
 }
 
 // An Enum defines an enumeration type.
 //
-// The generated type for an enumeration is a struct with an unexported pointer
-// to the string representation of the enumerator. This representation allows
-// cheap pointer comparisons, and users of the type outside the package cannot
-// create new non-zero values of the type. The zero value is explicitly defined
-// as the "unknown" value for an enumeration.
+// The generated type for an enumeration is a struct with an unexported field
+// that identifies the enumerator: by default its index in the string table,
+// or with the pointer Representation, a pointer to its interned string entry.
+// Either representation allows cheap comparisons, and users of the type
+// outside the package cannot create new non-zero values of the type. The zero
+// value is explicitly defined as the "unknown" value for an enumeration.
 type Enum struct {
-	Type   string   // enumeration type name (required)
-	Values []*Value // the enumeration values (required)
+	Type   string   `json:"type" yaml:"type"`                         // enumeration type name (required)
+	Values []*Value `json:"values,omitempty" yaml:"values,omitempty"` // the enumeration values (required)
+
+	// If set, the path of a YAML file containing a list of additional values,
+	// which are appended to Values by ResolveIncludes.
+	ValuesFrom string `json:"values-from,omitempty" yaml:"values-from,omitempty"`
+
+	// If set, the name of an entry of the bases of the config, or of another
+	// enumeration in the config, whose values are prepended to Values by
+	// ExpandBases.
+	Base string `json:"base,omitempty" yaml:"base,omitempty"`
 
 	// If set, this prefix is prepended to each enumerator's variable name.
 	// Otherwise, the variable name matches the Name field of the value.
-	Prefix string
+	Prefix string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+
+	// If set, this suffix is appended to each enumerator's variable name.
+	Suffix string `json:"suffix,omitempty" yaml:"suffix,omitempty"`
+
+	// If set, the style of the enumerator variable names, which combine the
+	// prefix, the name of the value, and the suffix:
+	//
+	//   - "exported" capitalizes each part, so prefix "color" and name "red"
+	//     give "ColorRed".
+	//   - "camel" is like "exported", but the first letter is lower case,
+	//     giving "colorRed".
+	//   - "screaming-snake" splits the parts into words and joins them in
+	//     upper case with underscores, giving "COLOR_RED".
+	//   - Otherwise, the style must be a template containing "{name}", in
+	//     which "{type}", "{prefix}", "{name}", and "{suffix}" are replaced
+	//     by the type name, prefix, value name, and suffix. For example,
+	//     "{type}{name}" gives "ColorRed" for type "Color" and name "Red".
+	//
+	// By default, the parts are concatenated unchanged.
+	NameStyle string `json:"name-style,omitempty" yaml:"name-style,omitempty"`
+
+	// If set, the policy that the enumerator names of the type must follow.
+	// This replaces the name policy of the config, if any.
+	NamePolicy *NamePolicy `json:"name-policy,omitempty" yaml:"name-policy,omitempty"`
+
+	// If true, the generated type, its enumerators, and the functions that
+	// are prefixed by its name are unexported, so that the enumeration may
+	// be a private detail of its package. The first letter of the type name
+	// and of each enumerator name is lowered, and functions whose names
+	// begin with a word such as "Parse" are renamed accordingly, so type
+	// "Color" gives the type "color" and the function "parseColor". The
+	// methods of the type are not affected.
+	Unexported bool `json:"unexported,omitempty" yaml:"unexported,omitempty"`
+
+	// If set, the name of the receiver of the generated methods of the type.
+	// It must be a valid Go identifier that is not used for another purpose
+	// in the methods generated for the options enabled on the type. By
+	// default, the receiver is "v".
+	Receiver string `json:"receiver,omitempty" yaml:"receiver,omitempty"`
 
 	// If set, this text is added as a doc comment for the enumeration.
 	// Multiple lines are OK. The text should not contain comment markers.
-	Doc string
+	Doc string `json:"doc,omitempty" yaml:"doc,omitempty"`
+
+	// If set, the path of a Markdown file whose contents are converted to the
+	// doc comment for the enumeration by ResolveIncludes. Headings, lists,
+	// fenced code blocks, and links are converted to their Go doc comment
+	// equivalents. It is an error to set both Doc and DocFile.
+	DocFile string `json:"doc-file,omitempty" yaml:"doc-file,omitempty"`
 
 	// If set, a variable is defined for the zero value with this name.
 	// Typically a name like "Unknown" or "Invalid" makes sense.
@@ -108,293 +463,759 @@ type Enum struct {
 	// entry with this name in the list of values. Otherwise the zero value will
 	// be undocumented and use a default string. The index of the zero value is
 	// always 0, even if explicitly specified.
-	Zero string
+	Zero string `json:"zero,omitempty" yaml:"zero,omitempty"`
+
+	// If set, this text is used as the string representation of the zero
+	// value, instead of the default "<invalid>". This is an alternative to
+	// setting the text of the zero enumerator in the list of values.
+	InvalidText string `json:"invalid-text,omitempty" yaml:"invalid-text,omitempty"`
+
+	// If true, distinct enumerators may have the same string representation.
+	// Parsing such a string gives the first enumerator in the list of values
+	// that has it. Otherwise, duplicate strings are reported as errors.
+	AllowDuplicateText bool `json:"allow-duplicate-text,omitempty" yaml:"allow-duplicate-text,omitempty"`
+
+	// If set, the unsigned integer type (uint8, uint16, uint32, or uint64) used
+	// to represent the enumeration. By default, the smallest type that can
+	// represent all the enumerators is chosen, which may change as enumerators
+	// are added. Setting a fixed width guarantees a stable size and layout for
+	// the type, for example to reserve room for enumerators to be added later.
+	// It is an error if the type cannot represent all the enumerators, or the
+	// explicit index of any enumerator.
+	FixedWidth string `json:"fixed-width,omitempty" yaml:"fixed-width,omitempty"`
+
+	// If "pointer", the struct field of the type is a pointer to an interned
+	// entry of the string table, so that the String method is a pointer
+	// dereference. If "" or "index" (the default), the field is an integer
+	// ordinal. Either way, enumerators are comparable and usable as map keys.
+	Representation string `json:"representation,omitempty" yaml:"representation,omitempty"`
+
+	// If "packed", the strings of the enumerators are concatenated into a
+	// single string, with a table of offsets, rather than stored in a slice
+	// of strings. This reduces the size of the tables for large enumerations.
+	// If "" or "slice" (the default), the strings are stored in a slice.
+	StringTable string `json:"string-table,omitempty" yaml:"string-table,omitempty"`
+
+	// If set, the integer type of the codes of the enumerators (see the Code
+	// field of Value). The default is int. It is an error if a code cannot be
+	// represented by the type.
+	CodeType string `json:"code-type,omitempty" yaml:"code-type,omitempty"`
+
+	// If true, generate a typed constant for the code of each non-zero
+	// enumerator, named by the enumerator with the suffix "Code", for use
+	// where a constant is required, such as the cases of a switch over the
+	// results of the Code method. This requires enumerator codes. It also
+	// generates a Value method and a <Type>FromValue function, which treat
+	// the codes as numeric payloads; these are aliases for Code and
+	// <Type>FromCode, so it cannot be combined with SQL.
+	CodeConsts bool `json:"code-consts,omitempty" yaml:"code-consts,omitempty"`
+
+	// If true, derived lookup tables (such as the map used by FromCode, and
+	// the maps selected by MapThreshold) are constructed on first use with
+	// sync.OnceValue, rather than during package initialization. This reduces
+	// the startup cost of programs that link many enumerations but use few of
+	// them. It requires enumerator codes or lookup maps.
+	Lazy bool `json:"lazy,omitempty" yaml:"lazy,omitempty"`
+
+	// If true, the generated parsing and decoding functions delegate to the
+	// generic functions of the runtime support package
+	// github.com/creachadair/enumgen/enum, rather than defining their own
+	// loops, and the type has an Enumerators method satisfying enum.Enum.
+	// This reduces the size of the generated code, at the cost of a dependency
+	// on the support package. By default, the generated code is self-contained.
+	Runtime bool `json:"runtime,omitempty" yaml:"runtime,omitempty"`
+
+	// If set, the kind of error reported by the generated functions when a
+	// string does not match any enumerator, so that callers can detect parse
+	// failures with errors.Is or errors.As rather than by their text:
+	//
+	//   - "sentinel" defines a variable ErrInvalid<Type>, which the errors wrap.
+	//   - "struct" reports errors of type *InvalidEnumError, which is defined
+	//     once in the generated file, with the type name and the input.
+	//
+	// By default, the errors are created with fmt.Errorf. In any case, the
+	// text of the errors is the same. This option cannot be combined with
+	// Runtime.
+	ParseError string `json:"parse-error,omitempty" yaml:"parse-error,omitempty"`
 
 	// If set, this text is inserted at the top of the var block in the
 	// generated code for the enumerator values.
-	ValDoc string `yaml:"val-doc"`
+	ValDoc string `json:"val-doc,omitempty" yaml:"val-doc,omitempty"`
 
 	// If true, generate a New function to convert strings to enumerators.
-	Constructor bool `yaml:"constructor"`
+	// This is equivalent to setting New in Constructors.
+	Constructor bool `json:"constructor,omitempty" yaml:"constructor,omitempty"`
+
+	// Select additional functions to convert strings to enumerators.
+	Constructors Constructors `json:"constructors,omitempty" yaml:"constructors,omitempty"`
+
+	// If true, the enumeration is marked as extensible, meaning that new
+	// enumerators may be added in the future, so code that switches over its
+	// values should have a default case. This adds an ExtensibleDirective to
+	// the doc comment of the generated type, and an "x-extensible" property
+	// to its schema definition. By default, an enumeration is sealed.
+	Extensible bool `json:"extensible,omitempty" yaml:"extensible,omitempty"`
+
+	// If true, generate a <Type>Strings function returning the strings of the
+	// valid enumerators, in order, for example to list the choices in help text.
+	StringsFunc bool `json:"strings-func,omitempty" yaml:"strings-func,omitempty"`
+
+	// If true, generate a <Type>Names function returning the Go names of the
+	// valid enumerators, in order.
+	NamesFunc bool `json:"names-func,omitempty" yaml:"names-func,omitempty"`
+
+	// If true, generate a <Type>Completions function returning the strings of
+	// the valid enumerators that begin with a given prefix, in order, for
+	// programs that support shell completion of enum-valued flags.
+	Completions bool `json:"completions,omitempty" yaml:"completions,omitempty"`
+
+	// If true, generate a <Type>Choices function returning a <Type>Choice for
+	// each valid enumerator, recording its string and whether it is among
+	// those selected, for templates that render form controls such as HTML
+	// <select> elements.
+	Choices bool `json:"choices,omitempty" yaml:"choices,omitempty"`
+
+	// If true, generate a Name method returning the Go identifier of each
+	// enumerator, and a <Type>ByName function returning the enumerator with
+	// a given identifier, as distinct from its string.
+	ByName bool `json:"by-name,omitempty" yaml:"by-name,omitempty"`
+
+	// If true, generate a <Type>Docs function returning a map from each
+	// documented enumerator to its doc text, and a <Type>Deprecations
+	// function returning a map from each deprecated enumerator to its
+	// deprecation text, so that programs can describe the enumerators at
+	// runtime without duplicating their docs.
+	Docs bool `json:"docs,omitempty" yaml:"docs,omitempty"`
+
+	// If set, customize the methods generated for every enumeration, keyed by
+	// their lower-case names (enum, index, string, valid). A value of "false"
+	// omits the method, and any other value renames it, for example to free
+	// the name for another interface.
+	Methods map[string]string `json:"methods,omitempty" yaml:"methods,omitempty"`
+
+	// If set, named groups of enumerators. For each group, an Is<Group>
+	// predicate method reports whether an enumerator belongs to the group,
+	// and a variable <Type><Group> lists the enumerators of the group.
+	Groups map[string][]string `json:"groups,omitempty" yaml:"groups,omitempty"`
+
+	// If true, generate an Is<Name> predicate method for each enumerator
+	// (including a named zero enumerator), reporting whether a value is that
+	// enumerator. The first letter of the name is capitalized.
+	Predicates bool `json:"predicates,omitempty" yaml:"predicates,omitempty"`
+
+	// If set, the names and Go types of attributes of the enumerators. Each
+	// attribute has an accessor method named for it (with the first letter in
+	// upper case), returning the value set for the enumerator in its Attrs, or
+	// the zero value of the type if none. The supported types are bool, int,
+	// int64, float64, and string.
+	Attrs map[string]string `json:"attrs,omitempty" yaml:"attrs,omitempty"`
+
+	// If set, the language of the display names used when no display name
+	// matches the language requested from the Display method. This requires
+	// that some enumerator has display names.
+	DisplayDefault string `json:"display-default,omitempty" yaml:"display-default,omitempty"`
+
+	// If true, generate a constant <Type>Fingerprint whose value is a digest
+	// of the names, strings, and indices of the enumerators, in order, so that
+	// programs can cheaply check that they agree on the definition of the
+	// enumeration (for example, during a protocol handshake).
+	Fingerprint bool `json:"fingerprint,omitempty" yaml:"fingerprint,omitempty"`
+
+	// If true, GenerateTests generates property tests for the functions that
+	// parse strings as enumerators of the type. At least one such function
+	// must be enabled.
+	QuickCheck bool `json:"quickcheck,omitempty" yaml:"quickcheck,omitempty"`
+
+	// If true, GenerateTests generates a table-driven test that checks the
+	// String and Valid methods of each enumerator, and that its string
+	// survives a round trip through each of the parsing and text encoding
+	// functions generated for the type.
+	Tests bool `json:"tests,omitempty" yaml:"tests,omitempty"`
+
+	// If true, GenerateTests generates runnable examples for the package
+	// documentation, which print the strings of the enumerators of the type,
+	// and parse strings with its exported parsing functions. Deprecated
+	// enumerators are not shown. The type must be exported.
+	Examples bool `json:"examples,omitempty" yaml:"examples,omitempty"`
+
+	// If set, generate provider functions for dependency injection.
+	Providers *Providers `json:"providers,omitempty" yaml:"providers,omitempty"`
+
+	// If set, the index of the first non-zero enumerator, unless its index is
+	// set explicitly: 0 or 1 (the default). With index base 0, the index of the
+	// first enumerator is 0, and the Index of the zero value is -1, so that
+	// indices can match 0-based external data without adjustment. The zero
+	// value remains invalid in either case.
+	IndexBase *int `json:"index-base,omitempty" yaml:"index-base,omitempty"`
+
+	// If set, the order of the enumerators in the generated code, including
+	// the string table and the functions that list the enumerators: "name"
+	// or "text" sorts them by their names or strings, "index" sorts them by
+	// their indices, and "none" (the default) keeps the order of the values.
+	// The indices of the enumerators are assigned in the order of the values
+	// before sorting, so that the config can be kept in any order without
+	// changing them.
+	Sort string `json:"sort,omitempty" yaml:"sort,omitempty"`
 
 	// If true, generate a FromIndex function to convert integers to enumerators.
-	FromIndex bool `yaml:"from-index"`
+	FromIndex bool `json:"from-index,omitempty" yaml:"from-index,omitempty"`
+
+	// If set, a map from former indices of enumerators to their names, as
+	// recorded by CompactIndexes. If non-empty, a MigrateOld<Type>Index
+	// function is generated to translate former indices to enumerators.
+	Migrate map[int]string `json:"migrate,omitempty" yaml:"migrate,omitempty"`
+
+	// If true, generate a Num constant giving the number of valid enumerators,
+	// and an AsArrayIndex method that maps valid enumerators to dense 0-based
+	// indices suitable for indexing an array of that length.
+	ArrayIndex bool `json:"array-index,omitempty" yaml:"array-index,omitempty"`
 
 	// If true, generate methods to implement flag.Value for the type.
-	FlagValue bool `yaml:"flag-value"`
+	FlagValue bool `json:"flag-value,omitempty" yaml:"flag-value,omitempty"`
+
+	// If true, generate a <Type>Flag function that defines a flag of the type
+	// in a flag.FlagSet with a default value, and lists the valid strings in
+	// its usage text. This requires FlagValue.
+	FlagFunc bool `json:"flag-func,omitempty" yaml:"flag-func,omitempty"`
+
+	// If true, implement encoding.TextMarshaler, encoding.TextUnmarshaler, and
+	// encoding.TextAppender for the type.
+	TextMarshal bool `json:"text-marshal,omitempty" yaml:"text-marshal,omitempty"`
+
+	// If true, generate a SetQuery method and a <Type>FromQuery function to
+	// store and retrieve enumerators as URL query parameters (url.Values).
+	// This requires TextMarshal.
+	Query bool `json:"query,omitempty" yaml:"query,omitempty"`
+
+	// If true, implement the fmt.Formatter interface for the type, so that %s
+	// formats the string of an enumerator, %d its index, and %v its type and
+	// string, as Type(text).
+	Formatter bool `json:"formatter,omitempty" yaml:"formatter,omitempty"`
+
+	// If true, implement the slog.LogValuer interface for the type, logging
+	// the string representation of the enumerator.
+	LogValue bool `json:"log-value,omitempty" yaml:"log-value,omitempty"`
+
+	// If true, the LogValue method logs a group containing the type name and
+	// the string of the enumerator. This requires LogValue.
+	LogGroup bool `json:"log-group,omitempty" yaml:"log-group,omitempty"`
+
+	// If true, generate functions to attach an enumerator to a context and to
+	// retrieve it, using an unexported key type.
+	Context bool `json:"context,omitempty" yaml:"context,omitempty"`
+
+	// If true, generate a JSONSchemaFragment function that returns a JSON
+	// Schema definition for the string representation of the type, as written
+	// by EmitSchema.
+	JSONSchema bool `json:"json-schema,omitempty" yaml:"json-schema,omitempty"`
+
+	// If true, implement the xml.Marshaler, xml.Unmarshaler, xml.MarshalerAttr,
+	// and xml.UnmarshalerAttr interfaces for the type, using the string
+	// representation of the enumerators.
+	XML bool `json:"xml,omitempty" yaml:"xml,omitempty"`
+
+	// If true, generate MarshalCBOR and UnmarshalCBOR methods for the type,
+	// which satisfy the cbor.Marshaler and cbor.Unmarshaler interfaces of
+	// github.com/fxamacker/cbor, using the string representation of the
+	// enumerators. The generated code does not import that package.
+	CBOR bool `json:"cbor,omitempty" yaml:"cbor,omitempty"`
+
+	// If true, the CBOR methods encode the integer code of each enumerator
+	// rather than its string. This requires cbor, and codes for the
+	// enumerators.
+	CBORCode bool `json:"cbor-code,omitempty" yaml:"cbor-code,omitempty"`
+
+	// If true, generate MarshalJSONTo and UnmarshalJSONFrom methods for the
+	// type, which satisfy the json.MarshalerTo and json.UnmarshalerFrom
+	// interfaces of the experimental encoding/json/v2 package, using the
+	// string representation of the enumerators. These methods are generated
+	// by GenerateJSONv2 rather than Generate, since they build only with
+	// GOEXPERIMENT=jsonv2.
+	JSONv2 bool `json:"json-v2,omitempty" yaml:"json-v2,omitempty"`
+
+	// The policy of the UnmarshalText, UnmarshalXML, UnmarshalXMLAttr, and
+	// UnmarshalJSONFrom methods for strings that do not encode a known
+	// enumerator, so that data from peers with newer versions of the
+	// enumeration can be read:
+	//
+	//   - "error" (or ""), the default, reports an error.
+	//   - "zero" decodes the string to the zero value.
+	//   - "keep-text" decodes the string to a value that is not Valid, but
+	//     retains the string, so that it is encoded again unchanged.
+	//     Unknown strings are interned, so that values with the same string
+	//     are equal. This requires the pointer Representation.
+	UnmarshalUnknown string `json:"unmarshal-unknown,omitempty" yaml:"unmarshal-unknown,omitempty"`
+
+	// If set, generate methods to convert between the enumeration and the
+	// specified protobuf enumeration type.
+	Proto *ProtoEnum `json:"proto,omitempty" yaml:"proto,omitempty"`
+
+	// If true, implement the sql.Scanner and driver.Valuer interfaces for the
+	// type, storing enumerators as their string representation.
+	SQL bool `json:"sql,omitempty" yaml:"sql,omitempty"`
+
+	// If true, implement the GORM data type interfaces for the type, in
+	// addition to the methods generated for SQL. This requires the generated
+	// package to depend on gorm.io/gorm.
+	GORM bool `json:"gorm,omitempty" yaml:"gorm,omitempty"`
+
+	// If set, the name of a native enumerated type to use as the column type
+	// for Postgres databases in the GormDBDataType method.
+	DBType string `json:"db-type,omitempty" yaml:"db-type,omitempty"`
+
+	// If true, generate a ParseList function to convert a string containing a
+	// delimited list of enumerator strings into a slice of enumerators.
+	ParseList bool `json:"parse-list,omitempty" yaml:"parse-list,omitempty"`
+
+	// If true, the ParseList function reports an error if the same enumerator
+	// occurs more than once in its input. This requires ParseList.
+	ListUnique bool `json:"list-unique,omitempty" yaml:"list-unique,omitempty"`
+
+	// If set, this selects how the unexported package-level symbols that
+	// support the enumeration (such as its string table) are named:
+	//
+	//   - "" or "default": one variable per table, named _str_Type, _idx_Type.
+	//   - "hashed": as default, but each name has a suffix derived from a hash
+	//     of the type name, e.g., _str_Type_1a2b3c4d.
+	//   - "grouped": all the tables are fields of a single unexported struct
+	//     variable named _enumgen_Type.
+	//
+	// No matter which scheme is chosen, no exported symbols are defined other
+	// than the type, its enumerators, and the functions requested by options.
+	Naming string `json:"naming,omitempty" yaml:"naming,omitempty"`
+
+	// If set, Go source text copied into the generated file after the other
+	// declarations for the enumeration, for example to define additional
+	// methods of the type. It must be a sequence of Go declarations.
+	ExtraCode string `json:"extra-code,omitempty" yaml:"extra-code,omitempty"`
+
+	// If set, additional packages to import for ExtraCode, each given as an
+	// import path optionally preceded by a package name and a space, e.g.,
+	// "strconv" or "pb example.com/api/pb". These are merged with the imports
+	// of the generated code.
+	ExtraImports []string `json:"extra-imports,omitempty" yaml:"extra-imports,omitempty"`
+
+	pos token.Position // where the enumeration is defined, if known
+}
+
+// Constructors selects the functions generated to convert strings to
+// enumerators. Each function returns the first enumerator whose string is a
+// case-insensitive match for its argument, and they differ only in how they
+// handle a string that does not match any enumerator.
+type Constructors struct {
+	// If true, generate a New function that returns the zero enumerator for
+	// a string that does not match.
+	New bool `json:"new,omitempty" yaml:"new,omitempty"`
+
+	// If true, generate a Parse function that reports an error for a string
+	// that does not match.
+	Parse bool `json:"parse,omitempty" yaml:"parse,omitempty"`
+
+	// If true, generate a Must function that panics for a string that does
+	// not match.
+	Must bool `json:"must,omitempty" yaml:"must,omitempty"`
+}
+
+// Providers selects provider functions for an enumeration, suitable for use
+// with dependency injection frameworks such as github.com/google/wire and
+// go.uber.org/fx. At least one of the fields must be set.
+type Providers struct {
+	// If set, generate a FromEnv provider that parses the enumerator from the
+	// value of the environment variable with this name.
+	Env string `json:"env,omitempty" yaml:"env,omitempty"`
+
+	// If set, the name of the enumerator to provide by default. This generates
+	// a ProvideDefault provider, and is also the value of the FromEnv provider
+	// when the environment variable is unset or empty.
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// A NamePolicy describes rules for the names of enumerators, which Validate
+// checks so that generation fails if an enumerator would have a
+// non-idiomatic, unwanted, or misleading name.
+type NamePolicy struct {
+	// If set, the style that the variable name of each enumerator, combining
+	// its prefix, name, and suffix, must follow:
+	//
+	//   - "go-identifier" requires a valid Go identifier that is not a
+	//     predeclared identifier such as "string" or "nil". Validate checks
+	//     this for every enumerator, so it adds no rules of its own.
+	//   - "mixed-caps" also rejects underscores, following the Go convention
+	//     for multi-word names.
+	//   - "go-exported" is like "mixed-caps", but also requires the name to
+	//     be exported.
+	Style string `json:"style,omitempty" yaml:"style,omitempty"`
+
+	// If set, a regular expression that the name of each enumerator, as
+	// written in the config without its prefix or suffix, must match in full.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+
+	// If set, names that enumerators may not have, as written in the config.
+	// The comparison ignores case, so "None" also reserves "NONE".
+	Reserved []string `json:"reserved,omitempty" yaml:"reserved,omitempty"`
+}
+
+// A ProtoEnum describes a protobuf enumeration type corresponding to an Enum.
+// If an Enum has a ProtoEnum, a ToProto method and a FromProto function are
+// generated to convert between the two types.
+type ProtoEnum struct {
+	// The name of the Go type generated for the protobuf enum, qualified by
+	// its package name, e.g., "mypb.Color" (required).
+	Type string `json:"type" yaml:"type"`
+
+	// The import path of the package that defines Type. This may be omitted
+	// if Type is defined in the same package as the generated code.
+	Import string `json:"import,omitempty" yaml:"import,omitempty"`
+
+	// Explicit mappings from enumerator names to the names of the
+	// corresponding protobuf enum constants, without package qualifiers.
+	// Enumerators not listed here correspond to the constant whose name is
+	// the unqualified protobuf type name joined to the enumerator name with an
+	// underscore, e.g., "Color_Red".
+	//
+	// The zero enumerator corresponds to the zero value of the protobuf type,
+	// unless it is included in this map.
+	Values map[string]string `json:"values,omitempty" yaml:"values,omitempty"`
+}
 
-	// If true, implement encoding.TextMarshaler for the type.
-	TextMarshal bool `yaml:"text-marshal"`
+// qualifier returns the package qualifier of p.Type, or "" if it has none.
+func (p *ProtoEnum) qualifier() string {
+	q, _, ok := strings.Cut(p.Type, ".")
+	if !ok {
+		return ""
+	}
+	return q
+}
+
+// constant returns the name of the protobuf enum constant corresponding to the
+// named enumerator, qualified by its package if necessary. If the enumerator
+// is not listed in p.Values, the default name is returned if dflt is true;
+// otherwise constant reports false.
+func (p *ProtoEnum) constant(name string, dflt bool) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	qual, base, ok := strings.Cut(p.Type, ".")
+	if !ok {
+		qual, base = "", p.Type
+	} else {
+		qual += "."
+	}
+	if c, ok := p.Values[name]; ok {
+		return qual + c, true
+	} else if dflt {
+		return qual + base + "_" + name, true
+	}
+	return "", false
 }
 
 // A Value defines a single enumerator.
 type Value struct {
-	Name string // enumerator name (required)
+	Name string `json:"name" yaml:"name"` // enumerator name (required)
 
 	// If set, this text is added as a doc comment for the enumerator value.  If
 	// it is a single line, it is added as a line comment; otherwise it is
 	// placed before the enumerator. The text should not contain comment markers.
 	// The placeholder {name} will be replaced with the final generated name of
 	// the enumerator.
-	Doc string
+	Doc string `json:"doc,omitempty" yaml:"doc,omitempty"`
+
+	// If set, the path of a Markdown file whose contents are converted to the
+	// doc comment for the enumerator by ResolveIncludes. It is an error to set
+	// both Doc and DocFile.
+	DocFile string `json:"doc-file,omitempty" yaml:"doc-file,omitempty"`
+
+	// If set, the enumerator is deprecated, and this text explains what to use
+	// instead. The text is added to the doc comment of the enumerator as a
+	// "Deprecated:" paragraph, which tools such as staticcheck recognize.
+	Deprecated string `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
 
 	// If set, this text is used as the string representation of the value.
 	// Otherwise, the Name field is used.
-	Text string
+	Text string `json:"text,omitempty" yaml:"text,omitempty"`
 
 	// If non-nil, this value is used as the index of the value.  Otherwise the
 	// index is one greater than the previous value's index.
-	Index *int
+	Index *int `json:"index,omitempty" yaml:"index,omitempty"`
+
+	// If set, a condition on the tags supplied to the generator, in the syntax
+	// of a Go build constraint (for example, "experimental && !release"). The
+	// enumerator is included only if the condition is satisfied. Conditions are
+	// evaluated by SelectValues, which must be called before generating code.
+	When string `json:"when,omitempty" yaml:"when,omitempty"`
+
+	// If set, the name of the gRPC status code (for example, "NotFound")
+	// corresponding to the enumerator. If any enumerator has a gRPC code, the
+	// type has a GRPCCode method returning the codes.Code of each enumerator,
+	// and a GRPCError method returning a status error whose message is the
+	// documentation of the enumerator. Enumerators without a gRPC code map
+	// to codes.Unknown.
+	GRPCCode string `json:"grpc-code,omitempty" yaml:"grpc-code,omitempty"`
+
+	// If set, the values of attributes of the enumerator, keyed by name. Each
+	// attribute must be declared in the Attrs of the enumeration, and its value
+	// must be valid for the declared type.
+	Attrs map[string]any `json:"attrs,omitempty" yaml:"attrs,omitempty"`
+
+	// If set, human-readable display names for the enumerator, keyed by
+	// language tag (for example, "en" or "pt-BR"). If any enumerator has
+	// display names, the type has a Display method that returns the display
+	// name for a language, falling back to the base language of the tag
+	// ("pt" for "pt-BR"), then to the DisplayDefault language of the
+	// enumeration, then to the string of the enumerator.
+	Display map[string]string `json:"display,omitempty" yaml:"display,omitempty"`
+
+	// If non-nil, this value is the integer code of the enumerator, returned
+	// by the Code method. Codes may be negative or sparse, but must be unique.
+	// If any enumerator has a code, all the non-zero enumerators must.
+	Code *int `json:"code,omitempty" yaml:"code,omitempty"`
 }
 
 // Generate generates the enumerations defined by c into w as Go source text.
+// It does not generate the enumerations of other packages listed by c.
 //
 // If there is an error formatting the generated code, the unformatted code is
 // still written to w before reporting the error. The caller should NOT use the
-// output in case of error. Any error means there is a bug in the generator,
-// and the output is written only to support debugging.
+// output in case of error. Unless c replaces some of the default templates,
+// any such error means there is a bug in the generator, and the output is
+// written only to support debugging. To get the unformatted code without an
+// error, set NoFormat.
+//
+// If c has an AfterGenerate function, it is called after the output has been
+// written without error.
 func (c *Config) Generate(w io.Writer) error {
-	if err := c.checkValid(); err != nil {
-		return err
+	if c.AfterGenerate == nil {
+		return c.execute(w, "file")
 	}
-
 	var buf bytes.Buffer
-	fmt.Fprint(&buf, "// Code generated by enumgen. DO NOT EDIT.\n\n")
-	fmt.Fprintf(&buf, "package %s\n", c.Package)
+	if err := c.execute(&buf, "file"); err != nil {
+		w.Write(buf.Bytes())
+		return err
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return c.afterGenerate(buf.Bytes())
+}
 
-	var imp mapset.Set[string]
+// afterGenerate calls the AfterGenerate function of c, if any, with src.
+func (c *Config) afterGenerate(src []byte) error {
+	if c.AfterGenerate == nil {
+		return nil
+	}
+	if err := c.AfterGenerate(c, src); err != nil {
+		return fmt.Errorf("after generate: %w", err)
+	}
+	return nil
+}
 
-	// If we are generating any flag or text marshaler values, import the "fmt"
-	// package used by the generated code for error reporting.
-	for _, e := range c.Enum {
-		if e.FlagValue || e.TextMarshal {
-			imp.Add("fmt", "strings")
-		} else if e.Constructor {
-			imp.Add("strings")
-		}
+// GenerateAll generates the enumerations defined by c into w, as Generate
+// does, but reports all the problems it finds rather than stopping at the
+// first. Like Generate, it reports every problem found by Validate. If c is
+// valid but its code cannot be generated, for example because of an error in
+// a template, it generates each enumeration separately, and reports the
+// errors of each one that fails. The output is written to w, and passed to
+// the AfterGenerate function of c if any, only if there are no errors.
+func (c *Config) GenerateAll(w io.Writer) error {
+	if err := c.Validate(); err != nil {
+		return err
 	}
-	if !imp.IsEmpty() {
-		fmt.Fprintln(&buf, "import (")
-		for p := range imp {
-			fmt.Fprintf(&buf, "\t%q\n", p)
+	var buf bytes.Buffer
+	err := c.execute(&buf, "file")
+	if err == nil {
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
 		}
-		fmt.Fprintln(&buf, ")")
+		return c.afterGenerate(buf.Bytes())
 	}
-
+	var errs []error
 	for _, e := range c.Enum {
-		fmt.Fprintln(&buf)
-		if err := e.generate(&buf); err != nil {
-			return fmt.Errorf("enum %q: %w", e.Type, err)
+		one := *c
+		one.Enum = []*Enum{e}
+		if err := one.execute(io.Discard, "file"); err != nil {
+			errs = append(errs, fmt.Errorf("enum %q: %w", e.Type, err))
 		}
 	}
-
-	// Format the generated source. If this fails, write the unformatted source
-	// to the output before reporting an error so the caller can debug.
-	src, err := format.Source(buf.Bytes())
-	if err != nil {
-		w.Write(buf.Bytes())
-		return fmt.Errorf("go format: %w", err)
+	if len(errs) == 0 {
+		return err // the enumerations fail only in combination
 	}
-	_, err = w.Write(src)
-	return err
+	return errors.Join(errs...)
 }
 
-// generate generates the enumeration defined by e into w.
-func (e *Enum) generate(w io.Writer) error {
-	zero, rest := e.extractZero()
-	if zero != nil && zero.Index != nil && *zero.Index != 0 {
-		return fmt.Errorf("cannot override index of zero enumerator %q", zero.Name)
+// GenerateTests generates Go test source text into w, containing tests for
+// the enumerations of c that enable quickcheck or tests, and examples for
+// those that enable examples.
+//
+// The property tests enabled by quickcheck check that each parsing function
+// for the type accepts the string of every valid enumerator, and rejects
+// random strings that are not the string of any enumerator. The tests use a
+// fixed random seed, so they are deterministic. The table-driven tests enabled
+// by tests check the methods of each enumerator, and that its string survives
+// a round trip through the parsing and text encoding functions. The examples
+// enabled by examples verify their output, as for any example.
+//
+// The output belongs in a _test.go file in the same package as the output of
+// Generate. It is an error if no enumeration of c enables quickcheck, tests,
+// or examples.
+// Formatting errors are handled as for Generate.
+func (c *Config) GenerateTests(w io.Writer) error {
+	if !c.HasTests() {
+		return errors.New("no enumerations enable quickcheck, tests, or examples")
 	}
+	return c.execute(w, "test-file")
+}
 
-	if doc := formatDoc(injectName(e.Doc, e.Type)); doc != "" {
-		fmt.Fprintln(w, doc)
-	}
-	base := baseType(len(e.Values))
-	field := fmt.Sprintf("_%s", e.Type)
+// HasTests reports whether any enumeration of c enables quickcheck, tests, or
+// examples, so that GenerateTests will produce output.
+func (c *Config) HasTests() bool {
+	return slices.ContainsFunc(c.Enum, func(e *Enum) bool { return e.QuickCheck || e.Tests || e.Examples })
+}
 
-	parseFunc := "" // empty means don't generate it
-	if e.Constructor {
-		parseFunc = fmt.Sprintf("New%s", e.Type)
-	} else if e.FlagValue {
-		parseFunc = fmt.Sprintf("new%s", e.Type)
+// GenerateJSONv2 generates Go source text into w, containing methods that
+// implement the json.MarshalerTo and json.UnmarshalerFrom interfaces for the
+// enumerations of c that enable json-v2.
+//
+// The encoding/json/v2 package is experimental, so the output is constrained
+// to build only with the goexperiment.jsonv2 tag, in addition to the build
+// tags of c. It belongs in a separate file in the same package as the output
+// of Generate. It is an error if no enumeration of c enables json-v2.
+// Formatting errors are handled as for Generate.
+func (c *Config) GenerateJSONv2(w io.Writer) error {
+	if !c.HasJSONv2() {
+		return errors.New("no enumerations enable json-v2")
 	}
-
-	// Generate the enumeration type.
-	fmt.Fprintf(w, "type %[1]s struct { %s %s }\n", e.Type, field, base)
-
-	// Extract the label strings and indices for the defined enumerators.
-	labels := make([]string, len(rest)+1)
-	indices := make([]int, len(rest)+1)
-	labels[0] = zero.label()
-	curIndex, setIndex := 1, false
-	for i, v := range rest {
-		labels[i+1] = v.label()
-		if v.Index != nil {
-			curIndex = *v.Index
-			setIndex = true
+	return c.render(w, "jsonv2-file", func(fd *FileData) {
+		fd.BuildTags = jsonV2Tag
+		if c.BuildTags != "" {
+			fd.BuildTags = "(" + c.BuildTags + ") && " + jsonV2Tag
 		}
-		indices[i+1] = curIndex
-		curIndex++
-	}
-	strs := fmt.Sprintf("_str_%s", e.Type)
-	idxs := fmt.Sprintf("_idx_%s", e.Type)
-
-	// Generate the Enum, String, and Valid methods.
-	fmt.Fprintf(w, `
-// Enum returns the name of the enumeration type for %[1]s.
-func (%[1]s) Enum() string { return %[1]q }
-
-// String returns the string representation of %[1]s v.
-func (v %[1]s) String() string { return %[3]s[v.%[2]s] }
-
-// Valid reports whether v is a valid non-zero %[1]s value.
-func (v %[1]s) Valid() bool { return v.%[2]s > 0 && int(v.%[2]s) < len(%[3]s) }
-`, e.Type, field, strs)
-
-	// Generate the Index method.
-	if setIndex {
-		// Case 1: The index has overrides (refer to _idx_Name[...]).
-		fmt.Fprintf(w, `
-// Index returns the integer index of %[1]s v.
-func (v %[1]s) Index() int { return %[3]s[v.%[2]s] }
-`, e.Type, field, idxs)
-	} else {
-		// The index is conventional (return the ordinal directly).
-		fmt.Fprintf(w, `
-// Index returns the integer index of %[1]s v.
-func (v %[1]s) Index() int { return int(v.%[2]s) }
-`, e.Type, field)
-	}
-
-	if parseFunc != "" {
-		fmt.Fprintf(w, `
-// %[2]s returns the first enumerator of %[1]s whose string is a
-// case-insensitive match for s. If no enumerator matches, it returns the
-// zero enumerator.
-func %[2]s(s string) %[1]s {
-   for i, opt := range %[3]s[1:] {
-      if strings.EqualFold(opt, s) {
-         return %[1]s{%[4]s(i+1)}
-      }
-   }
-   return %[1]s{0}
+	})
 }
-`, e.Type, parseFunc, strs, base)
-	}
-
-	if e.FromIndex {
-		fmt.Fprintf(w, `
-// %[2]s returns the first enumerator of %[1]s whose index equals v.
-// If no enumerator matches, it returns the zero enumerator.
-func %[2]s(v int) %[1]s {
-  var zero %[1]s
-`, e.Type, fmt.Sprintf("%sFromIndex", e.Type))
-		// If the index is conventional, use the ordinal directly.
-		// Otherwise we need to look it up explicitly.
-		if !setIndex {
-			fmt.Fprintf(w, "if v <= 0 || v >= len(%s) {\n return zero\n }\n", strs)
-			fmt.Fprintf(w, "return %s{%s(v)}\n}\n", e.Type, base)
-		} else {
-			fmt.Fprintln(w, "switch v{")
-			for _, v := range rest {
-				fullName := e.Prefix + v.Name
-				fmt.Fprintf(w, "case %s.Index():\n", fullName)
-				fmt.Fprintf(w, "\treturn %s\n", fullName)
-			}
-			fmt.Fprintln(w, "default:\n return zero\n}\n}")
-		}
-	}
 
-	// If requested, emit flag.Value methods.
-	if e.FlagValue {
-		fmt.Fprintf(w, `
-// Set implements part of the flag.Value interface for %[1]s.
-// A value must equal the string representation of an enumerator.
-func (v *%[1]s) Set(s string) error {
-   if e := %[5]s(s); e.Valid() {
-      *v = e
-      return nil
-   }
-   return fmt.Errorf("invalid value for %[1]s: %%q", s)
-}
-`, e.Type, field, strs, base, parseFunc)
-	}
-
-	// If requested, emit text marshaling methods.
-	if e.TextMarshal {
-		fmt.Fprintf(w, `
-// MarshalText encodes the value of the %[1]s enumerator as text.
-// It satisfies the encoding.TextMarshaler interface.
-func (v %[1]s) MarshalText() ([]byte, error) { return []byte(v.String()), nil }
-`, e.Type)
-		fmt.Fprintf(w, `
-// UnarshalText decodes the value of the %[1]s enumerator from a string.
-// It reports an error if data does not encode a known enumerator.
-// An empty slice decodes to the zero value.
-// This method satisfies the encoding.TextUnmarshaler interface.
-func (v *%[1]s) UnmarshalText(data []byte) error {
-   *v = %[1]s{}
-   text := string(data)
-   if text == "" || text == %[3]s[0] {
-      return nil
-   }
-   for i, opt := range %[3]s[1:] {
-      if opt == text {
-         v.%[2]s = %[4]s(i+1)
-         return nil
-      }
-   }
-   return fmt.Errorf("invalid value for %[1]s: %%q", text)
+// jsonV2Tag is the build tag that enables the encoding/json/v2 experiment.
+const jsonV2Tag = "goexperiment.jsonv2"
+
+// HasJSONv2 reports whether any enumeration of c enables json-v2, so that
+// GenerateJSONv2 will produce output.
+func (c *Config) HasJSONv2() bool {
+	return slices.ContainsFunc(c.Enum, func(e *Enum) bool { return e.JSONv2 })
 }
-`, e.Type, field, strs, base)
-	}
 
-	// Generate the enumerators and string and index values.
-	if doc := formatDoc(e.ValDoc); doc != "" {
-		fmt.Fprintln(w, doc)
+// DefaultOutput is the path under which GenerateFiles reports the output of
+// the top-level enumerations of a config.
+const DefaultOutput = "enums.go"
+
+// GenerateFiles generates the enumerations of c and of the packages it lists,
+// and returns the contents of the generated files keyed by path, rather than
+// writing them to an io.Writer. The output of the top-level enumerations of c,
+// if any, has the path DefaultOutput, and the output of each package has its
+// Output path, as written in the config. Beside each output are the files for
+// its tests and json/v2 methods, if it has them (see TestPath and JSONv2Path).
+//
+// All the configs are validated, and the outputs checked with CheckOutputs,
+// before any code is generated. If any output cannot be generated, the error
+// is reported and no files are returned. The AfterGenerate functions of the
+// configs are called as for Generate.
+func (c *Config) GenerateFiles() (map[string][]byte, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
 	}
-	fmt.Fprintf(w, "var(\n\t%s = []string{", strs)
-	for _, label := range labels {
-		fmt.Fprintf(w, "%q,", label)
+	pcs := c.PackageConfigs()
+	if len(c.Enum) != 0 || len(pcs) == 0 {
+		top := *c
+		top.Packages = nil
+		pcs = append(pcs, &PackageConfig{Output: DefaultOutput, Config: top})
 	}
-	fmt.Fprint(w, "}\n")
-	if setIndex {
-		fmt.Fprintf(w, "\t%s = []int{", idxs)
-		for _, idx := range indices {
-			fmt.Fprintf(w, "%d,", idx)
+	if err := CheckOutputs(pcs); err != nil {
+		return nil, err
+	}
+	out := make(map[string][]byte)
+	add := func(path string, generate func(io.Writer) error) error {
+		var buf bytes.Buffer
+		if err := generate(&buf); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
 		}
-		fmt.Fprint(w, "}\n\n")
-	} else {
-		fmt.Fprintln(w)
+		out[path] = buf.Bytes()
+		return nil
 	}
-
-	enumerate := func(i int, v *Value) {
-		fullName := e.Prefix + v.Name
-		doc := formatDoc(injectName(v.Doc, fullName))
-		multiline := strings.Contains(doc, "\n")
-		if doc != "" && multiline {
-			fmt.Fprintf(w, "\t%s\n", doc)
+	for _, pc := range pcs {
+		cfg := &pc.Config
+		if err := add(pc.Output, cfg.Generate); err != nil {
+			return nil, err
+		}
+		if cfg.HasTests() {
+			if err := add(TestPath(pc.Output), cfg.GenerateTests); err != nil {
+				return nil, err
+			}
 		}
-		fmt.Fprintf(w, "\t%[1]s = %[2]s{%[3]d}", fullName, e.Type, i)
-		if doc != "" {
-			if multiline {
-				fmt.Fprintln(w) // extra space after documented enumerator
-			} else {
-				fmt.Fprint(w, "\t", doc)
+		if cfg.HasJSONv2() {
+			if err := add(JSONv2Path(pc.Output), cfg.GenerateJSONv2); err != nil {
+				return nil, err
 			}
 		}
-		fmt.Fprintln(w)
 	}
-	if zero != nil {
-		enumerate(0, zero)
-	} else if e.Zero != "" {
-		fmt.Fprintf(w, "\t%s%s = %s{0}\n", e.Prefix, e.Zero, e.Type)
+	return out, nil
+}
+
+// TestPath returns the path of the test file beside the output at path,
+// e.g., enums.go → enums_test.go.
+func TestPath(path string) string { return strings.TrimSuffix(path, ".go") + "_test.go" }
+
+// JSONv2Path returns the path of the json/v2 file beside the output at path,
+// e.g., enums.go → enums_jsonv2.go.
+func JSONv2Path(path string) string { return strings.TrimSuffix(path, ".go") + "_jsonv2.go" }
+
+// execute validates c and generates formatted Go source text into w from the
+// named template.
+func (c *Config) execute(w io.Writer, name string) error { return c.render(w, name, nil) }
+
+// render validates c and generates formatted Go source text into w from the
+// named template. If edit != nil, it is called to modify the template data
+// before the template is executed.
+func (c *Config) render(w io.Writer, name string, edit func(*FileData)) error {
+	if err := c.Validate(); err != nil {
+		return err
+	} else if len(c.Enum) == 0 {
+		return errors.New("no enumerations defined")
+	}
+	t, err := c.loadTemplates()
+	if err != nil {
+		return err
 	}
-	for i, v := range rest {
-		enumerate(i+1, v)
+	data, err := c.fileData()
+	if err != nil {
+		return err
 	}
-	fmt.Fprintln(w, ")")
-	return nil
+	if edit != nil {
+		edit(data)
+	}
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, name, data); err != nil {
+		return fmt.Errorf("executing templates: %w", err)
+	}
+
+	if c.NoFormat {
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+
+	// Format the generated source. If this fails, write the unformatted source
+	// to the output before reporting an error so the caller can debug.
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		w.Write(buf.Bytes())
+		return fmt.Errorf("go format: %w", err)
+	}
+	if c.Format != nil {
+		out, err := c.Format(src)
+		if err != nil {
+			w.Write(src)
+			return fmt.Errorf("format: %w", err)
+		}
+		src = out
+	}
+	_, err = w.Write(src)
+	return err
 }
 
 // extractZero separates and returns the zero enumerator and the non-zero
@@ -416,6 +1237,43 @@ func (e *Enum) extractZero() (zero *Value, rest []*Value) {
 	return nil, e.Values
 }
 
+// tableName returns the expression used to refer to the specified kind of
+// table for e (for example, "str" or "idx"), according to its naming scheme.
+func (e *Enum) tableName(kind string) string {
+	switch e.Naming {
+	case "hashed":
+		h := sha256.Sum256([]byte(e.Type))
+		return fmt.Sprintf("_%s_%s_%s", kind, e.Type, hex.EncodeToString(h[:4]))
+	case "grouped":
+		return groupName(e.Type) + "." + kind
+	default:
+		return "_" + kind + "_" + e.Type
+	}
+}
+
+// typeName returns the name of the specified kind of unexported type for e,
+// according to its naming scheme. Since a type cannot belong to the grouped
+// table variable, grouped names are derived from the variable name.
+func (e *Enum) typeName(kind string) string {
+	if e.Naming == "grouped" {
+		return groupName(e.Type) + "_" + kind
+	}
+	return e.tableName(kind)
+}
+
+// groupName returns the name of the table variable for the "grouped" naming
+// scheme for the specified type name.
+func groupName(typeName string) string { return "_enumgen_" + typeName }
+
+// zeroLabel returns the label string for the zero enumerator of e, given the
+// value extracted for it by extractZero (which may be nil).
+func (e *Enum) zeroLabel(zero *Value) string {
+	if e.InvalidText != "" && (zero == nil || zero.Text == "") {
+		return e.InvalidText
+	}
+	return zero.label()
+}
+
 // label returns the label string for v.
 func (v *Value) label() string {
 	if v == nil {
@@ -427,18 +1285,43 @@ func (v *Value) label() string {
 }
 
 // formatDoc reformats a doc string into Go line comments. Line breaks in the
-// input are preserved. If s == "", the result is also empty.
+// input are preserved, as is indentation beyond that common to all the
+// non-blank lines (for example, in code blocks). If s == "", the result is
+// also empty.
 func formatDoc(s string) string {
-	if s == "" {
+	if strings.TrimSpace(s) == "" {
 		return ""
 	}
-	lines := strings.Split(strings.TrimSpace(s), "\n")
+	lines := strings.Split(strings.Trim(s, "\n"), "\n")
+	indent := -1
 	for i, line := range lines {
-		lines[i] = "// " + strings.TrimSpace(line)
+		lines[i] = strings.TrimRight(line, " \t")
+		if n := len(lines[i]) - len(strings.TrimLeft(lines[i], " \t")); lines[i] != "" && (indent < 0 || n < indent) {
+			indent = n
+		}
+	}
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = "//"
+		} else {
+			lines[i] = "// " + line[indent:]
+		}
 	}
 	return strings.Join(lines, "\n")
 }
 
+// docText returns the text of the doc comment for v, whose generated name is
+// name, followed by its deprecation notice, if any.
+func (v *Value) docText(name string) string {
+	doc := strings.TrimSpace(injectName(v.Doc, name))
+	if v.Deprecated == "" {
+		return doc
+	} else if doc != "" {
+		doc += "\n\n"
+	}
+	return doc + "Deprecated: " + strings.TrimSpace(v.Deprecated)
+}
+
 // injectName replaces "{name}" markers in s with the specified name.
 func injectName(s, name string) string {
 	return strings.ReplaceAll(s, "{name}", name)
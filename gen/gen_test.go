@@ -2,18 +2,40 @@ package gen_test
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
 	"encoding"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"flag"
 	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"html/template"
 	"io"
+	"log/slog"
+	"maps"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
+	"unsafe"
 
+	"github.com/creachadair/enumgen/enum"
 	"github.com/creachadair/enumgen/gen"
 	"github.com/creachadair/enumgen/gen/testdata"
+	"github.com/creachadair/mds/mtest"
 )
 
 type enumType interface {
@@ -38,8 +60,20 @@ func checkGenerated(t *testing.T) {
 
 	// Verify that the generator package and the testdata config match the hash
 	// embedded in the generated test data.
+	srcs, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("Listing sources: %v", err)
+	}
+	srcs = slices.DeleteFunc(srcs, func(s string) bool { return strings.HasSuffix(s, "_test.go") })
+	tmpls, err := filepath.Glob("templates/*.tmpl")
+	if err != nil {
+		t.Fatalf("Listing templates: %v", err)
+	}
+	inputs := append(srcs, tmpls...)
+	inputs = append(inputs, "testdata/gentest.yml", "testdata/testdata.go")
+
 	h := sha256.New()
-	for _, path := range []string{"gen.go", "config.go", "testdata/gentest.yml", "testdata/testdata.go"} {
+	for _, path := range inputs {
 		f, err := os.Open(path)
 		if err != nil {
 			t.Fatalf("Reading input: %v", err)
@@ -59,6 +93,30 @@ To update the test data to match, run:
 `)
 		t.Fatalf("Got hash %q, want %q", testdata.GeneratorHash, actual)
 	}
+
+	// Verify that the provenance recorded in the generated test data matches
+	// the config. This is redundant with the hash above, but checks that the
+	// provenance is usable to detect stale output.
+	src, err := os.ReadFile("testdata/enums.go")
+	if err != nil {
+		t.Fatalf("Reading generated source: %v", err)
+	}
+	prov, err := gen.ReadProvenance(src)
+	if err != nil {
+		t.Fatalf("ReadProvenance: %v", err)
+	}
+	cfg, err := gen.ConfigFromYAML("testdata/gentest.yml")
+	if err != nil {
+		t.Fatalf("Loading config: %v", err)
+	}
+	if err := cfg.SelectValues(); err != nil {
+		t.Fatalf("SelectValues: %v", err)
+	}
+	if want, err := cfg.ContentHash(); err != nil {
+		t.Fatalf("ContentHash: %v", err)
+	} else if prov.Config != "gentest.yml" || prov.Hash != want {
+		t.Fatalf("Provenance: got %+v, want config gentest.yml, hash %q", prov, want)
+	}
 }
 
 func TestEnums(t *testing.T) {
@@ -96,6 +154,146 @@ func TestEnums(t *testing.T) {
 		check(t, testdata.Two, true, "tango")
 	})
 
+	t.Run("CountIndexBase", func(t *testing.T) {
+		for _, tc := range []struct {
+			v     testdata.Count
+			index int
+		}{
+			{testdata.Count{}, -1},
+			{testdata.One, 0},
+			{testdata.Two, 1},
+		} {
+			if got := tc.v.Index(); got != tc.index {
+				t.Errorf("%v.Index(): got %d, want %d", tc.v, got, tc.index)
+			}
+			if tc.index < 0 {
+				continue
+			}
+			if got := testdata.CountFromIndex(tc.index); got != tc.v {
+				t.Errorf("CountFromIndex(%d): got %v, want %v", tc.index, got, tc.v)
+			}
+		}
+		if got := testdata.CountFromIndex(2); got.Valid() {
+			t.Errorf("CountFromIndex(2): got %v, want invalid", got)
+		}
+	})
+
+	t.Run("CountDocs", func(t *testing.T) {
+		docs := testdata.CountDocs()
+		if len(docs) != 2 || docs[testdata.Zero] != "Nothing to see here" || docs[testdata.One] != "The very loneliest" {
+			t.Errorf("CountDocs: got %v, want docs for Zero and One", docs)
+		}
+		dep := testdata.CountDeprecations()
+		if len(dep) != 1 || dep[testdata.Two] != "Use One twice." {
+			t.Errorf("CountDeprecations: got %v, want Two", dep)
+		}
+	})
+
+	t.Run("CountJSONSchema", func(t *testing.T) {
+		const want = `{"type":"string","enum":["lonely","tango"],"x-extensible":true}`
+		if got := testdata.CountJSONSchemaFragment(); got != want {
+			t.Errorf("CountJSONSchemaFragment: got %#q, want %#q", got, want)
+		}
+	})
+
+	t.Run("LogValue", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey || a.Key == slog.LevelKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+		log.Info("test", "e3", testdata.X, "count", testdata.Two)
+		const want = "msg=test e3=foo count.type=Count count.value=tango\n"
+		if got := buf.String(); got != want {
+			t.Errorf("Log: got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("FixedWidth", func(t *testing.T) {
+		if got := unsafe.Sizeof(testdata.E3{}); got != 4 {
+			t.Errorf("Size of E3: got %d, want 4", got)
+		}
+	})
+
+	t.Run("Fingerprint", func(t *testing.T) {
+		const input = "Unknown\t\"Unknown\"\t0\nOK\t\"OK\"\t1\nNotFound\t\"NotFound\"\t2\nTeapot\t\"Teapot\"\t3\n"
+		want := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(input)))
+		if testdata.StatusFingerprint != want {
+			t.Errorf("StatusFingerprint: got %q, want %q", testdata.StatusFingerprint, want)
+		}
+	})
+
+	t.Run("Codes", func(t *testing.T) {
+		for _, tc := range []struct {
+			e    testdata.Status
+			code int
+		}{
+			{testdata.Unknown, 0}, {testdata.OK, 200}, {testdata.NotFound, 404}, {testdata.Teapot, 418},
+		} {
+			if got := tc.e.Code(); got != tc.code {
+				t.Errorf("%v.Code(): got %d, want %d", tc.e, got, tc.code)
+			}
+			if got := testdata.StatusFromCode(tc.code); got != tc.e {
+				t.Errorf("StatusFromCode(%d): got %v, want %v", tc.code, got, tc.e)
+			}
+		}
+		if got := testdata.StatusFromCode(500); got != testdata.Unknown {
+			t.Errorf("StatusFromCode(500): got %v, want %v", got, testdata.Unknown)
+		}
+
+		var gc int8 = testdata.G1.Code()
+		if gc != -20 {
+			t.Errorf("G1.Code(): got %d, want -20", gc)
+		}
+		if got := testdata.GroupedFromCode(10); got != testdata.G2 {
+			t.Errorf("GroupedFromCode(10): got %v, want %v", got, testdata.G2)
+		}
+
+		// The code constants have the type of the codes, and the values are
+		// aliases for the codes.
+		var codes = []uint16{testdata.HTTPCode, testdata.HTTPSCode}
+		if got := []uint16{testdata.HTTP.Code(), testdata.HTTPS.Code()}; !slices.Equal(got, codes) {
+			t.Errorf("Port codes: got %d, want %d", got, codes)
+		}
+		if got := []uint16{testdata.HTTP.Value(), testdata.HTTPS.Value()}; !slices.Equal(got, codes) {
+			t.Errorf("Port values: got %d, want %d", got, codes)
+		}
+		if got := testdata.PortFromValue(443); got != testdata.HTTPS {
+			t.Errorf("PortFromValue(443): got %v, want %v", got, testdata.HTTPS)
+		}
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		ctx := context.Background()
+		if got, ok := testdata.HashedFromContext(ctx); ok {
+			t.Errorf("HashedFromContext(empty): got %v, want none", got)
+		}
+		ctx = testdata.WithHashed(ctx, testdata.H2)
+		ctx = testdata.WithGrouped(ctx, testdata.G1)
+		if got, ok := testdata.HashedFromContext(ctx); !ok || got != testdata.H2 {
+			t.Errorf("HashedFromContext: got (%v, %v), want (%v, true)", got, ok, testdata.H2)
+		}
+		if got, ok := testdata.GroupedFromContext(ctx); !ok || got != testdata.G1 {
+			t.Errorf("GroupedFromContext: got (%v, %v), want (%v, true)", got, ok, testdata.G1)
+		}
+	})
+
+	t.Run("Registry", func(t *testing.T) {
+		if got, want := testdata.Enums["E1"], []string{"alpha", "bravo", "C"}; !slices.Equal(got, want) {
+			t.Errorf("Enums[E1]: got %q, want %q", got, want)
+		}
+		if got, want := testdata.Enums["Count"], []string{"lonely", "tango"}; !slices.Equal(got, want) {
+			t.Errorf("Enums[Count]: got %q, want %q", got, want)
+		}
+		if _, ok := testdata.Enums["E4"]; ok {
+			t.Error("Enums contains E4, which is generated in another file")
+		}
+	})
+
 	t.Run("E1Index", func(t *testing.T) {
 		var zero testdata.E1
 		for i, e := range []testdata.E1{zero, testdata.A, testdata.B, testdata.C} {
@@ -105,6 +303,382 @@ func TestEnums(t *testing.T) {
 		}
 	})
 
+	t.Run("Migrate", func(t *testing.T) {
+		for old, want := range map[int]testdata.Grouped{
+			0: {}, 1: testdata.G1, 3: testdata.G2, 4: {}, 5: testdata.G2,
+		} {
+			if got := testdata.MigrateOldGroupedIndex(old); got != want {
+				t.Errorf("MigrateOldGroupedIndex(%d): got %v, want %v", old, got, want)
+			}
+		}
+	})
+
+	t.Run("Runtime", func(t *testing.T) {
+		if got, want := enum.Values[testdata.E3](), []testdata.E3{testdata.X, testdata.Y}; !slices.Equal(got, want) {
+			t.Errorf("Values[E3]: got %v, want %v", got, want)
+		}
+		if got := enum.Lookup[testdata.Hashed]("h2"); got != testdata.H2 {
+			t.Errorf("Lookup[Hashed](h2): got %v, want %v", got, testdata.H2)
+		}
+		if _, err := testdata.ParseHashed("nope"); err == nil || err.Error() != `invalid value for Hashed: "nope"` {
+			t.Errorf("ParseHashed(nope): got %v, want invalid value error", err)
+		}
+		mtest.MustPanic(t, func() { testdata.MustHashed("nope") })
+
+		var g testdata.Grouped
+		if err := g.UnmarshalText([]byte("second")); err != nil || g != testdata.G2 {
+			t.Errorf("UnmarshalText(second): got %v, %v; want %v, nil", g, err, testdata.G2)
+		}
+		if err := g.UnmarshalText([]byte("third")); err == nil || g.Valid() {
+			t.Errorf("UnmarshalText(third): got %v, %v; want zero, error", g, err)
+		}
+
+		data, err := enum.MarshalJSON(testdata.Y)
+		if err != nil || string(data) != `"bar"` {
+			t.Errorf("MarshalJSON(Y): got %#q, %v; want %#q", data, err, `"bar"`)
+		}
+		var e testdata.E3
+		if err := enum.UnmarshalJSON(data, &e); err != nil || e != testdata.Y {
+			t.Errorf("UnmarshalJSON(%#q): got %v, %v; want %v", data, e, err, testdata.Y)
+		}
+
+		if _, err := testdata.ParseE3List("foo,bar,foo", ""); err == nil {
+			t.Error("ParseE3List: got nil error for duplicates")
+		}
+	})
+
+	t.Run("ParseError", func(t *testing.T) {
+		_, err := testdata.ParseE1List("alpha,bogus", "")
+		if !errors.Is(err, testdata.ErrInvalidE1) {
+			t.Errorf("ParseE1List(bogus): got %v, want %v", err, testdata.ErrInvalidE1)
+		} else if got, want := err.Error(), `invalid value for E1: "bogus"`; got != want {
+			t.Errorf("ParseE1List(bogus): got %q, want %q", got, want)
+		}
+
+		var v testdata.E2
+		err = v.UnmarshalText([]byte("bogus"))
+		var ie *testdata.InvalidEnumError
+		if !errors.As(err, &ie) {
+			t.Fatalf("UnmarshalText(bogus): got %v, want *InvalidEnumError", err)
+		}
+		if ie.Type != "E2" || ie.Input != "bogus" {
+			t.Errorf("UnmarshalText(bogus): got %+v, want {E2 bogus}", ie)
+		}
+		if got, want := err.Error(), `invalid value for E2: "bogus"`; got != want {
+			t.Errorf("UnmarshalText(bogus): got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("Display", func(t *testing.T) {
+		for _, tc := range []struct {
+			v    testdata.E2
+			lang string
+			want string
+		}{
+			{testdata.E2_A, "en", "Apple"},
+			{testdata.E2_A, "de", "Apfel"},
+			{testdata.E2_A, "de-CH", "Apfel"},
+			{testdata.E2_A, "pt-BR", "Maçã"},
+			{testdata.E2_A, "pt", "Apple"},
+			{testdata.E2_A, "fr", "Apple"},
+			{testdata.E2_B, "de-AT", "Birne"},
+			{testdata.E2_B, "fr", "B"},
+			{testdata.E2_Invalid, "en", "<invalid>"},
+		} {
+			if got := tc.v.Display(tc.lang); got != tc.want {
+				t.Errorf("%v.Display(%q): got %q, want %q", tc.v, tc.lang, got, tc.want)
+			}
+		}
+	})
+
+	t.Run("Methods", func(t *testing.T) {
+		var v any = testdata.NotFound
+		if lv, ok := v.(interface{ Label() string }); !ok {
+			t.Error("Status does not have a Label method")
+		} else if got, want := lv.Label(), "NotFound"; got != want {
+			t.Errorf("Label: got %q, want %q", got, want)
+		}
+		if _, ok := v.(fmt.Stringer); ok {
+			t.Error("Status has a String method, but it should be renamed")
+		}
+		if _, ok := v.(interface{ Index() int }); ok {
+			t.Error("Status has an Index method, but it should be omitted")
+		}
+	})
+
+	t.Run("PointerRepresentation", func(t *testing.T) {
+		for _, v := range []any{testdata.A, testdata.G1} {
+			if k := reflect.TypeOf(v).Field(0).Type.Kind(); k != reflect.Pointer {
+				t.Errorf("%T: field kind is %v, want pointer", v, k)
+			}
+		}
+		if got, want := (testdata.E1{}).String(), "<invalid>"; got != want {
+			t.Errorf("Zero E1: got %q, want %q", got, want)
+		}
+		if (testdata.E1{}).Valid() {
+			t.Error("Zero E1 is valid")
+		}
+		vs, err := testdata.ParseE1List("bravo, alpha, C", "")
+		if err != nil {
+			t.Fatalf("ParseE1List: unexpected error: %v", err)
+		}
+		if want := []testdata.E1{testdata.B, testdata.A, testdata.C}; !slices.Equal(vs, want) {
+			t.Errorf("ParseE1List: got %v, want %v", vs, want)
+		}
+		m := map[testdata.E1]int{testdata.A: 1, testdata.B: 2}
+		if m[vs[1]] != 1 || m[vs[0]] != 2 || m[(testdata.E1{})] != 0 {
+			t.Errorf("Map lookup: got %d, %d, %d, want 1, 2, 0", m[vs[1]], m[vs[0]], m[testdata.E1{}])
+		}
+
+		var g testdata.Grouped
+		if err := g.UnmarshalText([]byte("second")); err != nil {
+			t.Fatalf("UnmarshalText: unexpected error: %v", err)
+		}
+		if g != testdata.G2 || g.Index() != 5 || g.Code() != 10 {
+			t.Errorf("Grouped: got %v (index %d, code %d), want G2 (index 5, code 10)", g, g.Index(), g.Code())
+		}
+		if got := testdata.GroupedFromIndex(5); got != testdata.G2 {
+			t.Errorf("GroupedFromIndex(5): got %v, want G2", got)
+		}
+		if got := (testdata.Grouped{}).Index(); got != 0 {
+			t.Errorf("Zero Grouped index: got %d, want 0", got)
+		}
+	})
+
+	t.Run("Interface", func(t *testing.T) {
+		for _, v := range []testdata.EnumType{testdata.A, testdata.Y, testdata.Two, testdata.H1} {
+			if !v.Valid() {
+				t.Errorf("%v: not valid", v)
+			}
+			if v.Enum() == "" || v.String() == "" {
+				t.Errorf("%v: empty Enum or String", v)
+			}
+		}
+		if _, ok := any(testdata.OK).(testdata.EnumType); ok {
+			t.Error("Status satisfies EnumType, but it should not")
+		}
+	})
+
+	t.Run("Predicates", func(t *testing.T) {
+		if !testdata.B.IsB() || testdata.B.IsA() || (testdata.E1{}).IsA() {
+			t.Error("E1 predicates: wrong result")
+		}
+		if !testdata.E2_Invalid.IsInvalid() || testdata.E2_A.IsInvalid() || !testdata.E2_A.IsA() {
+			t.Error("E2 predicates: wrong result")
+		}
+	})
+
+	t.Run("PackedStrings", func(t *testing.T) {
+		// E2, Status, and Grouped use packed string tables.
+		for _, tc := range []struct {
+			v    interface{ String() string }
+			want string
+		}{
+			{testdata.E2_A, "A"},
+			{testdata.E2_B, "B"},
+			{testdata.E2_Invalid, "<invalid>"},
+			{testdata.G1, "first"},
+			{testdata.G2, "second"},
+		} {
+			if got := tc.v.String(); got != tc.want {
+				t.Errorf("String: got %q, want %q", got, tc.want)
+			}
+		}
+		if got, want := testdata.StatusStrings(), []string{"OK", "NotFound", "Teapot"}; !slices.Equal(got, want) {
+			t.Errorf("StatusStrings: got %q, want %q", got, want)
+		}
+		if got := testdata.Teapot.Label(); got != "Teapot" {
+			t.Errorf("Label: got %q, want Teapot", got)
+		}
+		var v testdata.E2
+		if err := v.UnmarshalText([]byte("B")); err != nil || v != testdata.E2_B {
+			t.Errorf("UnmarshalText(B): got %v, %v; want %v, nil", v, err, testdata.E2_B)
+		}
+		if err := v.UnmarshalText([]byte("C")); err == nil {
+			t.Errorf("UnmarshalText(C): got %v, want error", v)
+		}
+	})
+
+	t.Run("MapLookup", func(t *testing.T) {
+		// Status is above the map threshold, so NewStatus uses a lookup map.
+		for _, tc := range []struct {
+			input string
+			want  testdata.Status
+		}{
+			{"OK", testdata.OK},
+			{"notfound", testdata.NotFound},
+			{"TEAPOT", testdata.Teapot},
+			{"Unknown", testdata.Unknown},
+			{"bogus", testdata.Unknown},
+			{"", testdata.Unknown},
+		} {
+			if got := testdata.NewStatus(tc.input); got != tc.want {
+				t.Errorf("NewStatus(%q): got %v, want %v", tc.input, got, tc.want)
+			}
+		}
+	})
+
+	t.Run("Attrs", func(t *testing.T) {
+		for _, tc := range []struct {
+			v      testdata.Status
+			reason string
+			retry  bool
+			weight float64
+		}{
+			{testdata.Unknown, "", false, 0},
+			{testdata.OK, "fine", false, 1},
+			{testdata.NotFound, "missing", false, 0.5},
+			{testdata.Teapot, "", true, 0},
+		} {
+			if got := tc.v.Reason(); got != tc.reason {
+				t.Errorf("%v.Reason(): got %q, want %q", tc.v, got, tc.reason)
+			}
+			if got := tc.v.Retry(); got != tc.retry {
+				t.Errorf("%v.Retry(): got %v, want %v", tc.v, got, tc.retry)
+			}
+			if got := tc.v.Weight(); got != tc.weight {
+				t.Errorf("%v.Weight(): got %v, want %v", tc.v, got, tc.weight)
+			}
+		}
+		if got := testdata.G1.Rank(); got != -3 {
+			t.Errorf("G1.Rank(): got %d, want -3", got)
+		}
+		if got := testdata.G2.Rank(); got != 0 {
+			t.Errorf("G2.Rank(): got %d, want 0", got)
+		}
+	})
+
+	t.Run("StringsAndNames", func(t *testing.T) {
+		if got, want := testdata.E3Strings(), []string{"foo", "bar"}; !slices.Equal(got, want) {
+			t.Errorf("E3Strings: got %q, want %q", got, want)
+		}
+		if got, want := testdata.E3Names(), []string{"X", "Y"}; !slices.Equal(got, want) {
+			t.Errorf("E3Names: got %q, want %q", got, want)
+		}
+		want := []string{"OK", "NotFound", "Teapot"}
+		if got := testdata.StatusStrings(); !slices.Equal(got, want) {
+			t.Errorf("StatusStrings: got %q, want %q", got, want)
+		}
+		names := testdata.StatusNames()
+		if !slices.Equal(names, want) {
+			t.Errorf("StatusNames: got %q, want %q", names, want)
+		}
+		names[0] = "changed"
+		if got := testdata.StatusNames(); !slices.Equal(got, want) {
+			t.Errorf("StatusNames after update: got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("DuplicateText", func(t *testing.T) {
+		if got := testdata.Grey.String(); got != "gray" {
+			t.Errorf("Grey: got %q, want gray", got)
+		}
+		// Parsing a shared string gives the first enumerator that has it.
+		if got, err := testdata.ParseAlias("gray"); err != nil || got != testdata.Gray {
+			t.Errorf("ParseAlias(gray): got (%v, %v), want %v", got, err, testdata.Gray)
+		}
+		var v testdata.Alias
+		if err := v.UnmarshalText([]byte("gray")); err != nil || v != testdata.Gray {
+			t.Errorf("UnmarshalText(gray): got (%v, %v), want %v", v, err, testdata.Gray)
+		}
+	})
+
+	t.Run("ExtraCode", func(t *testing.T) {
+		if got, want := testdata.Grey.Quoted(), `"gray"`; got != want {
+			t.Errorf("Quoted: got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("ByName", func(t *testing.T) {
+		for _, tc := range []struct {
+			name string
+			want testdata.Alias
+		}{
+			{"Gray", testdata.Gray},
+			{"Grey", testdata.Grey},
+			{"gray", testdata.Alias{}},
+			{"", testdata.Alias{}},
+		} {
+			if got := testdata.AliasByName(tc.name); got != tc.want {
+				t.Errorf("AliasByName(%q): got %v, want %v", tc.name, got, tc.want)
+			}
+		}
+		if got, want := testdata.Grey.Name(), "Grey"; got != want {
+			t.Errorf("Name: got %q, want %q", got, want)
+		}
+		if got := (testdata.Alias{}).Name(); got != "" {
+			t.Errorf("Name of zero: got %q, want empty", got)
+		}
+	})
+
+	t.Run("Completions", func(t *testing.T) {
+		for _, tc := range []struct {
+			got, want []string
+		}{
+			{testdata.E3Completions(""), []string{"foo", "bar"}},
+			{testdata.E3Completions("b"), []string{"bar"}},
+			{testdata.E3Completions("baz"), nil},
+			{testdata.StatusCompletions("N"), []string{"NotFound"}},
+			{testdata.StatusCompletions("T"), []string{"Teapot"}},
+		} {
+			if !slices.Equal(tc.got, tc.want) {
+				t.Errorf("Completions: got %q, want %q", tc.got, tc.want)
+			}
+		}
+	})
+
+	t.Run("E3Query", func(t *testing.T) {
+		q := make(url.Values)
+		testdata.X.SetQuery(q, "e3")
+		if got := q.Encode(); got != "e3=foo" {
+			t.Errorf("SetQuery: got %q, want %q", got, "e3=foo")
+		}
+		if got, err := testdata.E3FromQuery(q, "e3"); err != nil || got != testdata.X {
+			t.Errorf("E3FromQuery(e3): got (%v, %v), want (%v, nil)", got, err, testdata.X)
+		}
+		if got, err := testdata.E3FromQuery(q, "other"); err != nil || got.Valid() {
+			t.Errorf("E3FromQuery(other): got (%v, %v), want zero", got, err)
+		}
+		q.Set("bad", "nonesuch")
+		if got, err := testdata.E3FromQuery(q, "bad"); err == nil {
+			t.Errorf("E3FromQuery(bad): got %v, want error", got)
+		}
+	})
+
+	t.Run("E1Format", func(t *testing.T) {
+		for _, tc := range []struct {
+			format string
+			want   string
+		}{
+			{"%s", "alpha"},
+			{"%q", `"alpha"`},
+			{"%8s|", "   alpha|"},
+			{"%d", "1"},
+			{"%03d", "001"},
+			{"%v", "E1(alpha)"},
+			{"%+v", "E1(alpha)"},
+			{"%x", "%!x(E1=alpha)"},
+		} {
+			if got := fmt.Sprintf(tc.format, testdata.A); got != tc.want {
+				t.Errorf("Sprintf(%q, A): got %q, want %q", tc.format, got, tc.want)
+			}
+		}
+	})
+
+	t.Run("E1ArrayIndex", func(t *testing.T) {
+		var names [testdata.NumE1]string
+		for i, e := range []testdata.E1{testdata.A, testdata.B, testdata.C} {
+			if got := e.AsArrayIndex(); got != i {
+				t.Errorf("AsArrayIndex for %v: got %d, want %d", e, got, i)
+			}
+			names[e.AsArrayIndex()] = e.String()
+		}
+		if want := [...]string{"alpha", "bravo", "C"}; names != want {
+			t.Errorf("Names: got %q, want %q", names, want)
+		}
+		mtest.MustPanic(t, func() { testdata.E1{}.AsArrayIndex() })
+	})
+
 	t.Run("E2Map", func(t *testing.T) {
 		// Verify that enumerators work as map keys.
 		m := map[testdata.E2]bool{
@@ -122,9 +696,55 @@ func TestEnums(t *testing.T) {
 		}
 	})
 
+	t.Run("E2Groups", func(t *testing.T) {
+		for _, tc := range []struct {
+			v          testdata.E2
+			first, all bool
+		}{
+			{testdata.E2_Invalid, false, false},
+			{testdata.E2_A, true, true},
+			{testdata.E2_B, false, true},
+		} {
+			if got := tc.v.IsFirst(); got != tc.first {
+				t.Errorf("%v.IsFirst(): got %v, want %v", tc.v, got, tc.first)
+			}
+			if got := tc.v.IsAll(); got != tc.all {
+				t.Errorf("%v.IsAll(): got %v, want %v", tc.v, got, tc.all)
+			}
+		}
+		if want := []testdata.E2{testdata.E2_B, testdata.E2_A}; !slices.Equal(testdata.E2All, want) {
+			t.Errorf("E2All: got %v, want %v", testdata.E2All, want)
+		}
+		if want := []testdata.E2{testdata.E2_A}; !slices.Equal(testdata.E2First, want) {
+			t.Errorf("E2First: got %v, want %v", testdata.E2First, want)
+		}
+	})
+
+	t.Run("E2Providers", func(t *testing.T) {
+		if got := testdata.ProvideDefaultE2(); got != testdata.E2_B {
+			t.Errorf("ProvideDefaultE2: got %v, want %v", got, testdata.E2_B)
+		}
+		for _, tc := range []struct {
+			env  string
+			want testdata.E2
+			ok   bool
+		}{
+			{"", testdata.E2_B, true},
+			{"a", testdata.E2_A, true},
+			{"B", testdata.E2_B, true},
+			{"nonesuch", testdata.E2_Invalid, false},
+		} {
+			t.Setenv("ENUMGEN_TEST_E2", tc.env)
+			got, err := testdata.ProvideE2FromEnv()
+			if got != tc.want || (err == nil) != tc.ok {
+				t.Errorf("ProvideE2FromEnv [%q]: got (%v, %v), want %v", tc.env, got, err, tc.want)
+			}
+		}
+	})
+
 	t.Run("E3Flag", func(t *testing.T) {
 		var target testdata.E3
-		check(t, target, false, "<invalid>")
+		check(t, target, false, "none")
 
 		var _ flag.Value = &target
 
@@ -149,6 +769,50 @@ func TestEnums(t *testing.T) {
 		var _ encoding.TextMarshaler = testdata.E3{}
 		var _ encoding.TextUnmarshaler = (*testdata.E3)(nil)
 
+		t.Run("Append", func(t *testing.T) {
+			buf := make([]byte, 0, 64)
+			got, err := testdata.X.AppendText(append(buf, "v="...))
+			if err != nil || string(got) != "v="+testdata.X.String() {
+				t.Errorf("AppendText: got %q, %v; want %q", got, err, "v="+testdata.X.String())
+			}
+			if n := testing.AllocsPerRun(100, func() { buf, _ = testdata.X.AppendText(buf[:0]) }); n != 0 {
+				t.Errorf("AppendText: got %v allocations, want 0", n)
+			}
+		})
+
+		t.Run("Marshal", func(t *testing.T) {
+			for _, v := range []interface {
+				encoding.TextMarshaler
+				String() string
+			}{
+				testdata.A, testdata.E1{}, // pointer
+				testdata.E2_B, testdata.E2{}, // packed
+				testdata.X, testdata.E3{}, // runtime
+				testdata.G1, testdata.Grouped{}, // grouped, pointer, packed, runtime
+				testdata.Ebb, testdata.Tide{}, // slice
+			} {
+				got, err := v.MarshalText()
+				if err != nil || string(got) != v.String() {
+					t.Errorf("MarshalText: got %q, %v; want %q", got, err, v.String())
+				}
+				if len(got) != cap(got) {
+					t.Errorf("MarshalText %q: got capacity %d, want %d", got, cap(got), len(got))
+				}
+				if n := testing.AllocsPerRun(100, func() { v.MarshalText() }); n != 0 {
+					t.Errorf("MarshalText %q: got %v allocations, want 0", got, n)
+				}
+			}
+
+			// An unknown string kept by UnmarshalText is not in the table.
+			var v testdata.E1
+			if err := v.UnmarshalText([]byte("mystery")); err != nil {
+				t.Fatalf("UnmarshalText: unexpected error: %v", err)
+			}
+			if got, err := v.MarshalText(); err != nil || string(got) != "mystery" {
+				t.Errorf("MarshalText: got %q, %v; want %q", got, err, "mystery")
+			}
+		})
+
 		t.Run("Good", func(t *testing.T) {
 			var target testdata.E3
 
@@ -178,10 +842,12 @@ func TestEnums(t *testing.T) {
 				t.Error("Decoded empty incorrectly reports valid")
 			}
 
-			// The <invalid> label should decode to the zero enumerator.
+			// The invalid-text label should decode to the zero enumerator.
 			bits, err := json.Marshal(testdata.E3{})
 			if err != nil {
 				t.Fatalf("Marshal %v failed: %v", testdata.E3{}, err)
+			} else if got := string(bits); got != `"none"` {
+				t.Errorf("Marshal %v: got %s, want %q", testdata.E3{}, got, "none")
 			}
 			if err := json.Unmarshal(bits, &target); err != nil {
 				t.Fatalf("Unmarshal failed; %v", err)
@@ -203,6 +869,34 @@ func TestEnums(t *testing.T) {
 		})
 	})
 
+	t.Run("E3XML", func(t *testing.T) {
+		type doc struct {
+			Attr testdata.E3 `xml:"attr,attr"`
+			Elt  testdata.E3 `xml:"elt"`
+		}
+		bits, err := xml.Marshal(doc{Attr: testdata.X, Elt: testdata.Y})
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		const want = `<doc attr="foo"><elt>bar</elt></doc>`
+		if got := string(bits); got != want {
+			t.Errorf("Marshal: got %s, want %s", got, want)
+		}
+
+		var dec doc
+		if err := xml.Unmarshal(bits, &dec); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if dec.Attr != testdata.X || dec.Elt != testdata.Y {
+			t.Errorf("Unmarshal: got %+v, want {%v %v}", dec, testdata.X, testdata.Y)
+		}
+
+		const bad = `<doc attr="nonesuch"></doc>`
+		if err := xml.Unmarshal([]byte(bad), &dec); err == nil {
+			t.Errorf("Unmarshal %s: got %+v, want error", bad, dec)
+		}
+	})
+
 	t.Run("E3FromIndex", func(t *testing.T) {
 		var zero testdata.E3
 		tests := []struct {
@@ -245,40 +939,311 @@ func TestEnums(t *testing.T) {
 		}
 	})
 
-	t.Run("ColorFlag", func(t *testing.T) {
-		const redText = "fire-engine-red"
-		color := testdata.Red
-		if got := color.String(); got != redText {
-			t.Errorf("Red: got %q, want %q", got, redText)
+	t.Run("ParseList", func(t *testing.T) {
+		got, err := testdata.ParseE1List(" alpha, C,,alpha ", "")
+		if err != nil {
+			t.Fatalf("ParseE1List: unexpected error: %v", err)
+		}
+		want := []testdata.E1{testdata.A, testdata.C, testdata.A}
+		if !slices.Equal(got, want) {
+			t.Errorf("ParseE1List: got %v, want %v", got, want)
+		}
+		if got, err := testdata.ParseE1List("", ","); err != nil || len(got) != 0 {
+			t.Errorf("ParseE1List(empty): got %v, %v; want empty, nil", got, err)
+		}
+		if got, err := testdata.ParseE1List("alpha|bogus", "|"); err == nil {
+			t.Errorf("ParseE1List(bogus): got %v, want error", got)
 		}
-		var _ flag.Value = &color
-	})
-}
 
-func TestErrors(t *testing.T) {
-	tests := []struct {
-		desc   string
-		config *gen.Config
-	}{
-		{"package name not defined", &gen.Config{}},
-		{"no enumerations defined", &gen.Config{Package: "foo"}},
-		{"type name not defined", &gen.Config{
-			Package: "foo",
-			Enum:    []*gen.Enum{{}},
-		}},
-		{"no enumerators defined", &gen.Config{
-			Package: "foo",
-			Enum:    []*gen.Enum{{Type: "bar"}},
-		}},
-		{"name not defined", &gen.Config{
-			Package: "foo",
-			Enum: []*gen.Enum{{Type: "bar", Values: []*gen.Value{
-				{},
-			}}},
-		}},
+		if got, err := testdata.ParseE3List("foo|bar", "|"); err != nil {
+			t.Errorf("ParseE3List: unexpected error: %v", err)
+		} else if want := []testdata.E3{testdata.X, testdata.Y}; !slices.Equal(got, want) {
+			t.Errorf("ParseE3List: got %v, want %v", got, want)
+		}
+		if got, err := testdata.ParseE3List("foo,bar,foo", ","); err == nil {
+			t.Errorf("ParseE3List(dup): got %v, want error", got)
+		}
+	})
 
-		// Check for enumerator duplication within an enum.
-		{`name "baz" duplicated in "bar"`, &gen.Config{
+	t.Run("Naming", func(t *testing.T) {
+		check(t, testdata.Hashed{}, false, "<invalid>")
+		check(t, testdata.NewHashed("h2"), true, "H2")
+		if got := testdata.H2.Index(); got != 4 {
+			t.Errorf("H2.Index: got %d, want 4", got)
+		}
+		if got, err := testdata.ParseHashed("H1"); err != nil || got != testdata.H1 {
+			t.Errorf("ParseHashed(H1): got (%v, %v), want (%v, nil)", got, err, testdata.H1)
+		}
+		if got, err := testdata.ParseHashed("nonesuch"); err == nil {
+			t.Errorf("ParseHashed(nonesuch): got %v, want error", got)
+		}
+		if got := testdata.MustHashed("h1"); got != testdata.H1 {
+			t.Errorf("MustHashed(h1): got %v, want %v", got, testdata.H1)
+		}
+		mtest.MustPanic(t, func() { testdata.MustHashed("nonesuch") })
+
+		check(t, testdata.Grouped{}, false, "<invalid>")
+		check(t, testdata.G1, true, "first")
+		if got := testdata.GroupedFromIndex(5); got != testdata.G2 {
+			t.Errorf("GroupedFromIndex(5): got %v, want %v", got, testdata.G2)
+		}
+		var g testdata.Grouped
+		if err := g.UnmarshalText([]byte("second")); err != nil {
+			t.Errorf("UnmarshalText: unexpected error: %v", err)
+		} else if g != testdata.G2 {
+			t.Errorf("UnmarshalText: got %v, want %v", g, testdata.G2)
+		}
+	})
+
+	t.Run("GroupedSQL", func(t *testing.T) {
+		var _ driver.Valuer = testdata.G1
+		var _ sql.Scanner = (*testdata.Grouped)(nil)
+
+		if v, err := testdata.G2.Value(); err != nil || v != "second" {
+			t.Errorf("G2.Value: got %v, %v; want second, nil", v, err)
+		}
+		if v, err := (testdata.Grouped{}).Value(); err != nil || v != nil {
+			t.Errorf("Zero.Value: got %v, %v; want nil, nil", v, err)
+		}
+
+		var g testdata.Grouped
+		for _, src := range []any{"first", []byte("first")} {
+			if err := g.Scan(src); err != nil {
+				t.Errorf("Scan(%v): unexpected error: %v", src, err)
+			} else if g != testdata.G1 {
+				t.Errorf("Scan(%v): got %v, want %v", src, g, testdata.G1)
+			}
+		}
+		if err := g.Scan(nil); err != nil || g.Valid() {
+			t.Errorf("Scan(nil): got %v, %v; want zero, nil", g, err)
+		}
+		for _, src := range []any{"bogus", 25} {
+			if err := g.Scan(src); err == nil {
+				t.Errorf("Scan(%v): got %v, want error", src, g)
+			}
+		}
+	})
+
+	t.Run("Sort", func(t *testing.T) {
+		// The enumerators are ordered by their strings, but keep the indices
+		// assigned in the order of the config.
+		if got, want := testdata.SortedStrings(), []string{"xigua", "yam", "zucchini"}; !slices.Equal(got, want) {
+			t.Errorf("SortedStrings: got %q, want %q", got, want)
+		}
+		for _, tc := range []struct {
+			v     testdata.Sorted
+			index int
+		}{
+			{testdata.Apple, 1}, {testdata.Banana, 7}, {testdata.Cherry, 8},
+		} {
+			if got := tc.v.Index(); got != tc.index {
+				t.Errorf("%v.Index(): got %d, want %d", tc.v, got, tc.index)
+			}
+			if got := testdata.SortedFromIndex(tc.index); got != tc.v {
+				t.Errorf("SortedFromIndex(%d): got %v, want %v", tc.index, got, tc.v)
+			}
+		}
+
+		cfg := &gen.Config{Package: "foo", Enum: []*gen.Enum{{
+			Type: "Mood",
+			Sort: "index",
+			Values: []*gen.Value{
+				{Name: "Sad", Index: ptr(5)}, {Name: "Happy", Index: ptr(2)},
+			},
+		}}}
+		var buf bytes.Buffer
+		if err := cfg.Generate(&buf); err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if got := buf.String(); !strings.Contains(got, "_idx_Mood = []int{0, 2, 5}") {
+			t.Errorf("Generate: enumerators not sorted by index:\n%s", got)
+		}
+	})
+
+	t.Run("UnmarshalUnknown", func(t *testing.T) {
+		// With zero, an unknown string decodes to the zero value.
+		s := testdata.Cherry
+		if err := s.UnmarshalText([]byte("kumquat")); err != nil || s != (testdata.Sorted{}) {
+			t.Errorf("Sorted UnmarshalText: got (%v, %v), want zero", s, err)
+		}
+
+		// With keep-text, an unknown string is retained, but is not valid.
+		var a, b testdata.E1
+		if err := a.UnmarshalText([]byte("charlie")); err != nil {
+			t.Fatalf("E1 UnmarshalText: unexpected error: %v", err)
+		}
+		if a.Valid() || a == (testdata.E1{}) {
+			t.Errorf("E1 unknown: got valid=%v, want an invalid non-zero value", a.Valid())
+		}
+		if text, err := a.MarshalText(); err != nil || string(text) != "charlie" {
+			t.Errorf("E1 MarshalText: got (%q, %v), want charlie", text, err)
+		}
+		if err := b.UnmarshalText([]byte("charlie")); err != nil || a != b {
+			t.Errorf("E1 UnmarshalText: got %v, want equal to %v", b, a)
+		}
+		if a.Index() != (testdata.E1{}).Index() {
+			t.Errorf("E1 unknown: got index %d, want %d", a.Index(), (testdata.E1{}).Index())
+		}
+		if err := b.UnmarshalText([]byte("bravo")); err != nil || b != testdata.B {
+			t.Errorf("E1 UnmarshalText(bravo): got (%v, %v), want %v", b, err, testdata.B)
+		}
+
+		// The runtime implementation applies the policy to the errors of the
+		// enum package.
+		cfg := &gen.Config{Package: "foo", Enum: []*gen.Enum{{
+			Type:             "Mood",
+			Runtime:          true,
+			TextMarshal:      true,
+			Representation:   "pointer",
+			UnmarshalUnknown: "keep-text",
+			Values:           []*gen.Value{{Name: "Happy"}, {Name: "Sad"}},
+		}}}
+		var buf bytes.Buffer
+		if err := cfg.Generate(&buf); err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if got := buf.String(); !strings.Contains(got, "*v = unknownMood(string(data))") {
+			t.Errorf("Generate: runtime UnmarshalText does not keep unknown text:\n%s", got)
+		}
+	})
+
+	t.Run("Choices", func(t *testing.T) {
+		got := testdata.StatusChoices(testdata.NotFound, testdata.Unknown)
+		want := []testdata.StatusChoice{
+			{Value: testdata.OK, Text: "OK"},
+			{Value: testdata.NotFound, Text: "NotFound", Selected: true},
+			{Value: testdata.Teapot, Text: "Teapot"},
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("StatusChoices: got %+v, want %+v", got, want)
+		}
+
+		// The choices can be rendered by a template, for a pointer
+		// representation as well.
+		tmpl := template.Must(template.New("select").Funcs(template.FuncMap{
+			"E1Choices": testdata.E1Choices,
+		}).Parse(`{{range E1Choices .}}<option value="{{.Text}}"{{if .Selected}} selected{{end}}>{{end}}`))
+		var buf strings.Builder
+		if err := tmpl.Execute(&buf, testdata.B); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		const wantHTML = `<option value="alpha"><option value="bravo" selected><option value="C">`
+		if got := buf.String(); got != wantHTML {
+			t.Errorf("Template output: got %q, want %q", got, wantHTML)
+		}
+	})
+
+	t.Run("CBOR", func(t *testing.T) {
+		for _, tc := range []struct {
+			v    interface{ MarshalCBOR() ([]byte, error) }
+			want string
+		}{
+			{testdata.OK, "\x62OK"},
+			{testdata.NotFound, "\x68NotFound"},
+			{testdata.Unknown, "\xf6"},
+			{testdata.G1, "\x33"}, // code -20
+			{testdata.G2, "\x0a"}, // code 10
+			{testdata.Grouped{}, "\xf6"},
+		} {
+			got, err := tc.v.MarshalCBOR()
+			if err != nil || string(got) != tc.want {
+				t.Errorf("%v.MarshalCBOR(): got %q, %v; want %q", tc.v, got, err, tc.want)
+			}
+		}
+
+		var st testdata.Status
+		for _, tc := range []struct {
+			data string
+			want testdata.Status
+		}{
+			{"\x66Teapot", testdata.Teapot},
+			{"\x78\x02OK", testdata.OK}, // non-minimal length
+			{"\xf6", testdata.Unknown},
+			{"\x60", testdata.Unknown},
+		} {
+			if err := st.UnmarshalCBOR([]byte(tc.data)); err != nil || st != tc.want {
+				t.Errorf("UnmarshalCBOR(%q): got %v, %v; want %v", tc.data, st, err, tc.want)
+			}
+		}
+		for _, bad := range []string{"", "\x65bogus", "\x18\xc8", "\x63OK", "\x7f\x62OK\xff"} {
+			if err := st.UnmarshalCBOR([]byte(bad)); err == nil {
+				t.Errorf("UnmarshalCBOR(%q): got %v, want error", bad, st)
+			}
+		}
+
+		var g testdata.Grouped
+		for _, tc := range []struct {
+			data string
+			want testdata.Grouped
+		}{
+			{"\x33", testdata.G1},
+			{"\x19\x00\x0a", testdata.G2}, // non-minimal argument
+			{"\xf7", testdata.Grouped{}},
+		} {
+			if err := g.UnmarshalCBOR([]byte(tc.data)); err != nil || g != tc.want {
+				t.Errorf("UnmarshalCBOR(%q): got %v, %v; want %v", tc.data, g, err, tc.want)
+			}
+		}
+		for _, bad := range []string{"\x0b", "\x18\xc8", "\x66second", "\x0a\x00", "\x19\x00"} {
+			if err := g.UnmarshalCBOR([]byte(bad)); err == nil {
+				t.Errorf("UnmarshalCBOR(%q): got %v, want error", bad, g)
+			}
+		}
+	})
+
+	t.Run("ColorFlag", func(t *testing.T) {
+		const redText = "fire-engine-red"
+		color := testdata.Red
+		if got := color.String(); got != redText {
+			t.Errorf("Red: got %q, want %q", got, redText)
+		}
+		var _ flag.Value = &color
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		cp := testdata.ColorFlag(fs, "color", testdata.Green, "the color")
+		if *cp != testdata.Green {
+			t.Errorf("ColorFlag default: got %v, want %v", *cp, testdata.Green)
+		}
+		const wantUsage = "the color (one of: fire-engine-red, scummy-green, azure-sky-blue)"
+		if f := fs.Lookup("color"); f == nil || f.Usage != wantUsage || f.DefValue != "scummy-green" {
+			t.Errorf("ColorFlag: got flag %+v, want usage %q and default scummy-green", f, wantUsage)
+		}
+		if err := fs.Parse([]string{"-color", redText}); err != nil {
+			t.Fatalf("Parse: unexpected error: %v", err)
+		} else if *cp != testdata.Red {
+			t.Errorf("ColorFlag: got %v, want %v", *cp, testdata.Red)
+		}
+		if err := fs.Parse([]string{"-color", "mauve"}); err == nil {
+			t.Error("Parse: got nil, want error for an invalid color")
+		}
+	})
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		desc   string
+		config *gen.Config
+	}{
+		{"package name not defined", &gen.Config{}},
+		{"no enumerations defined", &gen.Config{Package: "foo"}},
+		{"type name not defined", &gen.Config{
+			Package: "foo",
+			Enum:    []*gen.Enum{{}},
+		}},
+		{"no enumerators defined", &gen.Config{
+			Package: "foo",
+			Enum:    []*gen.Enum{{Type: "bar"}},
+		}},
+		{"name not defined", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{{Type: "bar", Values: []*gen.Value{
+				{},
+			}}},
+		}},
+
+		// Check for enumerator duplication within an enum.
+		{`name "baz" duplicated in "bar"`, &gen.Config{
 			Package: "foo",
 			Enum: []*gen.Enum{{Type: "bar", Values: []*gen.Value{
 				{Name: "baz"}, {Name: "baz"},
@@ -334,16 +1299,1993 @@ func TestErrors(t *testing.T) {
 				{Type: "baz", Zero: "Y", Values: []*gen.Value{{Name: "Z"}}},
 			},
 		}},
-	}
-	for _, test := range tests {
-		t.Run(test.desc, func(t *testing.T) {
-			var buf bytes.Buffer
-			err := test.config.Generate(&buf)
-			if err == nil {
-				t.Errorf("Test %s: expected error, got\n%s", test.desc, buf.String())
-			} else if !strings.Contains(err.Error(), test.desc) {
-				t.Errorf("Test %s: error does not match: %v", test.desc, err)
-			}
-		})
+
+		// Check for invalid option settings.
+		{"proto type not defined", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Proto: &gen.ProtoEnum{}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`proto constant pb.Bar_X of "Y" duplicates "X"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Proto: &gen.ProtoEnum{Type: "pb.Bar", Values: map[string]string{"Y": "Bar_X"}},
+					Values: []*gen.Value{{Name: "X"}, {Name: "Y"}}},
+			},
+		}},
+		{`imported as both "apb" and "bpb"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Proto: &gen.ProtoEnum{Type: "apb.Bar", Import: "x/pb"}, Values: []*gen.Value{{Name: "X"}}},
+				{Type: "baz", Proto: &gen.ProtoEnum{Type: "bpb.Baz", Import: "x/pb"}, Values: []*gen.Value{{Name: "Y"}}},
+			},
+		}},
+		{`invalid-text conflicts with text of zero enumerator "X"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Zero: "X", InvalidText: "?", Values: []*gen.Value{{Name: "X", Text: "x"}}},
+			},
+		}},
+		{`unknown fixed-width type "int16"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", FixedWidth: "int16", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"fixed-width uint8 cannot represent 256 enumerators", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", FixedWidth: "uint8", Values: func() (vs []*gen.Value) {
+					for i := range 256 {
+						vs = append(vs, &gen.Value{Name: fmt.Sprintf("X%d", i)})
+					}
+					return
+				}()},
+			},
+		}},
+		{`index 300 of "Y" does not fit in fixed-width uint8`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", FixedWidth: "uint8", Values: []*gen.Value{{Name: "X"}, {Name: "Y", Index: ptr(300)}}},
+			},
+		}},
+		{`index -1 of "X" does not fit in fixed-width uint16`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", FixedWidth: "uint16", Values: []*gen.Value{{Name: "X", Index: ptr(-1)}}},
+			},
+		}},
+		{`registry conflicts with enumeration type "Enums"`, &gen.Config{
+			Package:  "foo",
+			Registry: true,
+			Enum: []*gen.Enum{
+				{Type: "Enums", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`code 200 of "Y" duplicates "X"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Values: []*gen.Value{{Name: "X", Code: ptr(200)}, {Name: "Y", Code: ptr(200)}}},
+			},
+		}},
+		{`code not defined for "Y"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Values: []*gen.Value{{Name: "X", Code: ptr(200)}, {Name: "Y"}}},
+			},
+		}},
+		{`code -1 of "X" overflows uint8`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", CodeType: "uint8", Values: []*gen.Value{{Name: "X", Code: ptr(-1)}}},
+			},
+		}},
+		{`zero enumerator "X" cannot have a code`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Zero: "X", Values: []*gen.Value{{Name: "X", Code: ptr(1)}, {Name: "Y", Code: ptr(2)}}},
+			},
+		}},
+		{"code-type requires enumerator codes", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", CodeType: "int", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"when is not resolved", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Values: []*gen.Value{{Name: "X", When: "beta"}}},
+			},
+		}},
+		{"invalid when condition", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Values: []*gen.Value{{Name: "X", When: "beta ||"}}},
+			},
+		}},
+		{"doc-file is not resolved", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Values: []*gen.Value{{Name: "X", DocFile: "x.md"}}},
+			},
+		}},
+		{"doc-file conflicts with doc", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Doc: "Bar.", DocFile: "bar.md", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`former index 2 maps to unknown enumerator "Z"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Migrate: map[int]string{2: "Z"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`group "Warm" has unknown enumerator "Z"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Groups: map[string][]string{"Warm": {"X", "Z"}}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`invalid group name "warm"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Groups: map[string][]string{"warm": {"X"}}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`unknown parse-error kind "panic"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", ParseError: "panic", TextMarshal: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"parse-error conflicts with runtime", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", ParseError: "struct", Runtime: true, TextMarshal: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"parse-error requires a function that reports parse errors", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", ParseError: "sentinel", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"invalid map threshold -1", &gen.Config{
+			Package:      "foo",
+			MapThreshold: -1,
+			Enum:         []*gen.Enum{{Type: "bar", Values: []*gen.Value{{Name: "X"}}}},
+		}},
+		{"interface-name requires emit-interface", &gen.Config{
+			Package:       "foo",
+			InterfaceName: "Enumeration",
+			Enum:          []*gen.Enum{{Type: "bar", Values: []*gen.Value{{Name: "X"}}}},
+		}},
+		{`invalid interface name "not valid"`, &gen.Config{
+			Package:       "foo",
+			EmitInterface: true,
+			InterfaceName: "not valid",
+			Enum:          []*gen.Enum{{Type: "bar", Values: []*gen.Value{{Name: "X"}}}},
+		}},
+		{`interface "EnumType" conflicts with enumeration type`, &gen.Config{
+			Package:       "foo",
+			EmitInterface: true,
+			Enum:          []*gen.Enum{{Type: "EnumType", Values: []*gen.Value{{Name: "X"}}}},
+		}},
+		{`unknown representation "string"`, &gen.Config{
+			Package: "foo",
+			Enum:    []*gen.Enum{{Type: "bar", Representation: "string", Values: []*gen.Value{{Name: "X"}}}},
+		}},
+		{`predicate IsWarm for "Warm" conflicts with group "Warm"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{{
+				Type: "bar", Predicates: true,
+				Groups: map[string][]string{"Warm": {"X"}},
+				Values: []*gen.Value{{Name: "X"}, {Name: "Warm"}},
+			}},
+		}},
+		{`predicate IsX for "x" conflicts with enumerator "X"`, &gen.Config{
+			Package: "foo",
+			Enum:    []*gen.Enum{{Type: "bar", Predicates: true, Values: []*gen.Value{{Name: "X"}, {Name: "x"}}}},
+		}},
+		{`unknown string table "compact"`, &gen.Config{
+			Package: "foo",
+			Enum:    []*gen.Enum{{Type: "bar", StringTable: "compact", Values: []*gen.Value{{Name: "X"}}}},
+		}},
+		{"fixed-width conflicts with pointer representation", &gen.Config{
+			Package: "foo",
+			Enum:    []*gen.Enum{{Type: "bar", Representation: "pointer", FixedWidth: "uint16", Values: []*gen.Value{{Name: "X"}}}},
+		}},
+		{`unknown method "code"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Methods: map[string]string{"code": "false"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`invalid name "not-valid" for method "valid"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Methods: map[string]string{"valid": "not-valid"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`cannot omit method "string" used by text-marshal`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Methods: map[string]string{"string": "false"}, TextMarshal: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`cannot rename method "valid" with runtime`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Methods: map[string]string{"valid": "OK"}, Runtime: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`methods "index" and "string" have the same name "Name"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Methods: map[string]string{"index": "Name", "string": "Name"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`unknown name style "kebab"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", NameStyle: "kebab", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`invalid variable name "X-y" for "X"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Suffix: "-y", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`name "BarX" duplicated in "bar"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", NameStyle: "exported", Prefix: "bar", Values: []*gen.Value{{Name: "X"}, {Name: "x"}}},
+			},
+		}},
+		{"display-default requires display names", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", DisplayDefault: "en", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`empty display language for "X"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Values: []*gen.Value{{Name: "X", Display: map[string]string{"": "x"}}}},
+			},
+		}},
+		{`attribute "hex" has unsupported type "color"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Attrs: map[string]string{"hex": "color"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`invalid attribute name "no-way"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Attrs: map[string]string{"no-way": "int"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`attribute "string" conflicts with method String`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Attrs: map[string]string{"string": "int"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`unknown attribute "hex" for "X"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Values: []*gen.Value{{Name: "X", Attrs: map[string]any{"hex": "#fff"}}}},
+			},
+		}},
+		{`attribute "n" of "X": invalid int value 1.5`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Attrs: map[string]string{"n": "int"}, Values: []*gen.Value{
+					{Name: "X", Attrs: map[string]any{"n": 1.5}},
+				}},
+			},
+		}},
+		{"index-base must be 0 or 1, not 2", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", IndexBase: ptr(2), Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"invalid former index 0", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", IndexBase: ptr(1), Migrate: map[int]string{0: "X"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"query requires text-marshal", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Query: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`text "x" of "B" duplicates "A"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Values: []*gen.Value{{Name: "A", Text: "x"}, {Name: "B", Text: "x"}}},
+			},
+		}},
+		{`text "A" of "B" duplicates "A"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Values: []*gen.Value{{Name: "A"}, {Name: "B", Text: "A"}}},
+			},
+		}},
+		{`text "?" of "A" duplicates the zero value`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", InvalidText: "?", Values: []*gen.Value{{Name: "A", Text: "?"}}},
+			},
+		}},
+		{`invalid import "a b c"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", ExtraImports: []string{"strconv", "a b c"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"invalid Go declarations: extra-code:2:", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", ExtraCode: "func (bar) OK() {}\nfunc Bad( {}\n", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"invalid unexported type name", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "9bar", Unexported: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"duplicate type name \"bar\"", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Values: []*gen.Value{{Name: "X"}}},
+				{Type: "Bar", Unexported: true, Values: []*gen.Value{{Name: "Y"}}},
+			},
+		}},
+		{`by-name conflicts with method "string"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", ByName: true, Methods: map[string]string{"string": "Name"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`by-name conflicts with attribute "name"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", ByName: true, Attrs: map[string]string{"name": "string"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`invalid receiver name "2b"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Receiver: "2b", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`receiver name "s" conflicts`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Receiver: "s", FlagValue: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`receiver name "c" conflicts`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Receiver: "c", CBOR: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`receiver name "len" conflicts`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Receiver: "len", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`receiver name "x" conflicts`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Receiver: "x", Unexported: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"flag-func requires flag-value", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", FlagFunc: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"flag-func requires a method named String", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", FlagValue: true, FlagFunc: true, Methods: map[string]string{"string": "Label"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"code-consts conflicts with sql", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", CodeConsts: true, SQL: true, Values: []*gen.Value{{Name: "X", Code: ptr(1)}}},
+			},
+		}},
+		{`attribute "value" conflicts with method Value`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", CodeConsts: true, Attrs: map[string]string{"value": "int"}, Values: []*gen.Value{{Name: "X", Code: ptr(1)}}},
+			},
+		}},
+		{"code-consts requires enumerator codes", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", CodeConsts: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`variable name "OKCode" for "OKCode" conflicts with the code constant of "OK"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "Bar", CodeConsts: true, Values: []*gen.Value{{Name: "OK", Code: ptr(200)}, {Name: "OKCode", Code: ptr(201)}}},
+			},
+		}},
+		{"lazy requires enumerator codes", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Lazy: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"invalid build tags", &gen.Config{
+			Package:   "foo",
+			BuildTags: "linux &&",
+			Enum: []*gen.Enum{
+				{Type: "bar", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"cbor-code requires cbor", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", CBORCode: true, Values: []*gen.Value{{Name: "X", Code: ptr(1)}}},
+			},
+		}},
+		{"cbor-code requires enumerator codes", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", CBOR: true, CBORCode: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`cannot omit method "string" used by flag-value`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", FlagValue: true, Methods: map[string]string{"string": "false"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`cannot rename method "string" with flag-value`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", FlagValue: true, Methods: map[string]string{"string": "Label"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`cannot omit method "valid" used by cbor`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", CBOR: true, Methods: map[string]string{"valid": "false"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`unknown name-policy style "pascal"`, &gen.Config{
+			Package:    "foo",
+			NamePolicy: &gen.NamePolicy{Style: "pascal"},
+			Enum: []*gen.Enum{
+				{Type: "bar", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"invalid name-policy pattern", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", NamePolicy: &gen.NamePolicy{Pattern: "[A-Z"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`invalid variable name "3D" for "3D"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Values: []*gen.Value{{Name: "X"}, {Name: "3D"}}},
+			},
+		}},
+		{`variable name "nil" for "nil" shadows a predeclared identifier`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Values: []*gen.Value{{Name: "nil"}}},
+			},
+		}},
+		{`invalid variable name "_" for "_"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Values: []*gen.Value{{Name: "_"}}},
+			},
+		}},
+		{`variable name "Bar" for "Bar" conflicts with enumeration type "Bar"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "Bar", Values: []*gen.Value{{Name: "X"}}},
+				{Type: "Baz", Values: []*gen.Value{{Name: "Bar"}}},
+			},
+		}},
+		{`variable name "_str_Bar" for "str_Bar" conflicts with a name generated for "Bar"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "Bar", Prefix: "_", Values: []*gen.Value{{Name: "str_Bar"}}},
+			},
+		}},
+		{`variable name "ParseBar" for "Bar" conflicts with a name generated for "Bar"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "Bar", Prefix: "Parse", Values: []*gen.Value{{Name: "Bar"}}},
+			},
+		}},
+		{`variable name "Enums" for "Enums" conflicts with the registry`, &gen.Config{
+			Package:  "foo",
+			Registry: true,
+			Enum: []*gen.Enum{
+				{Type: "Bar", Zero: "Enums", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`invalid type name "2Bar"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "2Bar", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`type name "error" shadows a predeclared identifier`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "error", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`variable name "Mode_Dry" for "Dry" contains an underscore`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Prefix: "Mode_", NamePolicy: &gen.NamePolicy{Style: "mixed-caps"}, Values: []*gen.Value{{Name: "Dry"}}},
+			},
+		}},
+		{`variable name "modeDry" for "Dry" is not exported`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Prefix: "mode", NamePolicy: &gen.NamePolicy{Style: "go-exported"}, Values: []*gen.Value{{Name: "Dry"}}},
+			},
+		}},
+		{`name "x" does not match name-policy pattern "[A-Z]\\w*"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", NamePolicy: &gen.NamePolicy{Pattern: `[A-Z]\w*`}, Values: []*gen.Value{{Name: "X"}, {Name: "x"}}},
+			},
+		}},
+		{`name "NONE" is reserved by name-policy`, &gen.Config{
+			Package:    "foo",
+			NamePolicy: &gen.NamePolicy{Reserved: []string{"None"}},
+			Enum: []*gen.Enum{
+				{Type: "bar", Zero: "NONE", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`unknown sort order "size"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Sort: "size", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`unknown unmarshal-unknown policy "drop"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", TextMarshal: true, UnmarshalUnknown: "drop", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"unmarshal-unknown requires text-marshal, xml, or json-v2", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", UnmarshalUnknown: "zero", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"keep-text requires the pointer representation", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", XML: true, UnmarshalUnknown: "keep-text", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"examples requires an exported type", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Unexported: true, Examples: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"log-group requires log-value", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", LogGroup: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"list-unique requires parse-list", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", ListUnique: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"providers must set env or default", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Providers: &gen.Providers{}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`default provider "Y" is not an enumerator`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Providers: &gen.Providers{Default: "Y"}, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{"quickcheck requires a parsing function", &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", QuickCheck: true, Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+		{`unknown naming scheme "bogus"`, &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "bar", Naming: "bogus", Values: []*gen.Value{{Name: "X"}}},
+			},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := test.config.Generate(&buf)
+			if err == nil {
+				t.Errorf("Test %s: expected error, got\n%s", test.desc, buf.String())
+			} else if !strings.Contains(err.Error(), test.desc) {
+				t.Errorf("Test %s: error does not match: %v", test.desc, err)
+			}
+		})
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{{
+			Type:   "Bar",
+			Values: []*gen.Value{{Name: "X"}, {Name: "Y"}},
+		}},
+		Templates: map[string]string{
+			// Supplement the output with an extra method.
+			"enum-extra": `
+// Count returns the number of {{.Type}} enumerators.
+func ({{.Type}}) Count() int { return {{len .Enumerators}} }
+`,
+			// Replace the doc comment for the Enum method.
+			"methods": `
+// Enum reports the type name.
+func ({{.Type}}) Enum() string { return {{quote .Type}} }
+
+func (v {{.Type}}) String() string { return {{.Strs}}[v.{{.Field}}] }
+
+func (v {{.Type}}) Valid() bool { return v.{{.Field}} != 0 }
+`,
+		},
+	}
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"func (Bar) Count() int { return 2 }",
+		"// Enum reports the type name.",
+		"func (v Bar) Valid() bool { return v._Bar != 0 }",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output is missing %q:\n%s", want, got)
+		}
+	}
+
+	t.Run("Docs", func(t *testing.T) {
+		cfg := &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{{
+				Type:     "Bar",
+				Receiver: "b2",
+				Attrs:    map[string]string{"weight": "int"},
+				Values:   []*gen.Value{{Name: "X"}, {Name: "Y"}},
+			}},
+			Templates: map[string]string{
+				"doc-String": `// {{.Method}} renders {{.Recv}} as text.`,
+				"doc-attr":   `// {{.Method}} reports the {{.Name}} of {{.Type}} {{.Recv}}.`,
+			},
+		}
+		var buf bytes.Buffer
+		if err := cfg.Generate(&buf); err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		got := buf.String()
+		for _, want := range []string{
+			"// String renders b2 as text.\nfunc (b2 Bar) String() string",
+			"// Weight reports the weight of Bar b2.\nfunc (b2 Bar) Weight() int",
+			"// Valid reports whether b2 is a valid non-zero Bar value.",
+			"return b2._Bar > 0",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("Output is missing %q:\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		cfg.Templates = map[string]string{"enum-extra": "{{.Nonesuch}}"}
+		if err := cfg.Generate(io.Discard); err == nil {
+			t.Error("Generate with a bad template did not report an error")
+		}
+	})
+}
+
+func TestGenerateAll(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{
+			{Type: "A", Values: []*gen.Value{{Name: "X"}}},
+			{Type: "B", Values: []*gen.Value{{Name: "Y"}}},
+			{Type: "C", Values: []*gen.Value{{Name: "Z"}}},
+		},
+	}
+
+	var want bytes.Buffer
+	if err := cfg.Generate(&want); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	var got bytes.Buffer
+	if err := cfg.GenerateAll(&got); err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	} else if got.String() != want.String() {
+		t.Errorf("GenerateAll output differs from Generate:\n%s", got.String())
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		bad := &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{
+				{Type: "A", Values: []*gen.Value{{Name: "X"}}, FlagFunc: true},
+				{Type: "B", Values: []*gen.Value{{Name: "Y"}}, Query: true},
+			},
+		}
+		var buf bytes.Buffer
+		err := bad.GenerateAll(&buf)
+		var verrs gen.ValidationErrors
+		if !errors.As(err, &verrs) || len(verrs) != 2 {
+			t.Errorf("GenerateAll: got %v, want 2 validation errors", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("GenerateAll wrote %d bytes despite errors", buf.Len())
+		}
+	})
+
+	t.Run("Templates", func(t *testing.T) {
+		cfg.Templates = map[string]string{
+			"enum-extra": `{{if ne .Type "B"}}{{.Nonesuch}}{{end}}`,
+		}
+		defer func() { cfg.Templates = nil }()
+
+		var buf bytes.Buffer
+		err := cfg.GenerateAll(&buf)
+		if err == nil {
+			t.Fatal("GenerateAll: got nil error, want errors")
+		}
+		for _, want := range []string{`enum "A"`, `enum "C"`} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("GenerateAll: error %q does not mention %s", err, want)
+			}
+		}
+		if strings.Contains(err.Error(), `enum "B"`) {
+			t.Errorf("GenerateAll: error %q reports valid enum B", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("GenerateAll wrote %d bytes despite errors", buf.Len())
+		}
+	})
+}
+
+func TestAfterGenerate(t *testing.T) {
+	var calls int
+	var got []byte
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum:    []*gen.Enum{{Type: "Mood", Values: []*gen.Value{{Name: "Happy"}}}},
+		AfterGenerate: func(c *gen.Config, src []byte) error {
+			calls++
+			got = src
+			if c.Package == "bad" {
+				return errors.New("migration failed")
+			}
+			return nil
+		},
+	}
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if calls != 1 || !bytes.Equal(got, buf.Bytes()) {
+		t.Errorf("AfterGenerate: got %d calls with %d bytes, want 1 call with the output", calls, len(got))
+	}
+
+	buf.Reset()
+	if err := cfg.GenerateAll(&buf); err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	}
+	if calls != 2 || !bytes.Equal(got, buf.Bytes()) {
+		t.Errorf("AfterGenerate: got %d calls with %d bytes, want 2 calls with the output", calls, len(got))
+	}
+
+	// Errors from the callback are reported, but the output is still written.
+	cfg.Package = "bad"
+	buf.Reset()
+	if err := cfg.Generate(&buf); err == nil || !strings.Contains(err.Error(), "after generate: migration failed") {
+		t.Errorf("Generate: got %v, want callback error", err)
+	}
+	if buf.Len() == 0 {
+		t.Error("Generate: no output was written")
+	}
+
+	// The callback is not called if generation fails.
+	cfg.Enum[0].Values = nil
+	if err := cfg.Generate(io.Discard); err == nil {
+		t.Error("Generate: got nil error, want error")
+	}
+	if calls != 3 {
+		t.Errorf("AfterGenerate: got %d calls, want 3", calls)
+	}
+}
+
+func TestProto(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{{
+			Type: "Color",
+			Zero: "Unknown",
+			Proto: &gen.ProtoEnum{
+				Type:   "cpb.Color",
+				Import: "example.com/proto/colorpb",
+				Values: map[string]string{"Unknown": "Color_UNSPECIFIED", "Blue": "Color_AZURE"},
+			},
+			Values: []*gen.Value{{Name: "Red"}, {Name: "Blue"}},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`cpb "example.com/proto/colorpb"`,
+		"func (v Color) ToProto() cpb.Color {",
+		"case Color{}:\n\t\treturn cpb.Color_UNSPECIFIED\n",
+		"case Red:\n\t\treturn cpb.Color_Red\n",
+		"case Blue:\n\t\treturn cpb.Color_AZURE\n",
+		"func ColorFromProto(p cpb.Color) Color {",
+		"case cpb.Color_AZURE:\n\t\treturn Blue\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output is missing %q:\n%s", want, got)
+		}
+	}
+
+	cfg.Enum[0].Proto.Values["Green"] = "Color_GREEN"
+	if err := cfg.Generate(io.Discard); err == nil {
+		t.Error("Generate with an unknown proto value did not report an error")
+	}
+}
+
+func TestGRPC(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{{
+			Type: "Failure",
+			Zero: "None",
+			Values: []*gen.Value{
+				{Name: "None", GRPCCode: "OK"},
+				{Name: "Missing", GRPCCode: "NotFound", Doc: "The {name}\nresource was not found."},
+				{Name: "Busy", GRPCCode: "Unavailable"},
+				{Name: "Other"},
+			},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`"google.golang.org/grpc/codes"`,
+		`"google.golang.org/grpc/status"`,
+		"func (v Failure) GRPCCode() codes.Code {",
+		"case Failure{}:\n\t\treturn codes.OK\n",
+		"case Missing:\n\t\treturn codes.NotFound\n",
+		"case Busy:\n\t\treturn codes.Unavailable\n",
+		"default:\n\t\treturn codes.Unknown\n",
+		"func (v Failure) GRPCError() error {",
+		"case Missing:\n\t\tmsg = \"The Missing resource was not found.\"\n",
+		"return status.Error(v.GRPCCode(), msg)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "case Other:") {
+		t.Errorf("Output has an unexpected case for Other:\n%s", got)
+	}
+
+	cfg.Enum[0].Values[3].GRPCCode = "Bogus"
+	if err := cfg.Generate(io.Discard); err == nil || !strings.Contains(err.Error(), `unknown gRPC code "Bogus"`) {
+		t.Errorf("Generate with an unknown gRPC code: got %v, want error", err)
+	}
+}
+
+func TestFindPackages(t *testing.T) {
+	const tagged = "package %s\n\n//enumgen:type T\n// values:\n//   - name: %s\n"
+	dir := t.TempDir()
+	for path, text := range map[string]string{
+		"a/a.go":          fmt.Sprintf(tagged, "a", "A"),
+		"a/b/b.go":        fmt.Sprintf(tagged, "b", "B"),
+		"a/c/c.go":        "package c\n",
+		"a/testdata/t.go": fmt.Sprintf(tagged, "t", "T"),
+		"d/go.mod":        "module d\n",
+		"d/d.go":          fmt.Sprintf(tagged, "d", "D"),
+		"e/e.go":          fmt.Sprintf(tagged, "e", "E"),
+	} {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatalf("Create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(text), 0600); err != nil {
+			t.Fatalf("Write file: %v", err)
+		}
+	}
+
+	got, err := gen.FindPackages(dir)
+	if err != nil {
+		t.Fatalf("FindPackages: unexpected error: %v", err)
+	}
+	want := []string{filepath.Join(dir, "a"), filepath.Join(dir, "a/b"), filepath.Join(dir, "e")}
+	if !slices.Equal(got, want) {
+		t.Errorf("FindPackages: got %q, want %q", got, want)
+	}
+
+	cfg, err := gen.LoadPackageDir(filepath.Join(dir, "a/b"))
+	if err != nil {
+		t.Fatalf("LoadPackageDir: unexpected error: %v", err)
+	}
+	if cfg.Package != "b" || len(cfg.Enum) != 1 || cfg.Enum[0].Values[0].Name != "B" {
+		t.Errorf("LoadPackageDir: got %+v, want package b with value B", cfg)
+	}
+	if _, err := gen.LoadPackageDir(filepath.Join(dir, "a/c")); !errors.Is(err, gen.ErrNoConfig) {
+		t.Errorf("LoadPackageDir: got %v, want %v", err, gen.ErrNoConfig)
+	}
+}
+
+func TestLoadPackageMerge(t *testing.T) {
+	const tagged = "package %s\n\n//enumgen:type T\n// values:\n//   - name: %s\n"
+	write := func(t *testing.T, dir string, files map[string]string) {
+		t.Helper()
+		for name, text := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0600); err != nil {
+				t.Fatalf("Write file: %v", err)
+			}
+		}
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, map[string]string{
+			"a.go":       fmt.Sprintf(tagged, "p", "A"),
+			"b.yml":      "merge: true\npackage: p\ninclude: [more.yml]\nenum:\n  - type: U\n    values:\n      - name: B\n",
+			"ci.yaml":    "steps: [build, test]\n",
+			"list.yml":   "- merge\n",
+			"unused.yml": "package: p\nenum:\n  - type: V\n    values:\n      - name: C\n",
+		})
+		cfg, err := gen.LoadPackageDir(dir)
+		if err != nil {
+			t.Fatalf("LoadPackageDir: unexpected error: %v", err)
+		}
+		var got []string
+		for _, e := range cfg.Enum {
+			got = append(got, e.Type)
+		}
+		if cfg.Package != "p" || !slices.Equal(got, []string{"T", "U"}) {
+			t.Errorf("LoadPackageDir: got package %q, enums %q; want p, [T U]", cfg.Package, got)
+		}
+		if !slices.Equal(cfg.Include, []string{"more.yml"}) {
+			t.Errorf("LoadPackageDir: got includes %q, want [more.yml]", cfg.Include)
+		}
+	})
+
+	t.Run("YAMLOnly", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, map[string]string{
+			"enums.yaml": "merge: true\npackage: q\nenum:\n  - type: U\n    values:\n      - name: B\n",
+		})
+		if cfg, err := gen.LoadPackageDir(dir); err != nil || cfg.Package != "q" {
+			t.Errorf("LoadPackageDir: got %+v, %v; want package q", cfg, err)
+		}
+	})
+
+	for _, tc := range []struct {
+		name, yaml, want string
+	}{
+		{"Package", "merge: true\npackage: other\n", `has package "other", want "p"`},
+		{"Settings", "merge: true\npackage: p\nregistry: true\n", "may set only package, enum, and include"},
+		{"NoPackage", "merge: true\nenum: []\n", "package name not defined"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			write(t, dir, map[string]string{"a.go": fmt.Sprintf(tagged, "p", "A"), "b.yml": tc.yaml})
+			if _, err := gen.LoadPackageDir(dir); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("LoadPackageDir: got %v, want error containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestConfigFromJSON(t *testing.T) {
+	const source = `{
+	"package": "p",
+	"enum": [
+		{
+			"type": "Color",
+			"val-doc": "The colors.",
+			"flag-value": true,
+			"flagvalue": true,
+			"values": [{"name": "Red", "attrs": {"hex": 16711680}}, {"name": "Blue"}]
+		}
+	]
+}
+`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "enums.json")
+	if err := os.WriteFile(path, []byte(source), 0600); err != nil {
+		t.Fatalf("Write file: %v", err)
+	}
+	cfg, err := gen.ConfigFromJSON(path)
+	if err != nil {
+		t.Fatalf("ConfigFromJSON: unexpected error: %v", err)
+	}
+	if len(cfg.Enum) != 1 {
+		t.Fatalf("ConfigFromJSON: got %d enums, want 1", len(cfg.Enum))
+	}
+	if e := cfg.Enum[0]; e.Type != "Color" || e.ValDoc != "The colors." || !e.FlagValue || len(e.Values) != 2 {
+		t.Errorf("ConfigFromJSON: got %+v, want Color with val-doc, flag-value, and 2 values", e)
+	}
+	if err := cfg.UnknownFields(); err == nil || !strings.Contains(err.Error(), "enums.json:8:") {
+		t.Errorf("UnknownFields: got %v, want error for flagvalue at line 8", err)
+	}
+
+	t.Run("Syntax", func(t *testing.T) {
+		bad := filepath.Join(dir, "bad.json")
+		if err := os.WriteFile(bad, []byte("{\n  \"package\": \"p\",\n  \"enum\": [}\n"), 0600); err != nil {
+			t.Fatalf("Write file: %v", err)
+		}
+		if _, err := gen.ConfigFromJSON(bad); err == nil || !strings.Contains(err.Error(), "bad.json: line 3: invalid JSON") {
+			t.Errorf("ConfigFromJSON: got %v, want a syntax error on line 3", err)
+		}
+	})
+
+	// A config encoded with encoding/json has the same keys.
+	t.Run("Marshal", func(t *testing.T) {
+		data, err := json.Marshal(cfg)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		for _, key := range []string{`"val-doc":"The colors."`, `"flag-value":true`} {
+			if !bytes.Contains(data, []byte(key)) {
+				t.Errorf("Marshal: output is missing %s: %s", key, data)
+			}
+		}
+		var dec gen.Config
+		if err := json.Unmarshal(data, &dec); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if e := dec.Enum[0]; e.ValDoc != "The colors." || !e.FlagValue || len(e.Values) != 2 {
+			t.Errorf("Unmarshal: got %+v, want the original enum", e)
+		}
+	})
+}
+
+func TestConfigSchema(t *testing.T) {
+	var schema struct {
+		Ref  string                    `json:"$ref"`
+		Defs map[string]map[string]any `json:"$defs"`
+	}
+	if err := json.Unmarshal(gen.ConfigSchema(), &schema); err != nil {
+		t.Fatalf("ConfigSchema: invalid JSON: %v", err)
+	}
+	if schema.Ref != "#/$defs/Config" {
+		t.Errorf("ConfigSchema: got $ref %q, want Config", schema.Ref)
+	}
+	for _, def := range []string{"Config", "Enum", "Value", "Profile", "PackageConfig"} {
+		if _, ok := schema.Defs[def]; !ok {
+			t.Errorf("ConfigSchema: missing definition for %s", def)
+		}
+	}
+
+	// The test config matches the schema.
+	data, err := os.ReadFile("testdata/gentest.yml")
+	if err != nil {
+		t.Fatalf("Read config: %v", err)
+	}
+	if err := gen.CheckConfigSchema("gentest.yml", data); err != nil {
+		t.Errorf("CheckConfigSchema: unexpected error: %v", err)
+	}
+
+	const source = `package: p
+enum:
+  - type: Color
+    flag-value: yes please
+    prefix: [C]
+    values:
+      - name: Red
+        index: first
+        colour: red
+      - Blue
+`
+	err = gen.CheckConfigSchema("bad.yml", []byte(source))
+	if err == nil {
+		t.Fatal("CheckConfigSchema: got nil, want errors")
+	}
+	for _, want := range []string{
+		`bad.yml:4:17: enum[0].flag-value: got string "yes please", want boolean`,
+		`bad.yml:5:13: enum[0].prefix: got an array, want string`,
+		`bad.yml:8:16: enum[0].values[0].index: got string "first", want integer`,
+		`bad.yml:9:9: enum[0].values[0]: unknown key "colour"`,
+		`bad.yml:10:9: enum[0].values[1]: got string, want an object`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("CheckConfigSchema: error is missing %q:\n%v", want, err)
+		}
+	}
+	var se *gen.SchemaError
+	if !errors.As(err, &se) || se.Line != 4 {
+		t.Errorf("CheckConfigSchema: got %v, want a *SchemaError at line 4", err)
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		const source = `{"package": "p", "enum": [{"type": "C", "values": [{"name": "A", "index": "1"}]}]}`
+		err := gen.CheckConfigSchema("bad.json", []byte(source))
+		if err == nil || !strings.Contains(err.Error(), `bad.json:1:75: enum[0].values[0].index: got string "1", want integer`) {
+			t.Errorf("CheckConfigSchema: got %v, want error for index", err)
+		}
+	})
+}
+
+func TestPackageComment(t *testing.T) {
+	const source = `package p
+
+//enumgen:type C
+// values:
+//   - name: X
+
+/*enumgen:package
+
+registry: true
+enum:
+  - type: A
+    values: [{name: Y}]
+  - type: B
+    values: [{name: Z}]
+    flagvalue: true
+*/
+`
+	cfg, err := gen.ConfigFromSource("p.go", []byte(source))
+	if err != nil {
+		t.Fatalf("ConfigFromSource: unexpected error: %v", err)
+	}
+	var got []string
+	for _, e := range cfg.Enum {
+		got = append(got, e.Type)
+	}
+	if cfg.Package != "p" || !cfg.Registry || !slices.Equal(got, []string{"C", "A", "B"}) {
+		t.Errorf("ConfigFromSource: got package %q, registry %v, enums %q; want p, true, [C A B]",
+			cfg.Package, cfg.Registry, got)
+	}
+	if err := cfg.UnknownFields(); err == nil || err.Error() != `p.go:15:5: unknown field "flagvalue" in gen.Enum` {
+		t.Errorf("UnknownFields: got %v, want flagvalue at p.go:15:5", err)
+	}
+	if err := cfg.Generate(io.Discard); err != nil {
+		t.Errorf("Generate: unexpected error: %v", err)
+	}
+
+	t.Run("LoadPackage", func(t *testing.T) {
+		dir := t.TempDir()
+		for name, text := range map[string]string{
+			"a.go": "package p\n\n//enumgen:type T\n// values: [{name: A}]\n",
+			"b.go": source,
+		} {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0600); err != nil {
+				t.Fatalf("Write file: %v", err)
+			}
+		}
+		cfg, err := gen.LoadPackageDir(dir)
+		if err != nil {
+			t.Fatalf("LoadPackageDir: unexpected error: %v", err)
+		}
+		var got []string
+		for _, e := range cfg.Enum {
+			got = append(got, e.Type)
+		}
+		if !cfg.Registry || !slices.Equal(got, []string{"T", "C", "A", "B"}) {
+			t.Errorf("LoadPackageDir: got registry %v, enums %q; want true, [T C A B]", cfg.Registry, got)
+		}
+
+		// Only one file may define settings.
+		if err := os.WriteFile(filepath.Join(dir, "c.go"), []byte("package p\n\n//enumgen:package\n// header: x\n"), 0600); err != nil {
+			t.Fatalf("Write file: %v", err)
+		}
+		if _, err := gen.LoadPackageDir(dir); err == nil || !strings.Contains(err.Error(), `already defined by "b.go"`) {
+			t.Errorf("LoadPackageDir: got %v, want error for settings in two files", err)
+		}
+	})
+
+	for _, tc := range []struct {
+		name, source, want string
+	}{
+		{"Package", "package p\n\n//enumgen:package\n// package: q\n", `has package "q", want "p"`},
+		{"Multiple", "package p\n\n//enumgen:package\n\n//enumgen:package\n", "multiple enumgen:package comments (first at line 3)"},
+		{"Tag", "package p\n\n//enumgen:package T\n", `unexpected text "T" after enumgen:package`},
+		{"Syntax", "package p\n\n//enumgen:package\n// enum: [\n", "p.go: yaml: line 4:"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := gen.ConfigFromSource("p.go", []byte(tc.source)); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("ConfigFromSource: got %v, want error containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestStructTags(t *testing.T) {
+	const source = `package p
+
+/*enumgen:package
+struct-tags: true
+*/
+
+type Options struct {
+	Mode  Mode    ` + "`json:\"mode\" enum:\"fast, dry-run\"`" + `
+	Modes []*Mode ` + "`enum:\"fast,dry-run\"`" + `
+	Name  string  ` + "`json:\"name\"`" + `
+}
+`
+	dir := t.TempDir()
+	for name, text := range map[string]string{
+		"a.go": source,
+		"b.go": "package p\n\ntype Job struct {\n\tState State `enum:\"new,done\"`\n}\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0600); err != nil {
+			t.Fatalf("Write file: %v", err)
+		}
+	}
+	cfg, err := gen.LoadPackageDir(dir)
+	if err != nil {
+		t.Fatalf("LoadPackageDir: unexpected error: %v", err)
+	}
+	var got []string
+	for _, e := range cfg.Enum {
+		got = append(got, e.Type)
+	}
+	if !slices.Equal(got, []string{"Mode", "State"}) {
+		t.Errorf("LoadPackageDir: got enums %q, want [Mode State]", got)
+	}
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	for _, want := range []string{
+		"// Mode is the type of the Options.Mode field.",
+		"ModeDryRun = Mode{2}",
+		"StateDone = State{2}",
+		`"dry-run"`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Output is missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	t.Run("Unset", func(t *testing.T) {
+		cfg, err := gen.ConfigFromSource("b.go", []byte("package p\n\ntype Job struct {\n\tState State `enum:\"new,done\"`\n}\n"))
+		if err != nil {
+			t.Fatalf("ConfigFromSource: unexpected error: %v", err)
+		}
+		if len(cfg.Enum) != 0 {
+			t.Errorf("ConfigFromSource: got %d enums, want none without struct-tags", len(cfg.Enum))
+		}
+	})
+
+	for _, tc := range []struct {
+		name, fields, want string
+	}{
+		{"Builtin", "X string `enum:\"a\"`", "enum tag on a field of type string"},
+		{"Map", "X map[string]T `enum:\"a\"`", "enum tag on a field of type map[string]T"},
+		{"Mismatch", "X T `enum:\"a,b\"`\n\tY T `enum:\"a\"`", `enum tag for type "T" lists ["a"], but the tag at p.go:4:6 lists ["a" "b"]`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			source := "package p\n\ntype S struct {\n\t" + tc.fields + "\n}\n"
+			if _, err := gen.ConfigFromSource("p.go", []byte(source)); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("ConfigFromSource: got %v, want error containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestGORM(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{{
+			Type:   "Mood",
+			GORM:   true,
+			DBType: "mood",
+			Values: []*gen.Value{{Name: "Happy"}, {Name: "Sad", Text: "don't ask"}},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`"gorm.io/gorm"`,
+		`"gorm.io/gorm/schema"`,
+		"func (v Mood) Value() (driver.Value, error) {",
+		"func (v *Mood) Scan(src any) error {",
+		`func (Mood) GormDataType() string { return "string" }`,
+		"func (Mood) GormDBDataType(db *gorm.DB, field *schema.Field) string {",
+		`return "ENUM('Happy', 'don''t ask')"`,
+		`case "postgres":` + "\n\t\treturn \"mood\"",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output is missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestGenerateTests(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{{
+			Type:   "Mood",
+			Values: []*gen.Value{{Name: "Happy"}, {Name: "Sad"}},
+		}},
+	}
+	if cfg.HasTests() {
+		t.Error("HasTests: got true, want false")
+	}
+	if err := cfg.GenerateTests(io.Discard); err == nil {
+		t.Error("GenerateTests without quickcheck did not report an error")
+	}
+
+	cfg.Enum[0].QuickCheck = true
+	cfg.Enum[0].TextMarshal = true
+	var buf bytes.Buffer
+	if err := cfg.GenerateTests(&buf); err != nil {
+		t.Fatalf("GenerateTests: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"package foo",
+		`"testing/quick"`,
+		"func TestQuickCheckMood(t *testing.T) {",
+		`{"UnmarshalText", func(s string) (Mood, bool) {`,
+		"values := []Mood{Happy, Sad}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output is missing %q:\n%s", want, got)
+		}
+	}
+
+	// Table-driven tests do not need the imports of the property tests.
+	cfg.Enum[0].QuickCheck = false
+	cfg.Enum[0].Tests = true
+	buf.Reset()
+	if err := cfg.GenerateTests(&buf); err != nil {
+		t.Fatalf("GenerateTests: %v", err)
+	}
+	got = buf.String()
+	for _, want := range []string{
+		"func TestEnumMood(t *testing.T) {",
+		`{Happy, "Happy"},`,
+		"text, err := tc.value.MarshalText()",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "testing/quick") {
+		t.Errorf("Output imports testing/quick:\n%s", got)
+	}
+
+	// Examples alone do not need the testing package, and omit deprecated
+	// enumerators.
+	cfg.Enum[0].Tests = false
+	cfg.Enum[0].Examples = true
+	cfg.Enum[0].Constructor = true
+	cfg.Enum[0].Values = append(cfg.Enum[0].Values, &gen.Value{Name: "Meh", Text: "happy", Deprecated: "Use Happy."})
+	buf.Reset()
+	if err := cfg.GenerateTests(&buf); err != nil {
+		t.Fatalf("GenerateTests: %v", err)
+	}
+	got = buf.String()
+	for _, want := range []string{
+		"func ExampleMood() {",
+		"range []Mood{Happy, Sad} {",
+		"// Output:\n\t// Happy\n\t// Sad\n}",
+		"func ExampleNewMood() {",
+		`fmt.Println(NewMood("HAPPY").String())`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `"testing"`) || strings.Contains(got, "ExampleParseMood") {
+		t.Errorf("Output has unexpected content:\n%s", got)
+	}
+}
+
+func TestGenerateJSONv2(t *testing.T) {
+	cfg := &gen.Config{
+		Package:   "foo",
+		BuildTags: "linux || darwin",
+		Enum: []*gen.Enum{{
+			Type:   "Mood",
+			Values: []*gen.Value{{Name: "Happy"}, {Name: "Sad"}},
+		}},
+	}
+	if cfg.HasJSONv2() {
+		t.Error("HasJSONv2: got true, want false")
+	}
+	if err := cfg.GenerateJSONv2(io.Discard); err == nil {
+		t.Error("GenerateJSONv2 without json-v2 did not report an error")
+	}
+
+	cfg.Enum[0].JSONv2 = true
+	var buf bytes.Buffer
+	if err := cfg.GenerateJSONv2(&buf); err != nil {
+		t.Fatalf("GenerateJSONv2: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"//go:build (linux || darwin) && goexperiment.jsonv2\n",
+		"package foo",
+		`"encoding/json/jsontext"`,
+		"func (v Mood) MarshalJSONTo(enc *jsontext.Encoder) error {",
+		"func (v *Mood) UnmarshalJSONFrom(dec *jsontext.Decoder) error {",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output is missing %q:\n%s", want, got)
+		}
+	}
+
+	// The main output does not depend on the experiment.
+	buf.Reset()
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if got := buf.String(); strings.Contains(got, "jsontext") || strings.Contains(got, "goexperiment") {
+		t.Errorf("Generate output refers to json/v2:\n%s", got)
+	}
+}
+
+func TestGenerateFiles(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{{
+			Type:   "Mood",
+			Tests:  true,
+			JSONv2: true,
+			Values: []*gen.Value{{Name: "Happy"}, {Name: "Sad"}},
+		}},
+		Packages: []*gen.PackageConfig{{
+			Output: "bar/enums.go",
+			Config: gen.Config{
+				Package: "bar",
+				Enum:    []*gen.Enum{{Type: "Color", Values: []*gen.Value{{Name: "Red"}}}},
+			},
+		}},
+	}
+	files, err := cfg.GenerateFiles()
+	if err != nil {
+		t.Fatalf("GenerateFiles: unexpected error: %v", err)
+	}
+	want := []string{"bar/enums.go", "enums.go", "enums_jsonv2.go", "enums_test.go"}
+	if got := slices.Sorted(maps.Keys(files)); !slices.Equal(got, want) {
+		t.Errorf("GenerateFiles: got files %q, want %q", got, want)
+	}
+
+	// Each file matches the output of the corresponding method.
+	top := *cfg
+	top.Packages = nil
+	for path, generate := range map[string]func(io.Writer) error{
+		"enums.go":        top.Generate,
+		"enums_test.go":   top.GenerateTests,
+		"enums_jsonv2.go": top.GenerateJSONv2,
+		"bar/enums.go":    cfg.PackageConfigs()[0].Generate,
+	} {
+		var buf bytes.Buffer
+		if err := generate(&buf); err != nil {
+			t.Fatalf("Generate %s: %v", path, err)
+		}
+		if got := string(files[path]); got != buf.String() {
+			t.Errorf("File %s: got:\n%s\nwant:\n%s", path, got, buf.String())
+		}
+	}
+
+	// Outputs that collide are reported before generating anything.
+	cfg.Packages[0].Output = "enums_other.go"
+	cfg.Packages[0].Package = "foo"
+	cfg.Packages[0].Enum[0].Type = "Mood"
+	if files, err := cfg.GenerateFiles(); err == nil {
+		t.Errorf("GenerateFiles: got %d files, want error", len(files))
+	}
+}
+
+func TestExtensible(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{{
+			Type:       "Mood",
+			Doc:        "A {name} is a state of mind.",
+			Extensible: true,
+			Values:     []*gen.Value{{Name: "Happy"}, {Name: "Sad"}},
+		}, {
+			Type:   "Sealed",
+			Values: []*gen.Value{{Name: "Only"}},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	got := buf.String()
+	want := "// A Mood is a state of mind.\n//\n" + gen.ExtensibleDirective + "\ntype Mood struct"
+	if !strings.Contains(got, want) {
+		t.Errorf("Output is missing %q:\n%s", want, got)
+	}
+	if n := strings.Count(got, gen.ExtensibleDirective); n != 1 {
+		t.Errorf("Output has %d extensible directives, want 1:\n%s", n, got)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestFingerprint(t *testing.T) {
+	fingerprint := func(t *testing.T, vals ...*gen.Value) string {
+		t.Helper()
+		cfg := &gen.Config{
+			Package: "foo",
+			Enum:    []*gen.Enum{{Type: "Mood", Fingerprint: true, Values: vals}},
+		}
+		var buf bytes.Buffer
+		if err := cfg.Generate(&buf); err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		_, rest, ok := strings.Cut(buf.String(), "const MoodFingerprint = ")
+		if !ok {
+			t.Fatalf("Generate: missing fingerprint:\n%s", buf.String())
+		}
+		fp, _, _ := strings.Cut(rest, "\n")
+		return fp
+	}
+
+	base := fingerprint(t, &gen.Value{Name: "Happy"}, &gen.Value{Name: "Sad"})
+	if !strings.HasPrefix(base, `"sha256:`) {
+		t.Errorf("Fingerprint: got %s, want sha256 digest", base)
+	}
+	if got := fingerprint(t, &gen.Value{Name: "Happy", Doc: "Joy."}, &gen.Value{Name: "Sad"}); got != base {
+		t.Errorf("Fingerprint with doc: got %s, want %s", got, base)
+	}
+	for _, vals := range [][]*gen.Value{
+		{{Name: "Sad"}, {Name: "Happy"}},
+		{{Name: "Happy", Text: "joyful"}, {Name: "Sad"}},
+		{{Name: "Happy"}, {Name: "Sad", Index: ptr(5)}},
+		{{Name: "Happy"}, {Name: "Sad"}, {Name: "Bored"}},
+	} {
+		if got := fingerprint(t, vals...); got == base {
+			t.Errorf("Fingerprint %v: got %s, want a different digest", vals, got)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	newConfig := func(format func([]byte) ([]byte, error)) *gen.Config {
+		return &gen.Config{
+			Package: "foo",
+			Enum:    []*gen.Enum{{Type: "Mood", Values: []*gen.Value{{Name: "Happy"}}}},
+			Format:  format,
+		}
+	}
+	var plain bytes.Buffer
+	if err := newConfig(nil).Generate(&plain); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	var got bytes.Buffer
+	cfg := newConfig(func(src []byte) ([]byte, error) {
+		if !bytes.Equal(src, plain.Bytes()) {
+			t.Errorf("Format: input is not the formatted source:\n%s", src)
+		}
+		return bytes.ReplaceAll(src, []byte("package foo"), []byte("package foo // reformatted")), nil
+	})
+	if err := cfg.Generate(&got); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !strings.Contains(got.String(), "package foo // reformatted\n") {
+		t.Errorf("Generate: output was not reformatted:\n%s", got.String())
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		var got bytes.Buffer
+		cfg := newConfig(func([]byte) ([]byte, error) { return nil, errors.New("bad style") })
+		if err := cfg.Generate(&got); err == nil || !strings.Contains(err.Error(), "bad style") {
+			t.Errorf("Generate: got %v, want format error", err)
+		}
+		if got.String() != plain.String() {
+			t.Errorf("Generate: got output\n%s\nwant\n%s", got.String(), plain.String())
+		}
+	})
+
+	t.Run("NoFormat", func(t *testing.T) {
+		var got bytes.Buffer
+		cfg := newConfig(func([]byte) ([]byte, error) { return nil, errors.New("unexpected format") })
+		cfg.NoFormat = true
+		if err := cfg.Generate(&got); err != nil {
+			t.Fatalf("Generate: unexpected error: %v", err)
+		}
+		if got.String() == plain.String() {
+			t.Error("Generate: output was formatted")
+		}
+		src, err := format.Source(got.Bytes())
+		if err != nil {
+			t.Fatalf("Output does not parse: %v\n%s", err, got.String())
+		}
+		if string(src) != plain.String() {
+			t.Errorf("Formatted output: got\n%s\nwant\n%s", src, plain.String())
+		}
+	})
+}
+
+func TestHeader(t *testing.T) {
+	cfg := &gen.Config{
+		Package:   "foo",
+		BuildTags: "linux && !386",
+		Header:    "Extra header text.\n\nSecond paragraph.",
+		Enum: []*gen.Enum{{
+			Type:   "Mood",
+			Values: []*gen.Value{{Name: "Happy"}},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	hash, err := cfg.ContentHash()
+	if err != nil {
+		t.Fatalf("ContentHash: %v", err)
+	}
+	want := "// Code generated by enumgen. DO NOT EDIT.\n" + gen.ProvenanceDirective + `{"hash":"` + hash + `"}` + `
+// Extra header text.
+//
+// Second paragraph.
+
+//go:build linux && !386
+
+package foo
+`
+	if got := buf.String(); !strings.HasPrefix(got, want) {
+		t.Errorf("Generate: output does not begin with\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{{
+			Type: "Mood",
+			Values: []*gen.Value{
+				{Name: "Happy"},
+				{Name: "Glad", Doc: "{name} is like Happy.", Deprecated: "Use Happy."},
+			},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	const want = `
+	// Glad is like Happy.
+	//
+	// Deprecated: Use Happy.
+	Glad = Mood{2}
+`
+	if got := buf.String(); !strings.Contains(got, want) {
+		t.Errorf("Generate: output does not contain\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestPackageDoc(t *testing.T) {
+	cfg := &gen.Config{
+		Package:    "foo",
+		PackageDoc: "Package foo defines moods.",
+		Enum: []*gen.Enum{{
+			Type:   "Mood",
+			Values: []*gen.Value{{Name: "Happy"}, {Name: "Sad", Text: "blue", Index: ptr(5)}},
+		}, {
+			Type:   "Level",
+			Zero:   "None",
+			Values: []*gen.Value{{Name: "Low", Code: ptr(10)}, {Name: "High", Code: ptr(200)}},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	hash, err := cfg.ContentHash()
+	if err != nil {
+		t.Fatalf("ContentHash: %v", err)
+	}
+	want := "// Code generated by enumgen. DO NOT EDIT.\n" + gen.ProvenanceDirective + `{"hash":"` + hash + `"}` + `
+
+// Package foo defines moods.
+//
+// # Enumerations
+//
+// The enumerators of type [Mood] are:
+//
+//	Name   String   Index
+//	Happy  "Happy"  1
+//	Sad    "blue"   5
+//
+// The enumerators of type [Level] are:
+//
+//	Name  String  Index  Code
+//	Low   "Low"   1      10
+//	High  "High"  2      200
+package foo
+`
+	if got := buf.String(); !strings.HasPrefix(got, want) {
+		t.Errorf("Generate: output does not begin with\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestNameStyle(t *testing.T) {
+	tests := []struct {
+		prefix, suffix, style string
+		want                  []string
+	}{
+		{"", "", "", []string{"None", "DarkRed", "HTTPCode"}},
+		{"color", "", "", []string{"colorNone", "colorDarkRed", "colorHTTPCode"}},
+		{"", "Color", "", []string{"NoneColor", "DarkRedColor", "HTTPCodeColor"}},
+		{"color", "", "exported", []string{"ColorNone", "ColorDarkRed", "ColorHTTPCode"}},
+		{"Color", "val", "camel", []string{"colorNoneVal", "colorDarkRedVal", "colorHTTPCodeVal"}},
+		{"color", "", "screaming-snake", []string{"COLOR_NONE", "COLOR_DARK_RED", "COLOR_HTTP_CODE"}},
+		{"x", "Y", "{type}{prefix}{name}{suffix}", []string{"ShadexNoneY", "ShadexDarkRedY", "ShadexHTTPCodeY"}},
+	}
+	for _, tc := range tests {
+		cfg := &gen.Config{
+			Package: "foo",
+			Enum: []*gen.Enum{{
+				Type:      "Shade",
+				Prefix:    tc.prefix,
+				Suffix:    tc.suffix,
+				NameStyle: tc.style,
+				Zero:      "None",
+				Groups:    map[string][]string{"Red": {"DarkRed"}},
+				Values:    []*gen.Value{{Name: "DarkRed"}, {Name: "HTTPCode"}},
+			}},
+		}
+		var buf bytes.Buffer
+		if err := cfg.Generate(&buf); err != nil {
+			t.Errorf("Generate %q/%q/%q: %v", tc.prefix, tc.suffix, tc.style, err)
+			continue
+		}
+		got := buf.String()
+		for i, name := range tc.want {
+			re := regexp.MustCompile(fmt.Sprintf(`\t%s\s+= Shade\{%d\}`, name, i))
+			if !re.MatchString(got) {
+				t.Errorf("Generate %q/%q/%q: missing variable %s:\n%s", tc.prefix, tc.suffix, tc.style, name, got)
+			}
+		}
+		if want := "case " + tc.want[1] + ":"; !strings.Contains(got, want) {
+			t.Errorf("Generate %q/%q/%q: missing %q in group predicate", tc.prefix, tc.suffix, tc.style, want)
+		}
+	}
+}
+
+func TestUnexported(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{{
+			Type:         "Shade",
+			Unexported:   true,
+			Constructors: gen.Constructors{New: true, Parse: true, Must: true},
+			ParseError:   "sentinel",
+			ParseList:    true,
+			ArrayIndex:   true,
+			Context:      true,
+			FlagValue:    true,
+			FlagFunc:     true,
+			Providers:    &gen.Providers{Env: "SHADE", Default: "Dark"},
+			Values:       []*gen.Value{{Name: "Dark"}, {Name: "Light"}},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: unexpected error: %v", err)
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), "", buf.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	// Only the methods of the type should be exported.
+	var names []string
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				names = append(names, d.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, spec.Name.Name)
+				case *ast.ValueSpec:
+					for _, id := range spec.Names {
+						names = append(names, id.Name)
+					}
+				}
+			}
+		}
+	}
+	for _, name := range names {
+		if token.IsExported(name) {
+			t.Errorf("Generate: %q is exported", name)
+		}
+	}
+	for _, want := range []string{"shade", "dark", "light", "parseShade", "mustShade", "newShade", "numShade", "shadeFlag"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("Generate: missing %q in %q", want, names)
+		}
+	}
+}
+
+// typeCheck reports an error for each problem found by type-checking the
+// output of cfg, such as an unused or missing import.
+func typeCheck(t *testing.T, cfg *gen.Config) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: unexpected error: %v", err)
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "enums.go", buf.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(err error) { t.Errorf("Type check: %v", err) },
+	}
+	conf.Check(cfg.Package, fset, []*ast.File{f}, nil)
+	if t.Failed() {
+		t.Logf("Generated source:\n%s", buf.String())
+	}
+}
+
+func TestImports(t *testing.T) {
+	values := []*gen.Value{{Name: "Happy"}, {Name: "Sad"}}
+	for _, tc := range []struct {
+		name string
+		enum gen.Enum
+	}{
+		{"RuntimeParse", gen.Enum{Runtime: true, Constructors: gen.Constructors{Parse: true}}},
+		{"RuntimeMust", gen.Enum{Runtime: true, Constructors: gen.Constructors{Must: true}}},
+		{"RuntimeProviders", gen.Enum{Runtime: true, Providers: &gen.Providers{Env: "MOOD"}}},
+		{"Providers", gen.Enum{Providers: &gen.Providers{Env: "MOOD"}}},
+		{"TextMarshal", gen.Enum{TextMarshal: true}},
+		{"FlagValue", gen.Enum{FlagValue: true}},
+		{"ParseList", gen.Enum{ParseList: true}},
+		{"Constructor", gen.Enum{Constructor: true}},
+		{"TextZero", gen.Enum{TextMarshal: true, UnmarshalUnknown: "zero"}},
+		{"XMLZero", gen.Enum{XML: true, UnmarshalUnknown: "zero"}},
+		{"TextKeepText", gen.Enum{TextMarshal: true, Representation: "pointer", UnmarshalUnknown: "keep-text"}},
+		{"XMLKeepText", gen.Enum{XML: true, Representation: "pointer", UnmarshalUnknown: "keep-text"}},
+		{"RuntimeTextZero", gen.Enum{Runtime: true, TextMarshal: true, UnmarshalUnknown: "zero"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			e := tc.enum
+			e.Type, e.Values = "Mood", values
+			typeCheck(t, &gen.Config{Package: "foo", Enum: []*gen.Enum{&e}})
+		})
+	}
+}
+
+func TestImportGroups(t *testing.T) {
+	const want = `import (
+	"fmt"
+	"strconv"
+
+	"example.com/util"
+	u "example.com/util/v2"
+)
+`
+	for _, noFormat := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := (&gen.Config{
+			Package:  "foo",
+			NoFormat: noFormat,
+			Enum: []*gen.Enum{{
+				Type:         "Mood",
+				TextMarshal:  true,
+				ExtraImports: []string{"example.com/util", "u example.com/util/v2", "strconv"},
+				Values:       []*gen.Value{{Name: "Happy"}, {Name: "Sad"}},
+			}},
+		}).Generate(&buf); err != nil {
+			t.Fatalf("Generate: unexpected error: %v", err)
+		}
+		if got := buf.String(); !strings.Contains(got, want) {
+			t.Errorf("Generate (NoFormat=%v): imports are not grouped, want:\n%s\ngot:\n%s", noFormat, want, got)
+		}
+	}
+}
+
+func TestReceiver(t *testing.T) {
+	// A receiver may use a name that only the methods of options not enabled
+	// for the enumeration refer to.
+	for _, recv := range []string{"c", "s", "e"} {
+		t.Run(recv, func(t *testing.T) {
+			typeCheck(t, &gen.Config{Package: "foo", Enum: []*gen.Enum{{
+				Type:        "Mood",
+				Receiver:    recv,
+				TextMarshal: true,
+				SQL:         true,
+				Formatter:   true,
+				LogValue:    true,
+				Query:       true,
+				ArrayIndex:  true,
+				ByName:      true,
+				Predicates:  true,
+				Values:      []*gen.Value{{Name: "Happy"}, {Name: "Sad"}},
+			}}})
+		})
+	}
+}
+
+func TestLazyMaps(t *testing.T) {
+	// With lazy, lookup maps are built on first use, and need no codes.
+	cfg := &gen.Config{
+		Package:      "foo",
+		MapThreshold: 2,
+		Enum: []*gen.Enum{{
+			Type:        "Mood",
+			Lazy:        true,
+			Constructor: true,
+			TextMarshal: true,
+			Values:      []*gen.Value{{Name: "Happy"}, {Name: "Sad"}},
+		}},
+	}
+	typeCheck(t, cfg)
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"_bystr_Mood  = sync.OnceValue(",
+		"_byfold_Mood = sync.OnceValue(",
+		"_bystr_Mood()[text]",
+		"_byfold_Mood()[strings.ToLower(s)]",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Generate: output is missing %q", want)
+		}
 	}
 }
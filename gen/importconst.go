@@ -0,0 +1,141 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImportConstEnum reads the Go package in the directory specified by pkgpath,
+// and constructs a Config that defines an enumeration equivalent to the
+// constants of the named integer type typeName declared in that package, in
+// the style of the stringer tool:
+//
+//	type Color int
+//
+//	const (
+//	   Unknown Color = iota
+//	   Red
+//	   Green
+//	)
+//
+// Each constant of the type becomes an enumerator with the same name and doc
+// comment, and whose string representation is the name of the constant. A
+// constant whose value is 0 becomes the zero enumerator. The values of the
+// remaining constants are preserved as their indices.
+//
+// An error is reported if the type is not found, is not an integer type, has
+// no constants, or if any of its constants are negative or share a value.
+func ImportConstEnum(pkgpath, typeName string) (*Config, error) {
+	des, err := os.ReadDir(pkgpath)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, de := range des {
+		if filepath.Ext(de.Name()) != ".go" || strings.HasSuffix(de.Name(), "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(pkgpath, de.Name()), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) != 0 && f.Name.Name != files[0].Name.Name {
+			continue // e.g., a package main generator script
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go source files found in %q", pkgpath)
+	}
+
+	// Type-check the package to evaluate the constants. Errors are ignored, as
+	// the dependencies of the package may not be available; so long as the
+	// constants of interest are well-formed, that does not matter.
+	info := &types.Info{Defs: make(map[*ast.Ident]types.Object)}
+	tc := &types.Config{
+		Importer: importer.Default(),
+		Error:    func(error) {},
+	}
+	pkg, _ := tc.Check(files[0].Name.Name, fset, files, info)
+
+	tobj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %q not found in %q", typeName, pkgpath)
+	}
+	if b, ok := tobj.Type().Underlying().(*types.Basic); !ok || b.Info()&types.IsInteger == 0 {
+		return nil, fmt.Errorf("type %q is not an integer type", typeName)
+	}
+
+	e := &Enum{Type: typeName}
+	seen := make(map[int64]string)
+	next := int64(1)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			switch gd.Tok {
+			case token.TYPE:
+				for _, spec := range gd.Specs {
+					if ts := spec.(*ast.TypeSpec); ts.Name.Name == typeName {
+						e.Doc = docText(ts.Doc, gd.Doc, len(gd.Specs))
+					}
+				}
+			case token.CONST:
+				for _, spec := range gd.Specs {
+					vs := spec.(*ast.ValueSpec)
+					for _, id := range vs.Names {
+						c, ok := info.Defs[id].(*types.Const)
+						if !ok || !types.Identical(c.Type(), tobj.Type()) {
+							continue
+						}
+						val, exact := constant.Int64Val(c.Val())
+						if !exact || val < 0 {
+							return nil, fmt.Errorf("constant %s has unsupported value %v", id.Name, c.Val())
+						} else if old, ok := seen[val]; ok {
+							return nil, fmt.Errorf("constants %s and %s have the same value (%d)", old, id.Name, val)
+						}
+						seen[val] = id.Name
+
+						v := &Value{Name: id.Name, Doc: docText(vs.Doc, vs.Comment, 1)}
+						if val == 0 {
+							e.Zero = id.Name
+						} else {
+							if val != next {
+								idx := int(val)
+								v.Index = &idx
+							}
+							next = val + 1
+						}
+						e.Values = append(e.Values, v)
+					}
+				}
+			}
+		}
+	}
+	if len(e.Values) == 0 {
+		return nil, errors.New("no constants found for type " + typeName)
+	}
+	return &Config{Package: pkg.Name(), Enum: []*Enum{e}}, nil
+}
+
+// docText returns the text of the first non-empty comment group among doc
+// and alt.  If n > 1, alt is assumed to apply to multiple declarations and is
+// ignored.
+func docText(doc, alt *ast.CommentGroup, n int) string {
+	if t := doc.Text(); t != "" || n > 1 {
+		return strings.TrimSpace(t)
+	}
+	return strings.TrimSpace(alt.Text())
+}
@@ -0,0 +1,79 @@
+package gen_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+	yaml "gopkg.in/yaml.v3"
+)
+
+const constEnumSource = `package legacy
+
+// Color is a colour of the rainbow.
+type Color int
+
+const (
+	Unknown Color = iota // not a colour
+	Red
+	Green
+
+	// Blue is the colour of the sky.
+	Blue Color = 10
+)
+
+const Other = 3 // not a Color
+`
+
+func TestImportConstEnum(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "legacy.go"), []byte(constEnumSource), 0600); err != nil {
+		t.Fatalf("Write source: %v", err)
+	}
+
+	cfg, err := gen.ImportConstEnum(dir, "Color")
+	if err != nil {
+		t.Fatalf("ImportConstEnum: unexpected error: %v", err)
+	}
+	got, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	const want = `package: legacy
+enum:
+    - type: Color
+      values:
+        - name: Unknown
+          doc: not a colour
+        - name: Red
+        - name: Green
+        - name: Blue
+          doc: Blue is the colour of the sky.
+          index: 10
+      doc: Color is a colour of the rainbow.
+      zero: Unknown
+`
+	if string(got) != want {
+		t.Errorf("Imported config: got\n%s\nwant\n%s", got, want)
+	}
+
+	// The imported config should be usable to generate code.
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !strings.Contains(buf.String(), `Blue    = Color{3}`) {
+		t.Errorf("Generated output is missing Blue:\n%s", buf.String())
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, name := range []string{"Nonesuch", "Other"} {
+			if cfg, err := gen.ImportConstEnum(dir, name); err == nil {
+				t.Errorf("ImportConstEnum(%q): got %+v, want error", name, cfg)
+			}
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package gen
+
+import (
+	"fmt"
+	"go/token"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// runtimePackage is the import path of the runtime support package used by
+// enumerations with the Runtime option.
+const runtimePackage = "github.com/creachadair/enumgen/enum"
+
+// An importTracker accumulates the packages imported by generated code.
+// The zero value is ready for use.
+type importTracker struct {
+	names map[string]string // import path → local name ("" for default)
+	err   error             // the first conflict reported, if any
+}
+
+// add registers imports of the specified package paths, using their default
+// package names.
+func (t *importTracker) add(paths ...string) {
+	for _, path := range paths {
+		t.addNamed("", path)
+	}
+}
+
+// addNamed registers an import of the specified package path with the given
+// local name. If name == "", the default package name is used.  It is an
+// error to import the same path with two different names.
+func (t *importTracker) addNamed(name, path string) {
+	if t.names == nil {
+		t.names = make(map[string]string)
+	}
+	old, ok := t.names[path]
+	if !ok {
+		t.names[path] = name
+	} else if old != name && t.err == nil {
+		t.err = fmt.Errorf("package %q imported as both %q and %q", path, old, name)
+	}
+}
+
+// specs returns the import specs registered with t, formatted as Go source
+// and sorted by import path, with the standard library packages first.  An
+// empty string separates the standard library packages from the others, if
+// there are both. It reports an error if any conflicting imports were
+// registered.
+func (t *importTracker) specs() ([]string, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	var std, other []string
+	for _, path := range slices.Sorted(maps.Keys(t.names)) {
+		spec := strconv.Quote(path)
+		if name := t.names[path]; name != "" {
+			spec = name + " " + spec
+		}
+		if isStdlib(path) {
+			std = append(std, spec)
+		} else {
+			other = append(other, spec)
+		}
+	}
+	if len(std) != 0 && len(other) != 0 {
+		std = append(std, "")
+	}
+	return append(std, other...), nil
+}
+
+// isStdlib reports whether path is the import path of a standard library
+// package, by the convention that only those lack a dot in their first path
+// element.
+func isStdlib(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+// addImports registers the packages imported by the code generated for e.
+func (e *Enum) addImports(t *importTracker) {
+	// The decoding methods report unknown strings as errors, unless the
+	// unmarshal-unknown policy accepts them.
+	unknownErr := e.UnmarshalUnknown == "" || e.UnmarshalUnknown == "error"
+	if e.Runtime {
+		if e.usesRuntime() {
+			t.add(runtimePackage)
+		}
+	} else {
+		if e.parseFunc() != "" || e.ParseList {
+			t.add("strings")
+		}
+		if e.FlagValue || e.ParseList || e.Constructors.Parse || e.Constructors.Must || (e.TextMarshal && unknownErr) {
+			t.add("fmt")
+		}
+	}
+	if e.Completions {
+		t.add("strings")
+	}
+	if e.FlagFunc {
+		t.add("flag")
+	}
+	for _, spec := range e.ExtraImports {
+		if name, path, ok := parseImport(spec); ok {
+			t.addNamed(name, path)
+		}
+	}
+	if e.Providers != nil && e.Providers.Env != "" {
+		t.add("fmt", "os")
+	}
+	if e.Formatter {
+		t.add("fmt")
+	}
+	switch e.ParseError {
+	case "sentinel":
+		t.add("errors", "fmt")
+	case "struct":
+		t.add("fmt")
+	}
+	if e.hasDisplay() {
+		t.add("strings")
+	}
+	if e.Lazy || e.UnmarshalUnknown == "keep-text" {
+		t.add("sync")
+	}
+	if e.Choices {
+		t.add("slices")
+	}
+	if e.Query {
+		t.add("net/url")
+	}
+	if e.Context {
+		t.add("context")
+	}
+	if e.LogValue {
+		t.add("log/slog")
+	}
+	if e.XML {
+		t.add("encoding/xml")
+		if unknownErr {
+			t.add("fmt")
+		}
+	}
+	if e.CBOR {
+		t.add("fmt", "math")
+	}
+	if e.SQL || e.GORM {
+		t.add("database/sql/driver", "fmt")
+	}
+	if e.GORM {
+		t.add("gorm.io/gorm", "gorm.io/gorm/schema")
+	}
+	if e.hasGRPC() {
+		t.add("google.golang.org/grpc/codes", "google.golang.org/grpc/status")
+	}
+	if e.Proto != nil && e.Proto.Import != "" {
+		t.addNamed(e.Proto.qualifier(), e.Proto.Import)
+	}
+}
+
+// usesRuntime reports whether the code generated for e calls the runtime
+// package. With the runtime option, it implements the parsing function, the
+// Parse and Must constructors, flag.Value, ParseList, and UnmarshalText.
+func (e *Enum) usesRuntime() bool {
+	return e.Runtime && (e.parseFunc() != "" || e.Constructors.Parse || e.Constructors.Must ||
+		e.FlagValue || e.ParseList || e.TextMarshal)
+}
+
+// parseImport parses an import spec of the form "path" or "name path", as
+// given in the ExtraImports of an enumeration. It reports false if spec is
+// not a valid import.
+func parseImport(spec string) (name, path string, ok bool) {
+	switch f := strings.Fields(spec); len(f) {
+	case 1:
+		path = f[0]
+	case 2:
+		name, path = f[0], f[1]
+		if name != "_" && name != "." && !token.IsIdentifier(name) {
+			return "", "", false
+		}
+	default:
+		return "", "", false
+	}
+	if path == "" || strings.ContainsAny(path, "\"'`") {
+		return "", "", false
+	}
+	return name, path, true
+}
@@ -0,0 +1,210 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// An IncludeError reports a problem resolving a file referenced by a config,
+// together with the chain of files that led to it.
+type IncludeError struct {
+	Chain []string // the paths of the files involved, outermost first
+	Err   error    // the underlying error
+}
+
+// Error satisfies the error interface.
+func (e *IncludeError) Error() string {
+	return fmt.Sprintf("%s: %v", strings.Join(e.Chain, " -> "), e.Err)
+}
+
+// Unwrap returns the underlying error of e.
+func (e *IncludeError) Unwrap() error { return e.Err }
+
+// ResolveIncludes loads the files referenced by the include field of c and
+// the values-from fields of its enumerations, and merges their contents into
+// c. The enumerations of each included file are appended to those of c, and
+// the values read from a values-from file are appended to the values of the
+// enumeration. Included files may themselves include other files. The
+// includes of the packages listed by c are also resolved.
+//
+// ResolveIncludes also reads the Markdown files referenced by the doc-file
+// fields of the enumerations and their values, and replaces each with the
+// equivalent doc comment text. Finally, it calls ExpandBases.
+//
+// The path is the file or directory from which c was read. References are
+// written with forward slashes on all platforms. A relative reference is
+// resolved relative to the directory of the file that contains it. A reference
+// beginning with "/" is resolved relative to root, or relative to the
+// directory of path if root == "".
+//
+// An included file may set only the package, enum, and include fields, and
+// its package name, if set, must match that of c. Errors resolving a file are
+// reported as an *IncludeError giving the chain of files involved.
+func (c *Config) ResolveIncludes(path, root string) error {
+	return c.resolveIncludes(localFS, path, root)
+}
+
+// ResolveIncludesFS is like ResolveIncludes, but reads the referenced files
+// from fsys. The path and root are slash-separated paths in fsys, as for the
+// [fs.FS] interface, so a root of "." denotes the root of fsys.
+func (c *Config) ResolveIncludesFS(fsys fs.FS, path, root string) error {
+	return c.resolveIncludes(fileSys{fsys: fsys}, path, root)
+}
+
+func (c *Config) resolveIncludes(fsys fileSys, path, root string) error {
+	path = fsys.clean(path)
+	dir := path
+	if fi, err := fsys.stat(path); err != nil {
+		return err
+	} else if !fi.IsDir() {
+		dir = fsys.dir(path)
+	}
+	if root == "" {
+		root = dir
+	}
+	chain := []string{path}
+	if err := resolveIncludes(fsys, c, dir, root, chain); err != nil {
+		return err
+	}
+	for _, p := range c.Packages {
+		if err := resolveIncludes(fsys, &p.Config, dir, root, chain); err != nil {
+			return err
+		}
+	}
+	return c.ExpandBases()
+}
+
+func resolveIncludes(fsys fileSys, c *Config, dir, root string, chain []string) error {
+	for _, e := range c.Enum {
+		if err := resolveDocs(fsys, &e.Doc, &e.DocFile, dir, root, chain); err != nil {
+			return err
+		}
+		for _, v := range e.Values {
+			if err := resolveDocs(fsys, &v.Doc, &v.DocFile, dir, root, chain); err != nil {
+				return err
+			}
+		}
+		if e.ValuesFrom == "" {
+			continue
+		}
+		vals, err := readValues(fsys, e.ValuesFrom, dir, root, chain)
+		if err != nil {
+			return err
+		}
+		e.Values = append(e.Values, vals...)
+		e.ValuesFrom = ""
+	}
+
+	incs := c.Include
+	c.Include = nil
+	for _, ref := range incs {
+		p, err := resolveRef(fsys, ref, dir, root)
+		if err != nil {
+			return &IncludeError{Chain: chain, Err: err}
+		}
+		next := append(slices.Clip(chain), p)
+		if slices.Contains(chain, p) {
+			return &IncludeError{Chain: next, Err: errors.New("include cycle")}
+		}
+		inc, err := readIncluded(fsys, p)
+		if err != nil {
+			return &IncludeError{Chain: next, Err: err}
+		} else if inc.Package != "" && inc.Package != c.Package {
+			return &IncludeError{Chain: next, Err: fmt.Errorf("package %q does not match %q", inc.Package, c.Package)}
+		}
+		if err := resolveIncludes(fsys, inc, fsys.dir(p), root, next); err != nil {
+			return err
+		}
+		c.Enum = append(c.Enum, inc.Enum...)
+		c.unknown = append(c.unknown, inc.unknown...)
+	}
+	return nil
+}
+
+// readIncluded reads an included config file from p, and checks that it sets
+// only the fields permitted in an included file.
+func readIncluded(fsys fileSys, p string) (*Config, error) {
+	data, err := fsys.readFile(p)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := ParseConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	cfg.setSource(p)
+	if cfg.Templates != nil || cfg.Profiles != nil || cfg.Packages != nil || cfg.Bases != nil {
+		return nil, errors.New("an included file may set only package, enum, and include")
+	}
+	return cfg, nil
+}
+
+// readValues reads a list of enumerators from the file referenced by ref.
+func readValues(fsys fileSys, ref, dir, root string, chain []string) ([]*Value, error) {
+	p, err := resolveRef(fsys, ref, dir, root)
+	if err != nil {
+		return nil, &IncludeError{Chain: chain, Err: err}
+	}
+	next := append(slices.Clip(chain), p)
+	data, err := fsys.readFile(p)
+	if err != nil {
+		return nil, &IncludeError{Chain: next, Err: err}
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var vals []*Value
+	if err := dec.Decode(&vals); err != nil {
+		return nil, &IncludeError{Chain: next, Err: err}
+	}
+	for _, v := range vals {
+		if err := resolveDocs(fsys, &v.Doc, &v.DocFile, fsys.dir(p), root, next); err != nil {
+			return nil, err
+		}
+	}
+	return vals, nil
+}
+
+// resolveDocs reads the Markdown file referenced by *file, if any, and stores
+// the equivalent doc comment text in *doc. It is an error if *doc is already
+// set. On success, *file is cleared.
+func resolveDocs(fsys fileSys, doc, file *string, dir, root string, chain []string) error {
+	if *file == "" {
+		return nil
+	} else if *doc != "" {
+		return nil // reported by Validate
+	}
+	p, err := resolveRef(fsys, *file, dir, root)
+	if err != nil {
+		return &IncludeError{Chain: chain, Err: err}
+	}
+	text, err := fsys.readFile(p)
+	if err != nil {
+		return &IncludeError{Chain: append(slices.Clip(chain), p), Err: err}
+	}
+	*doc, *file = markdownDoc(string(text)), ""
+	return nil
+}
+
+// resolveRef resolves a file reference from a config in directory dir to a
+// path in fsys. Backslashes are treated as forward slashes, so that configs
+// written on Windows are portable.
+func resolveRef(fsys fileSys, ref, dir, root string) (string, error) {
+	p := strings.ReplaceAll(ref, `\`, "/")
+	if p == "" {
+		return "", errors.New("empty file reference")
+	} else if len(p) >= 2 && p[1] == ':' {
+		return "", fmt.Errorf("file reference %q must not have a volume name", ref)
+	}
+	p = path.Clean(p)
+	if path.IsAbs(p) {
+		return fsys.join(root, p[1:]), nil
+	}
+	return fsys.join(dir, p), nil
+}
@@ -0,0 +1,225 @@
+package gen_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+// writeFiles writes the specified files, relative to dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, text := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatalf("Create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(text), 0600); err != nil {
+			t.Fatalf("Write file: %v", err)
+		}
+	}
+}
+
+func TestResolveIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"cfg/main.yml": `package: shapes
+include:
+  - sub\colors.yml
+enum:
+  - type: Shape
+    values: [{name: Circle}]
+    values-from: ../vals/shapes.yml
+`,
+		"cfg/sub/colors.yml": `package: shapes
+include: [/common/sizes.yml]
+enum:
+  - type: Color
+    values: [{name: Red}]
+`,
+		"cfg/common/sizes.yml": `enum:
+  - type: Size
+    values: [{name: Small}]
+`,
+		"vals/shapes.yml": `- name: Square
+- name: Triangle
+  text: tri
+`,
+		"alt/common/sizes.yml": `enum:
+  - type: AltSize
+    values: [{name: Large}]
+`,
+	})
+	mainPath := filepath.Join(dir, "cfg", "main.yml")
+
+	typeNames := func(cfg *gen.Config) []string {
+		var out []string
+		for _, e := range cfg.Enum {
+			out = append(out, e.Type)
+		}
+		return out
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		cfg, err := gen.ConfigFromYAML(mainPath)
+		if err != nil {
+			t.Fatalf("ConfigFromYAML: %v", err)
+		}
+		if err := cfg.Validate(); err == nil {
+			t.Error("Validate with unresolved includes did not report an error")
+		}
+		if err := cfg.ResolveIncludes(mainPath, ""); err != nil {
+			t.Fatalf("ResolveIncludes: %v", err)
+		}
+		if got, want := typeNames(cfg), []string{"Shape", "Color", "Size"}; !slices.Equal(got, want) {
+			t.Errorf("Enums: got %q, want %q", got, want)
+		}
+		var vals []string
+		for _, v := range cfg.Enum[0].Values {
+			vals = append(vals, v.Name)
+		}
+		if want := []string{"Circle", "Square", "Triangle"}; !slices.Equal(vals, want) {
+			t.Errorf("Shape values: got %q, want %q", vals, want)
+		}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Validate: %v", err)
+		}
+	})
+
+	t.Run("Root", func(t *testing.T) {
+		cfg, err := gen.ConfigFromYAML(mainPath)
+		if err != nil {
+			t.Fatalf("ConfigFromYAML: %v", err)
+		}
+		if err := cfg.ResolveIncludes(mainPath, filepath.Join(dir, "alt")); err != nil {
+			t.Fatalf("ResolveIncludes: %v", err)
+		}
+		if got, want := typeNames(cfg), []string{"Shape", "Color", "AltSize"}; !slices.Equal(got, want) {
+			t.Errorf("Enums: got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		writeFiles(t, dir, map[string]string{
+			"bad/missing.yml": "package: p\ninclude: [mid.yml]\n",
+			"bad/mid.yml":     "include: [nonesuch.yml]\n",
+			"bad/cycle.yml":   "package: p\ninclude: [cycle2.yml]\n",
+			"bad/cycle2.yml":  "include: [cycle.yml]\n",
+			"bad/volume.yml":  "package: p\ninclude: ['C:/x.yml']\n",
+			"bad/other.yml":   "package: p\ninclude: [pkg.yml]\n",
+			"bad/pkg.yml":     "package: q\n",
+		})
+		tests := []struct {
+			file  string
+			chain []string
+			msg   string
+		}{
+			{"missing.yml", []string{"missing.yml", "mid.yml", "nonesuch.yml"}, "no such file"},
+			{"cycle.yml", []string{"cycle.yml", "cycle2.yml", "cycle.yml"}, "include cycle"},
+			{"volume.yml", []string{"volume.yml"}, "volume name"},
+			{"other.yml", []string{"other.yml", "pkg.yml"}, `package "q" does not match "p"`},
+		}
+		for _, tc := range tests {
+			path := filepath.Join(dir, "bad", tc.file)
+			cfg, err := gen.ConfigFromYAML(path)
+			if err != nil {
+				t.Fatalf("ConfigFromYAML: %v", err)
+			}
+			err = cfg.ResolveIncludes(path, "")
+			var ierr *gen.IncludeError
+			if !errors.As(err, &ierr) {
+				t.Errorf("ResolveIncludes(%s): got %v, want *IncludeError", tc.file, err)
+				continue
+			}
+			var chain []string
+			for _, p := range ierr.Chain {
+				chain = append(chain, filepath.Base(p))
+			}
+			if !slices.Equal(chain, tc.chain) {
+				t.Errorf("ResolveIncludes(%s): chain is %q, want %q", tc.file, chain, tc.chain)
+			}
+			if !strings.Contains(err.Error(), tc.msg) {
+				t.Errorf("ResolveIncludes(%s): error %q does not mention %q", tc.file, err, tc.msg)
+			}
+		}
+	})
+}
+
+func TestDocFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.yml": `package: shapes
+enum:
+  - type: Shape
+    doc-file: docs/shape.md
+    values:
+      - name: Circle
+        doc-file: docs/circle.md
+    values-from: vals/more.yml
+`,
+		"docs/shape.md":  "# Shapes\n\nA Shape is a [geometric](https://example.com/geo) figure.\nIt has sides.\n\n\n## Kinds\n\n* polygons\n* curves\n\nFor example:\n\n```go\nif s.Valid() {\n    draw(s)\n}\n```\n",
+		"docs/circle.md": "A round shape.\n",
+		"vals/more.yml":  "- name: Square\n  doc-file: square.md\n",
+		"vals/square.md": "Four equal sides.\n",
+	})
+	cfg, err := gen.ConfigFromYAML(filepath.Join(dir, "main.yml"))
+	if err != nil {
+		t.Fatalf("Load config: %v", err)
+	}
+	if err := cfg.ResolveIncludes(filepath.Join(dir, "main.yml"), ""); err != nil {
+		t.Fatalf("ResolveIncludes: unexpected error: %v", err)
+	}
+
+	e := cfg.Enum[0]
+	const wantDoc = "# Shapes\n\nA Shape is a [geometric] figure.\nIt has sides.\n\n# Kinds\n\n" +
+		"  - polygons\n  - curves\n\nFor example:\n\n\tif s.Valid() {\n\t    draw(s)\n\t}\n\n[geometric]: https://example.com/geo"
+	if e.Doc != wantDoc || e.DocFile != "" {
+		t.Errorf("Shape doc: got %q, file %q; want %q", e.Doc, e.DocFile, wantDoc)
+	}
+	for i, want := range []string{"A round shape.", "Four equal sides."} {
+		if v := e.Values[i]; v.Doc != want || v.DocFile != "" {
+			t.Errorf("Value %s doc: got %q, file %q; want %q", v.Name, v.Doc, v.DocFile, want)
+		}
+	}
+
+	var buf strings.Builder
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	const wantCode = `// # Shapes
+//
+// A Shape is a [geometric] figure.
+// It has sides.
+//
+// # Kinds
+//
+//   - polygons
+//   - curves
+//
+// For example:
+//
+//	if s.Valid() {
+//	    draw(s)
+//	}
+//
+// [geometric]: https://example.com/geo
+type Shape struct{`
+	if got := buf.String(); !strings.Contains(got, wantCode) {
+		t.Errorf("Generate: output does not contain\n%s\ngot:\n%s", wantCode, got)
+	}
+
+	t.Run("Missing", func(t *testing.T) {
+		cfg := &gen.Config{Package: "shapes", Enum: []*gen.Enum{{
+			Type: "Shape", DocFile: "nonesuch.md", Values: []*gen.Value{{Name: "Circle"}},
+		}}}
+		var ierr *gen.IncludeError
+		if err := cfg.ResolveIncludes(dir, ""); !errors.As(err, &ierr) {
+			t.Errorf("ResolveIncludes: got %v, want *IncludeError", err)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+//go:build go1.27 && goexperiment.jsonv2
+
+package gen_test
+
+import (
+	"encoding/json/v2"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen/testdata"
+)
+
+func TestJSONv2(t *testing.T) {
+	type wrapper struct {
+		E testdata.E3 `json:"e"`
+	}
+	for _, tc := range []struct {
+		v    testdata.E3
+		want string
+	}{
+		{testdata.X, `{"e":"foo"}`},
+		{testdata.Y, `{"e":"bar"}`},
+		{testdata.E3{}, `{"e":"none"}`},
+	} {
+		got, err := json.Marshal(wrapper{tc.v})
+		if err != nil || string(got) != tc.want {
+			t.Errorf("Marshal(%v): got %s, %v; want %s", tc.v, got, err, tc.want)
+		}
+		var w wrapper
+		if err := json.Unmarshal(got, &w); err != nil || w.E != tc.v {
+			t.Errorf("Unmarshal(%s): got %v, %v; want %v", got, w.E, err, tc.v)
+		}
+	}
+
+	var w wrapper
+	if err := json.Unmarshal([]byte(`{"e":null}`), &w); err != nil || w.E != (testdata.E3{}) {
+		t.Errorf("Unmarshal null: got %v, %v; want zero", w.E, err)
+	}
+	for _, bad := range []string{`{"e":"baz"}`, `{"e":1}`, `{"e":["foo"]}`} {
+		if err := json.Unmarshal([]byte(bad), &w); err == nil {
+			t.Errorf("Unmarshal(%s): got %v, want error", bad, w.E)
+		}
+	}
+}
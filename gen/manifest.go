@@ -0,0 +1,105 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the name of the manifest file that FindManifest looks for.
+const ManifestFile = "enumgen.work"
+
+// A Manifest lists the configs of a module and the outputs generated from
+// them, so that every output can be generated by one command, rather than by
+// go:generate directives scattered among the packages of the module. It is
+// conventionally stored in a file named by ManifestFile at the module root:
+//
+//	configs:
+//	  - config: color/enums.yml
+//	    output: color/enums.go
+//	  - config: shapes/shapes.go
+//	    output: shapes/shapes_enum.go
+//	  - config: billing/packages.yml # lists its own packages
+type Manifest struct {
+	Configs []*ManifestEntry `json:"configs" yaml:"configs"` // the configs to generate (required)
+}
+
+// A ManifestEntry is a config listed by a Manifest.
+type ManifestEntry struct {
+	// The path of the config file, which may be a YAML, JSON, or Go source
+	// file, as for the -config flag of enumgen (required).
+	Config string `json:"config" yaml:"config"`
+
+	// The path of the output file for the top-level enumerations of the
+	// config. This may be omitted if the config defines only other packages,
+	// which have their own output paths.
+	Output string `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
+// ReadManifest reads and parses the manifest file at path. Unknown keys are
+// reported as errors. The relative paths of its entries are resolved relative
+// to the directory containing the manifest.
+func ReadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var m Manifest
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(m.Configs) == 0 {
+		return nil, fmt.Errorf("%s: no configs listed", path)
+	}
+	dir := filepath.Dir(path)
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(dir, p)
+	}
+	seen := make(map[string]int) // output path → 1-based entry
+	for i, e := range m.Configs {
+		if e.Config == "" {
+			return nil, fmt.Errorf("%s: entry %d: config path not defined", path, i+1)
+		}
+		e.Config, e.Output = resolve(e.Config), resolve(e.Output)
+		if e.Output == "" {
+			continue
+		} else if j, ok := seen[e.Output]; ok {
+			return nil, fmt.Errorf("%s: entry %d: output %q duplicates entry %d", path, i+1, e.Output, j)
+		}
+		seen[e.Output] = i + 1
+	}
+	return &m, nil
+}
+
+// FindManifest returns the path of the nearest manifest file named by
+// ManifestFile, in dir or one of its parent directories. It reports an error
+// wrapping fs.ErrNotExist if there is none.
+func FindManifest(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		path := filepath.Join(dir, ManifestFile)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no %s found: %w", ManifestFile, fs.ErrNotExist)
+		}
+		dir = parent
+	}
+}
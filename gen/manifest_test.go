@@ -0,0 +1,90 @@
+package gen_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestReadManifest(t *testing.T) {
+	dir := t.TempDir()
+	write := func(t *testing.T, text string) string {
+		t.Helper()
+		path := filepath.Join(dir, gen.ManifestFile)
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatalf("Write manifest: %v", err)
+		}
+		return path
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		path := write(t, `configs:
+  - config: color/enums.yml
+    output: color/enums.go
+  - config: /abs/packages.yml
+`)
+		m, err := gen.ReadManifest(path)
+		if err != nil {
+			t.Fatalf("ReadManifest: unexpected error: %v", err)
+		}
+		if len(m.Configs) != 2 {
+			t.Fatalf("ReadManifest: got %d configs, want 2", len(m.Configs))
+		}
+		if got, want := m.Configs[0].Config, filepath.Join(dir, "color/enums.yml"); got != want {
+			t.Errorf("Config 1: got %q, want %q", got, want)
+		}
+		if got, want := m.Configs[0].Output, filepath.Join(dir, "color/enums.go"); got != want {
+			t.Errorf("Output 1: got %q, want %q", got, want)
+		}
+		if got, want := m.Configs[1].Config, "/abs/packages.yml"; got != want {
+			t.Errorf("Config 2: got %q, want %q", got, want)
+		}
+		if got := m.Configs[1].Output; got != "" {
+			t.Errorf("Output 2: got %q, want empty", got)
+		}
+	})
+
+	for _, tc := range []struct {
+		name, text, want string
+	}{
+		{"Empty", "configs: []\n", "no configs listed"},
+		{"NoConfig", "configs:\n  - output: x.go\n", "entry 1: config path not defined"},
+		{"Unknown", "configs:\n  - config: a.yml\n    outptu: a.go\n", "outptu"},
+		{"Duplicate", "configs:\n  - {config: a.yml, output: x.go}\n  - {config: b.yml, output: x.go}\n",
+			"entry 2: output"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := gen.ReadManifest(write(t, tc.text))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("ReadManifest: got error %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestFindManifest(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gen.FindManifest(sub); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("FindManifest: got error %v, want %v", err, fs.ErrNotExist)
+	}
+	want := filepath.Join(root, gen.ManifestFile)
+	if err := os.WriteFile(want, []byte("configs: [{config: x.yml}]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := gen.FindManifest(sub)
+	if err != nil {
+		t.Fatalf("FindManifest: unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("FindManifest: got %q, want %q", got, want)
+	}
+}
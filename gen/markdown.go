@@ -0,0 +1,95 @@
+package gen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// mdLink matches an inline Markdown link, [text](url).
+var mdLink = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+
+// markdownDoc converts the Markdown text of a documentation file to the text
+// of a Go doc comment (without comment markers):
+//
+//   - Headings of any level become Go doc headings ("# Title").
+//   - Fenced code blocks become indented code blocks.
+//   - List items become indented Go doc list items, with "-" bullets.
+//   - Inline links become Go doc links, with their targets defined at the end.
+//
+// Paragraphs are separated by single blank lines. Other text is copied as-is.
+func markdownDoc(text string) string {
+	var lines, links []string
+	seen := make(map[string]bool)
+	inCode, blank := false, true
+	emit := func(s string) {
+		if s == "" {
+			if blank {
+				return // collapse runs of blank lines
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		lines = append(lines, s)
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimRight(line, " \t")
+		trim := strings.TrimSpace(line)
+		if strings.HasPrefix(trim, "```") || strings.HasPrefix(trim, "~~~") {
+			inCode = !inCode
+			if !inCode {
+				emit("")
+			}
+			continue
+		} else if inCode {
+			if line == "" {
+				lines = append(lines, "") // preserve blank lines in code
+			} else {
+				emit("\t" + line)
+			}
+			continue
+		}
+
+		trim = mdLink.ReplaceAllStringFunc(trim, func(m string) string {
+			sub := mdLink.FindStringSubmatch(m)
+			if !seen[sub[1]] {
+				seen[sub[1]] = true
+				links = append(links, fmt.Sprintf("[%s]: %s", sub[1], sub[2]))
+			}
+			return "[" + sub[1] + "]"
+		})
+		switch {
+		case trim == "":
+			emit("")
+		case strings.HasPrefix(trim, "#"):
+			title := strings.TrimSpace(strings.TrimLeft(trim, "#"))
+			emit("")
+			emit("# " + title)
+			emit("")
+		case isListItem(trim):
+			if strings.ContainsRune("*+", rune(trim[0])) {
+				trim = "-" + trim[1:]
+			}
+			emit("  " + trim)
+		default:
+			emit(trim)
+		}
+	}
+	if len(links) != 0 {
+		emit("")
+		for _, link := range links {
+			emit(link)
+		}
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// isListItem reports whether s begins with a Markdown list marker.
+func isListItem(s string) bool {
+	if len(s) > 1 && strings.ContainsRune("-*+", rune(s[0])) && s[1] == ' ' {
+		return true
+	}
+	num := strings.TrimLeft(s, "0123456789")
+	return len(num) < len(s) && (strings.HasPrefix(num, ". ") || strings.HasPrefix(num, ") "))
+}
@@ -0,0 +1,106 @@
+package gen
+
+import (
+	"go/token"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// methodUsers maps the configurable methods of an enumeration to the options
+// whose generated code calls them, which therefore cannot omit the method.
+var methodUsers = map[string][]struct {
+	option string
+	uses   func(*Enum) bool
+}{
+	"enum": {
+		{"runtime", func(e *Enum) bool { return e.Runtime }},
+	},
+	"index": {
+		{"formatter", func(e *Enum) bool { return e.Formatter }},
+		{"from-index", func(e *Enum) bool { return e.FromIndex }},
+		{"migrate", func(e *Enum) bool { return len(e.Migrate) != 0 }},
+	},
+	"string": {
+		{"runtime", func(e *Enum) bool { return e.Runtime }},
+		{"text-marshal", func(e *Enum) bool { return e.TextMarshal }},
+		{"xml", func(e *Enum) bool { return e.XML }},
+		{"json-v2", func(e *Enum) bool { return e.JSONv2 }},
+		{"cbor", func(e *Enum) bool { return e.CBOR && !e.CBORCode }},
+		{"formatter", func(e *Enum) bool { return e.Formatter }},
+		{"log-value", func(e *Enum) bool { return e.LogValue }},
+		{"sql", func(e *Enum) bool { return e.SQL || e.GORM }},
+		{"display", func(e *Enum) bool { return e.hasDisplay() }},
+		{"grpc-code", func(e *Enum) bool { return e.hasGRPC() }},
+		{"quickcheck", func(e *Enum) bool { return e.QuickCheck }},
+		{"examples", func(e *Enum) bool { return e.Examples }},
+	},
+	"valid": {
+		{"runtime", func(e *Enum) bool { return e.Runtime }},
+		{"constructors", func(e *Enum) bool { return e.Constructors.Parse || e.Constructors.Must }},
+		{"providers", func(e *Enum) bool { return e.Providers != nil && e.Providers.Env != "" }},
+		{"flag-value", func(e *Enum) bool { return e.FlagValue }},
+		{"array-index", func(e *Enum) bool { return e.ArrayIndex }},
+		{"sql", func(e *Enum) bool { return e.SQL || e.GORM }},
+		{"cbor", func(e *Enum) bool { return e.CBOR }},
+		{"quickcheck", func(e *Enum) bool { return e.QuickCheck }},
+	},
+}
+
+// methodName returns the name of the generated method of e with the given
+// key in the methods option ("enum", "index", "string", or "valid"), or ""
+// if the method is omitted.
+func (e *Enum) methodName(key string) string {
+	switch name, ok := e.Methods[key]; {
+	case !ok:
+		return mapFirst(key, unicode.ToUpper)
+	case name == "false":
+		return ""
+	default:
+		return name
+	}
+}
+
+// Method returns the name of the generated method of ed that is named name by
+// default (Enum, Index, String, or Valid), or "" if the method is omitted,
+// for use in templates.
+func (ed *EnumData) Method(name string) string {
+	return ed.Enum.methodName(strings.ToLower(name))
+}
+
+// validateMethods reports the problems with the methods settings of e.
+func validateMethods(e *Enum, report func(ValidationError, string, ...any), at func(string) ValidationError) {
+	seen := make(map[string]string)
+	for _, key := range slices.Sorted(maps.Keys(e.Methods)) {
+		users, ok := methodUsers[key]
+		if !ok {
+			report(at("methods"), "unknown method %q", key)
+			continue
+		}
+		name := e.methodName(key)
+		if name == "" && key == "string" && e.FlagValue {
+			// The flag.Value interface requires a method named String.
+			report(at("methods"), "cannot omit method %q used by flag-value", key)
+			continue
+		} else if name == "" {
+			for _, u := range users {
+				if u.uses(e) {
+					report(at("methods"), "cannot omit method %q used by %s", key, u.option)
+				}
+			}
+			continue
+		} else if !token.IsIdentifier(name) {
+			report(at("methods"), "invalid name %q for method %q", name, key)
+			continue
+		} else if e.Runtime && name != mapFirst(key, unicode.ToUpper) && key != "index" {
+			report(at("methods"), "cannot rename method %q with runtime", key)
+		} else if e.FlagValue && key == "string" && name != "String" {
+			report(at("methods"), "cannot rename method %q with flag-value", key)
+		}
+		if prev, ok := seen[name]; ok {
+			report(at("methods"), "methods %q and %q have the same name %q", prev, key, name)
+		}
+		seen[name] = key
+	}
+}
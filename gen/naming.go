@@ -0,0 +1,106 @@
+package gen
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// nameStyles lists the named styles for the variable names of enumerators.
+var nameStyles = []string{"", "exported", "camel", "screaming-snake"}
+
+// valueName returns the variable name of the enumerator of e with the given
+// base name, according to the prefix, suffix, name style, and visibility of e.
+func (e *Enum) valueName(name string) string {
+	full := e.styleName(name)
+	if e.Unexported {
+		return mapFirst(full, unicode.ToLower)
+	}
+	return full
+}
+
+// styleName returns the variable name of the enumerator of e with the given
+// base name, according to the prefix, suffix, and name style of e.
+func (e *Enum) styleName(name string) string {
+	parts := []string{e.Prefix, name, e.Suffix}
+	switch style := e.NameStyle; style {
+	case "":
+		return strings.Join(parts, "")
+	case "exported", "camel":
+		var sb strings.Builder
+		for _, p := range parts {
+			if sb.Len() == 0 && style == "camel" {
+				sb.WriteString(mapFirst(p, unicode.ToLower))
+			} else {
+				sb.WriteString(mapFirst(p, unicode.ToUpper))
+			}
+		}
+		return sb.String()
+	case "screaming-snake":
+		var words []string
+		for _, p := range parts {
+			for _, w := range strings.Split(snakeCase(p), "_") {
+				if w != "" {
+					words = append(words, strings.ToUpper(w))
+				}
+			}
+		}
+		return strings.Join(words, "_")
+	default:
+		return strings.NewReplacer(
+			"{type}", e.Type, "{prefix}", e.Prefix, "{name}", name, "{suffix}", e.Suffix,
+		).Replace(style)
+	}
+}
+
+// goType returns the name of the generated type for e.
+func (e *Enum) goType() string {
+	if e.Unexported {
+		return mapFirst(e.Type, unicode.ToLower)
+	}
+	return e.Type
+}
+
+// ident returns the name of a package-level symbol for e that is formed by
+// prefixing the type name with a word, such as "Parse". If e is unexported,
+// the prefix is lowered instead of the type name.
+func (e *Enum) ident(prefix string) string {
+	if e.Unexported {
+		return mapFirst(prefix, unicode.ToLower) + mapFirst(e.Type, unicode.ToUpper)
+	}
+	return prefix + e.Type
+}
+
+// Ident returns the name of a package-level symbol for ed that is formed by
+// prefixing the type name with a word, such as "Parse", for use in templates.
+func (ed *EnumData) Ident(prefix string) string { return ed.Enum.ident(prefix) }
+
+// isNameTemplate reports whether style is a template for variable names
+// rather than the name of a style.
+func isNameTemplate(style string) bool { return strings.Contains(style, "{name}") }
+
+// mapFirst returns s with f applied to its first rune.
+func mapFirst(s string, f func(rune) rune) string {
+	r, n := utf8.DecodeRuneInString(s)
+	if n == 0 {
+		return s
+	}
+	return string(f(r)) + s[n:]
+}
+
+// predicateName returns the name of the predicate method for the enumerator
+// with the given base name, generated by the predicates option.
+func predicateName(name string) string { return "Is" + mapFirst(name, unicode.ToUpper) }
+
+// Predicate returns the name of the predicate method for v, generated by the
+// predicates option, for use in templates.
+func (v *ValueData) Predicate() string {
+	if v.Value == nil {
+		return predicateName(v.Enum.Zero) // an implicit zero enumerator
+	}
+	return predicateName(v.Value.Name)
+}
+
+// VarName returns the variable name of the enumerator of ed with the given
+// base name (the Name field of its config), for use in templates.
+func (ed *EnumData) VarName(name string) string { return ed.Enum.valueName(name) }
@@ -0,0 +1,152 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+)
+
+// A PackageConfig is the config for one of several packages generated from a
+// single config file, as listed in the packages field of a Config.
+type PackageConfig struct {
+	// The path of the output file for the package (required). A relative path
+	// is interpreted relative to the directory containing the config file.
+	Output string `json:"output" yaml:"output"`
+
+	// The settings for the package. The packages field may not be set.
+	Config `yaml:",inline"`
+}
+
+// PackageConfigs returns the config for each package listed in c, in order.
+// Each package inherits the templates and profiles of c, except those it
+// defines itself, the name policy and the Format and AfterGenerate functions
+// of c if it has none, and the NoFormat setting of c if it is true. The
+// results are shallow copies, sharing their enumerations with the packages
+// of c.
+func (c *Config) PackageConfigs() []*PackageConfig {
+	out := make([]*PackageConfig, len(c.Packages))
+	for i, p := range c.Packages {
+		cp := *p
+		cp.Templates = inherit(c.Templates, p.Templates)
+		cp.Profiles = inherit(c.Profiles, p.Profiles)
+		if cp.Format == nil {
+			cp.Format = c.Format
+		}
+		if cp.AfterGenerate == nil {
+			cp.AfterGenerate = c.AfterGenerate
+		}
+		if cp.NamePolicy == nil {
+			cp.NamePolicy = c.NamePolicy
+		}
+		cp.NoFormat = cp.NoFormat || c.NoFormat
+		out[i] = &cp
+	}
+	return out
+}
+
+// inherit returns a map containing the entries of base updated by the entries
+// of over. The inputs are not modified.
+func inherit[M ~map[K]V, K comparable, V any](base, over M) M {
+	if len(base) == 0 {
+		return over
+	}
+	out := maps.Clone(base)
+	maps.Copy(out, over)
+	return out
+}
+
+// A CollisionError reports a package-level name that the generated code of
+// two outputs in the same Go package would both declare, as found by
+// CheckOutputs.
+type CollisionError struct {
+	Name    string    // the colliding name
+	Outputs [2]string // the paths of the outputs, in order
+	Origins [2]string // what declares the name in each output
+}
+
+// Error satisfies the error interface.
+func (e *CollisionError) Error() string {
+	return fmt.Sprintf("%s: name %q of %s conflicts with the same name of %s in %s",
+		e.Outputs[1], e.Name, e.Origins[1], e.Origins[0], e.Outputs[0])
+}
+
+// CheckOutputs reports whether the outputs of pcs that belong to the same Go
+// package, because their output paths are in the same directory, can be
+// compiled together. It reports an error if they have different package
+// names, and a *CollisionError for each pair of enumerations, or other
+// generated declarations, whose package-level names collide. These include
+// the names that Validate reserves for each enumeration. The output paths
+// are compared after cleaning, so relative paths must be relative to the
+// same directory.
+//
+// Validate checks each config alone; CheckOutputs finds the problems that
+// would otherwise be reported only when the package is compiled.
+func CheckOutputs(pcs []*PackageConfig) error {
+	type decl struct {
+		output int    // index in pcs
+		origin string // what declares the name
+	}
+	var errs []error
+	dirs := make(map[string][]int) // directory → indexes in pcs
+	for i, pc := range pcs {
+		dir := filepath.Dir(filepath.Clean(pc.Output))
+		dirs[dir] = append(dirs[dir], i)
+	}
+	for _, dir := range slices.Sorted(maps.Keys(dirs)) {
+		outs := dirs[dir]
+		if len(outs) < 2 {
+			continue
+		}
+		first := pcs[outs[0]]
+		seen := make(map[string]decl)
+		type originPair struct{ a, b string }
+		reported := make(map[originPair]bool)
+		for _, i := range outs {
+			pc := pcs[i]
+			if pc.Package != first.Package {
+				errs = append(errs, fmt.Errorf("%s: package %q conflicts with package %q of %s",
+					pc.Output, pc.Package, first.Package, first.Output))
+				continue
+			}
+			syms := pc.Config.symbols()
+			for _, name := range slices.Sorted(maps.Keys(syms)) {
+				prev, ok := seen[name]
+				if !ok {
+					seen[name] = decl{i, syms[name]}
+					continue
+				} else if prev.output == i {
+					continue
+				}
+				op := originPair{prev.origin, syms[name]}
+				if reported[op] {
+					continue // one report for each pair of origins suffices
+				}
+				reported[op] = true
+				errs = append(errs, &CollisionError{
+					Name:    name,
+					Outputs: [2]string{pcs[prev.output].Output, pc.Output},
+					Origins: [2]string{prev.origin, syms[name]},
+				})
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// symbols returns the package-level names declared by the generated code for
+// c, including the names reserved by generatedNames, mapped to a description
+// of their origin.
+func (c *Config) symbols() map[string]string {
+	syms := c.generatedNames()
+	for _, e := range c.Enum {
+		for _, v := range e.Values {
+			syms[e.valueName(v.Name)] = fmt.Sprintf("enumerator %q of %q", v.Name, e.Type)
+		}
+		if full := e.valueName(e.Zero); e.Zero != "" && syms[full] == "" {
+			syms[full] = fmt.Sprintf("enumerator %q of %q", e.Zero, e.Type)
+		}
+	}
+	return syms
+}
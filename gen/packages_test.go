@@ -0,0 +1,152 @@
+package gen_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+const packagesConfig = `
+templates:
+  enum-extra: "// extra for {{.Type}}\n"
+profiles:
+  dev:
+    enum:
+      naming: hashed
+packages:
+  - package: billing
+    output: billing/enums.go
+    enum:
+      - type: Plan
+        values: [{name: Free}, {name: Pro}]
+
+  - package: shipping
+    output: shipping/enums.go
+    templates:
+      enum-extra: ""
+    enum:
+      - type: Carrier
+        values: [{name: Post}, {name: Courier}]
+`
+
+func TestPackageConfigs(t *testing.T) {
+	cfg, err := gen.ParseConfig(strings.NewReader(packagesConfig))
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err == nil {
+		t.Error("Generate with no top-level enumerations did not report an error")
+	}
+
+	pcs := cfg.PackageConfigs()
+	if len(pcs) != 2 {
+		t.Fatalf("PackageConfigs: got %d, want 2", len(pcs))
+	}
+	for i, want := range []struct {
+		pkg, output, extra string
+	}{
+		{"billing", "billing/enums.go", "// extra for Plan"},
+		{"shipping", "shipping/enums.go", ""},
+	} {
+		pc := pcs[i]
+		if pc.Package != want.pkg || pc.Output != want.output {
+			t.Errorf("Package %d: got (%q, %q), want (%q, %q)", i+1, pc.Package, pc.Output, want.pkg, want.output)
+		}
+		if err := pc.ApplyProfile("dev"); err != nil {
+			t.Errorf("Package %d: ApplyProfile: %v", i+1, err)
+		}
+		buf.Reset()
+		if err := pc.Generate(&buf); err != nil {
+			t.Fatalf("Package %d: Generate: %v", i+1, err)
+		}
+		got := buf.String()
+		if !strings.Contains(got, "package "+want.pkg+"\n") {
+			t.Errorf("Package %d: wrong package clause:\n%s", i+1, got)
+		}
+		if !strings.Contains(got, "_str_"+pc.Enum[0].Type+"_") {
+			t.Errorf("Package %d: profile was not applied:\n%s", i+1, got)
+		}
+		if want.extra != "" && !strings.Contains(got, want.extra) {
+			t.Errorf("Package %d: missing inherited template output %q:\n%s", i+1, want.extra, got)
+		}
+	}
+	if len(cfg.Templates) != 1 || cfg.Templates["enum-extra"] == "" {
+		t.Errorf("Top-level templates were modified: %q", cfg.Templates)
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		cfg.Packages[1].Output = ""
+		cfg.Packages[1].Enum[0].Type = ""
+		err := cfg.Validate()
+		if err == nil {
+			t.Fatal("Validate: got nil, want error")
+		}
+		for _, want := range []string{
+			"package 2: output path not defined",
+			"package 2: enum 1: type name not defined",
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("Validate: error %q does not contain %q", err, want)
+			}
+		}
+	})
+}
+
+func TestCheckOutputs(t *testing.T) {
+	pkg := func(output, pkg string, enums ...*gen.Enum) *gen.PackageConfig {
+		return &gen.PackageConfig{Output: output, Config: gen.Config{Package: pkg, Enum: enums}}
+	}
+	color := &gen.Enum{Type: "Color", Values: []*gen.Value{{Name: "Red"}, {Name: "Blue"}}}
+	hue := &gen.Enum{Type: "Hue", Values: []*gen.Value{{Name: "Red"}, {Name: "Green"}}}
+	size := &gen.Enum{Type: "Size", Values: []*gen.Value{{Name: "Small"}}}
+
+	// Outputs in different directories may declare the same names.
+	if err := gen.CheckOutputs([]*gen.PackageConfig{
+		pkg("a/colors.go", "a", color),
+		pkg("b/colors.go", "b", color),
+		pkg("a/sizes.go", "a", size),
+	}); err != nil {
+		t.Errorf("CheckOutputs: unexpected error: %v", err)
+	}
+
+	err := gen.CheckOutputs([]*gen.PackageConfig{
+		pkg("a/colors.go", "a", color),
+		pkg("a/../a/hues.go", "a", hue),
+		pkg("a/sizes.go", "other", size),
+	})
+	var cerr *gen.CollisionError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("CheckOutputs: got %v, want CollisionError", err)
+	}
+	want := gen.CollisionError{
+		Name:    "Red",
+		Outputs: [2]string{"a/colors.go", "a/../a/hues.go"},
+		Origins: [2]string{`enumerator "Red" of "Color"`, `enumerator "Red" of "Hue"`},
+	}
+	if *cerr != want {
+		t.Errorf("CheckOutputs: got %+v, want %+v", cerr, want)
+	}
+	if got := err.Error(); !strings.Contains(got, `a/sizes.go: package "other" conflicts with package "a" of a/colors.go`) {
+		t.Errorf("CheckOutputs: error does not report the package mismatch:\n%s", got)
+	}
+
+	// The same enumeration in two outputs is reported once for the type, and
+	// once for the names generated for it, rather than once for each name.
+	err = gen.CheckOutputs([]*gen.PackageConfig{
+		pkg("a/x.go", "a", color),
+		pkg("a/y.go", "a", color),
+	})
+	if err == nil {
+		t.Fatal("CheckOutputs: got nil, want error")
+	}
+	if got := strings.Split(err.Error(), "\n"); len(got) != 4 {
+		t.Errorf("CheckOutputs: got %d errors, want 4 (type, generated names, Red, Blue):\n%s", len(got), err)
+	}
+}
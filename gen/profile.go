@@ -0,0 +1,82 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// A Profile is a named set of option overrides for a Config, for example to
+// select different options for development and release builds. Each field is
+// a map of YAML keys and values, in the same format as the config file.
+type Profile struct {
+	// Overrides for the options of every enumeration, for example:
+	//
+	//   enum:
+	//     naming: grouped
+	//
+	// The "type" and "values" keys may not be overridden.
+	Enum map[string]any `json:"enum,omitempty" yaml:"enum,omitempty"`
+
+	// Overrides for the top-level settings of the config, for example:
+	//
+	//   config:
+	//     templates:
+	//       enum-extra: ""
+	//
+	// The "package", "enum", "packages", and "profiles" keys may not be
+	// overridden.
+	Config map[string]any `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+// ApplyProfile applies the overrides of the named profile to c.  It reports an
+// error if c does not define a profile with that name, or if the profile sets
+// an unknown or prohibited option.
+func (c *Config) ApplyProfile(name string) error {
+	p, ok := c.Profiles[name]
+	if !ok {
+		if len(c.Profiles) == 0 {
+			return fmt.Errorf("profile %q not defined (no profiles are defined)", name)
+		}
+		return fmt.Errorf("profile %q not defined (want one of %s)",
+			name, strings.Join(slices.Sorted(maps.Keys(c.Profiles)), ", "))
+	}
+	if p == nil {
+		return nil // empty profile
+	}
+	if err := overlay(c, p.Config, "package", "enum", "packages", "profiles"); err != nil {
+		return fmt.Errorf("profile %q: config: %w", name, err)
+	}
+	for _, e := range c.Enum {
+		if err := overlay(e, p.Enum, "type", "values"); err != nil {
+			return fmt.Errorf("profile %q: enum %q: %w", name, e.Type, err)
+		}
+	}
+	return nil
+}
+
+// overlay decodes the YAML encoding of opts into v, replacing only the fields
+// of v whose keys are present in opts. It reports an error if opts contains
+// any of the forbidden keys, or any key that does not correspond to a field
+// of v.
+func overlay(v any, opts map[string]any, forbidden ...string) error {
+	if len(opts) == 0 {
+		return nil
+	}
+	for _, key := range forbidden {
+		if _, ok := opts[key]; ok {
+			return fmt.Errorf("option %q may not be overridden", key)
+		}
+	}
+	bits, err := yaml.Marshal(opts)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(bits))
+	dec.KnownFields(true)
+	return dec.Decode(v)
+}
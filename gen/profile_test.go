@@ -0,0 +1,80 @@
+package gen_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestApplyProfile(t *testing.T) {
+	const config = `package: foo
+enum:
+  - type: Color
+    constructor: true
+    values:
+      - name: Red
+  - type: Size
+    naming: hashed
+    values:
+      - name: Small
+profiles:
+  release:
+    enum:
+      naming: grouped
+    config:
+      templates:
+        enum-extra: "// extra"
+  bad-key:
+    enum:
+      values: []
+  typo:
+    enum:
+      flagvalue: true
+  empty:
+`
+	load := func(t *testing.T) *gen.Config {
+		t.Helper()
+		cfg, err := gen.ParseConfig(strings.NewReader(config))
+		if err != nil {
+			t.Fatalf("Parse config: %v", err)
+		}
+		return cfg
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		cfg := load(t)
+		if err := cfg.ApplyProfile("release"); err != nil {
+			t.Fatalf("ApplyProfile: unexpected error: %v", err)
+		}
+		for _, e := range cfg.Enum {
+			if e.Naming != "grouped" {
+				t.Errorf("Enum %q: naming is %q, want grouped", e.Type, e.Naming)
+			}
+		}
+		if !cfg.Enum[0].Constructor {
+			t.Error("Enum Color: constructor setting was not preserved")
+		}
+		if got := cfg.Templates["enum-extra"]; got != "// extra" {
+			t.Errorf("Template enum-extra: got %q, want %q", got, "// extra")
+		}
+		if err := cfg.ApplyProfile("empty"); err != nil {
+			t.Errorf("ApplyProfile(empty): unexpected error: %v", err)
+		}
+	})
+
+	for _, tc := range []struct {
+		profile, want string
+	}{
+		{"nonesuch", `profile "nonesuch" not defined`},
+		{"bad-key", `option "values" may not be overridden`},
+		{"typo", "field flagvalue not found"},
+	} {
+		t.Run(tc.profile, func(t *testing.T) {
+			err := load(t).ApplyProfile(tc.profile)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("ApplyProfile(%q): got %v, want %q", tc.profile, err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package gen
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+// ProvenanceDirective is the prefix of the comment line recording the
+// provenance of a generated file. The rest of the line is a JSON object
+// encoding a [Provenance] value. Every generated file records at least the
+// content hash of its config.
+const ProvenanceDirective = "//enumgen:provenance "
+
+// A Provenance records how a generated file was produced.
+type Provenance struct {
+	Version string `json:"version,omitempty"` // the version of the generator
+	Config  string `json:"config,omitempty"`  // the path of the config file
+
+	// The content hash of the config, as computed by Config.ContentHash.
+	// This is set by the generator.
+	Hash string `json:"hash,omitempty"`
+}
+
+// ReadProvenance returns the provenance recorded in the header of src, the
+// source of a generated file. The Version and Config fields are empty unless
+// the Provenance field of the config was set. It reports an error if src does
+// not record its provenance, for example if it was generated by an earlier
+// version of enumgen.
+func ReadProvenance(src []byte) (*Provenance, error) {
+	sc := bufio.NewScanner(bytes.NewReader(src))
+	for sc.Scan() {
+		line := sc.Text()
+		if rest, ok := strings.CutPrefix(line, ProvenanceDirective); ok {
+			var p Provenance
+			if err := json.Unmarshal([]byte(rest), &p); err != nil {
+				return nil, fmt.Errorf("invalid provenance: %w", err)
+			}
+			return &p, nil
+		} else if strings.HasPrefix(line, "package ") {
+			break
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("provenance not found")
+}
+
+// ContentHash returns a hash of the canonical form of c, including any
+// templates it defines, as "sha256:" followed by the hex digest. The hash does
+// not depend on the Provenance and Format fields, nor on the formatting and
+// comments of the config file. Generated files whose recorded hash differs
+// from that of their config are stale.
+//
+// The hash does not cover the built-in templates, so it does not change when
+// the generator is updated. Use the Version recorded with Config.Provenance
+// to detect outputs from a different version of the generator.
+func (c *Config) ContentHash() (string, error) {
+	cp := *c
+	cp.Provenance = nil
+	h := sha256.New()
+	if err := cp.WriteYAML(h); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// IsStale reports whether generatedSrc, the source of a generated file, is
+// stale with respect to a config whose content hash is configHash, as computed
+// by Config.ContentHash. A file is stale if the hash recorded in its header
+// differs from configHash, or if it does not record a hash.
+func IsStale(configHash string, generatedSrc []byte) bool {
+	p, err := ReadProvenance(generatedSrc)
+	return err != nil || p.Hash != configHash
+}
+
+// provenance returns the text of the provenance directive for c. It records
+// the content hash of c, and the other fields of c.Provenance, if set.
+func (c *Config) provenance() (string, error) {
+	var p Provenance
+	if c.Provenance != nil {
+		p = *c.Provenance
+	}
+	hash, err := c.ContentHash()
+	if err != nil {
+		return "", err
+	}
+	p.Hash = hash
+	bits, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return ProvenanceDirective + string(bits), nil
+}
+
+// GeneratorVersion returns the version of the enumgen module linked into the
+// running program, as reported by its build information, or "(devel)" if the
+// version is not known.
+func GeneratorVersion() string {
+	const modPath = "github.com/creachadair/enumgen"
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if bi.Main.Path == modPath && bi.Main.Version != "" {
+		return bi.Main.Version
+	}
+	for _, dep := range bi.Deps {
+		if dep.Path == modPath {
+			return dep.Version
+		}
+	}
+	return "(devel)"
+}
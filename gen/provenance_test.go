@@ -0,0 +1,100 @@
+package gen_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestProvenance(t *testing.T) {
+	cfg := &gen.Config{
+		Package:    "foo",
+		Enum:       []*gen.Enum{{Type: "Mood", Values: []*gen.Value{{Name: "Happy"}}}},
+		Provenance: &gen.Provenance{Version: "v1.2.3", Config: "path/to/enums.yml"},
+	}
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	hash, err := cfg.ContentHash()
+	if err != nil {
+		t.Fatalf("ContentHash: %v", err)
+	}
+	if !strings.HasPrefix(hash, "sha256:") {
+		t.Errorf("ContentHash: got %q, want sha256 digest", hash)
+	}
+	want := "// Code generated by enumgen. DO NOT EDIT.\n" + gen.ProvenanceDirective +
+		`{"version":"v1.2.3","config":"path/to/enums.yml","hash":"` + hash + `"}` + "\n"
+	if !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("Generate: output does not begin with\n%s\ngot:\n%s", want, buf.String())
+	}
+
+	got, err := gen.ReadProvenance(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ReadProvenance: unexpected error: %v", err)
+	}
+	if *got != (gen.Provenance{Version: "v1.2.3", Config: "path/to/enums.yml", Hash: hash}) {
+		t.Errorf("ReadProvenance: got %+v", got)
+	}
+
+	// The hash does not depend on the provenance, but does depend on the
+	// settings and templates of the config.
+	cfg.Provenance = nil
+	if h, _ := cfg.ContentHash(); h != hash {
+		t.Errorf("ContentHash without provenance: got %q, want %q", h, hash)
+	}
+	cfg.Enum[0].Values = append(cfg.Enum[0].Values, &gen.Value{Name: "Sad"})
+	if h, _ := cfg.ContentHash(); h == hash {
+		t.Error("ContentHash did not change when a value was added")
+	}
+	cfg.Enum[0].Values = cfg.Enum[0].Values[:1]
+	cfg.Templates = map[string]string{"enum-extra": "// extra\n"}
+	if h, _ := cfg.ContentHash(); h == hash {
+		t.Error("ContentHash did not change when a template was added")
+	}
+
+	// Without provenance, the output still records the content hash.
+	buf.Reset()
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	newHash, _ := cfg.ContentHash()
+	if p, err := gen.ReadProvenance(buf.Bytes()); err != nil {
+		t.Errorf("ReadProvenance: unexpected error: %v", err)
+	} else if *p != (gen.Provenance{Hash: newHash}) {
+		t.Errorf("ReadProvenance: got %+v, want only hash %q", p, newHash)
+	}
+	if p, err := gen.ReadProvenance([]byte("package foo\n")); err == nil {
+		t.Errorf("ReadProvenance: got %+v, want error", p)
+	}
+}
+
+func TestIsStale(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum:    []*gen.Enum{{Type: "Mood", Values: []*gen.Value{{Name: "Happy"}}}},
+	}
+	var buf bytes.Buffer
+	if err := cfg.Generate(&buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	src := buf.Bytes()
+	hash, err := cfg.ContentHash()
+	if err != nil {
+		t.Fatalf("ContentHash: %v", err)
+	}
+	if gen.IsStale(hash, src) {
+		t.Error("IsStale: got true for freshly generated output")
+	}
+
+	cfg.Enum[0].Values = append(cfg.Enum[0].Values, &gen.Value{Name: "Sad"})
+	newHash, _ := cfg.ContentHash()
+	if !gen.IsStale(newHash, src) {
+		t.Error("IsStale: got false after a value was added")
+	}
+	if !gen.IsStale(hash, []byte("// Code generated by enumgen. DO NOT EDIT.\n\npackage foo\n")) {
+		t.Error("IsStale: got false for output without a hash")
+	}
+}
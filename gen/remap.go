@@ -0,0 +1,43 @@
+package gen
+
+// CompactIndexes removes the gaps from the indices of the enumerators of c
+// and of the packages listed by c, so that the non-zero enumerators of each
+// enumeration are numbered consecutively in order from its index base. The
+// old index of each enumerator whose index changed is recorded in the Migrate
+// field of its enumeration, replacing any previous record of the same index,
+// so that the generated code can translate stored indices.
+//
+// CompactIndexes returns a report of the indices that were changed. It does
+// not resolve includes, so enumerators read by ResolveIncludes from a
+// values-from file are not affected.
+func (c *Config) CompactIndexes() Report {
+	out := compactIndexes(c)
+	for _, p := range c.Packages {
+		out = append(out, compactIndexes(&p.Config)...)
+	}
+	return out
+}
+
+func compactIndexes(c *Config) Report {
+	var out Report
+	for _, e := range c.Enum {
+		_, rest := e.extractZero()
+		base := e.indexBase()
+		idx, _ := e.indices(rest)
+		for i, v := range rest {
+			v.Index = nil
+			if idx[i] == i+base {
+				continue
+			}
+			if e.Migrate == nil {
+				e.Migrate = make(map[int]string)
+			}
+			e.Migrate[idx[i]] = v.Name
+			out = append(out, Change{
+				Kind: IndexChanged, Type: e.Type, Name: v.Name,
+				OldIndex: idx[i], NewIndex: i + base,
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+package gen_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestCompactIndexes(t *testing.T) {
+	cfg, err := gen.ParseConfig(strings.NewReader(`package: foo
+enum:
+  - type: Color
+    zero: Unknown
+    migrate:
+      9: Blue
+    values:
+      - name: Red
+      - name: Green
+        index: 4
+      - name: Unknown
+      - name: Blue
+  - type: Size
+    values:
+      - name: Small
+      - name: Large
+packages:
+  - package: bar
+    output: bar/enums.go
+    enum:
+      - type: Mode
+        values:
+          - name: Fast
+            index: 3
+`))
+	if err != nil {
+		t.Fatalf("Parse config: %v", err)
+	}
+	got := cfg.CompactIndexes().String()
+	const want = `Color.Green: index changed from 4 to 2
+Color.Blue: index changed from 5 to 3
+Mode.Fast: index changed from 3 to 1
+`
+	if got != want {
+		t.Errorf("CompactIndexes: got\n%s\nwant\n%s", got, want)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: unexpected error: %v", err)
+	}
+
+	var buf strings.Builder
+	if err := cfg.WriteYAML(&buf); err != nil {
+		t.Fatalf("WriteYAML: %v", err)
+	}
+	const wantYAML = `  - type: Color
+    values:
+      - name: Red
+      - name: Green
+      - name: Unknown
+      - name: Blue
+    zero: Unknown
+    migrate:
+      4: Green
+      5: Blue
+      9: Blue
+`
+	if !strings.Contains(buf.String(), wantYAML) {
+		t.Errorf("WriteYAML: output does not contain\n%s\ngot:\n%s", wantYAML, buf.String())
+	}
+
+	if r := cfg.CompactIndexes(); len(r) != 0 {
+		t.Errorf("CompactIndexes again: got %v, want empty", r)
+	}
+}
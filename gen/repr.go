@@ -0,0 +1,69 @@
+package gen
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// The helpers in this file abstract over the representation of an enumeration
+// type and its string table for templates. With the default representation, the struct field is
+// the ordinal of the enumerator in the string table. With the pointer
+// representation, the field points to an interned entry of the entry table,
+// which records the string and the ordinal of the enumerator, and the zero
+// enumerator has a nil pointer.
+
+// Elem returns the contents of a composite literal of the enumeration type
+// for the enumerator with the given ordinal.
+func (ed *EnumData) Elem(ord int) string {
+	if !ed.Pointer {
+		return strconv.Itoa(ord)
+	} else if ord == 0 {
+		return ""
+	}
+	return fmt.Sprintf("&%s[%d]", ed.Entries, ord)
+}
+
+// Make returns an expression for the enumerator whose ordinal is given by the
+// integer expression ord, which must denote a valid non-zero ordinal.
+func (ed *EnumData) Make(ord string) string {
+	if ed.Pointer {
+		return fmt.Sprintf("%s{&%s[%s]}", ed.Type, ed.Entries, ord)
+	}
+	return fmt.Sprintf("%s{%s(%s)}", ed.Type, ed.Base, ord)
+}
+
+// Ord returns an expression for the ordinal of the enumerator denoted by the
+// expression v, for use as an index into the tables of the enumeration.
+func (ed *EnumData) Ord(v string) string {
+	if ed.Pointer {
+		return v + "." + ed.Field + ".ordinal()"
+	}
+	return v + "." + ed.Field
+}
+
+// The string table of an enumeration is a slice of the strings of its
+// enumerators, indexed by ordinal. With the packed string table, the strings
+// are concatenated into a single string, and an offset table gives the start
+// of the string for each ordinal, followed by the total length.
+
+// Str returns an expression for the string of the enumerator whose ordinal is
+// given by the integer expression ord.
+func (ed *EnumData) Str(ord string) string {
+	if ed.Packed {
+		next := ord + "+1"
+		if n, err := strconv.Atoi(ord); err == nil {
+			next = strconv.Itoa(n + 1)
+		}
+		return fmt.Sprintf("%[1]s[%[2]s[%[3]s]:%[2]s[%[4]s]]", ed.Strs, ed.StrOffs, ord, next)
+	}
+	return fmt.Sprintf("%s[%s]", ed.Strs, ord)
+}
+
+// NumStrs returns an expression for the number of entries in the string
+// table, including the zero enumerator.
+func (ed *EnumData) NumStrs() string {
+	if ed.Packed {
+		return "(len(" + ed.StrOffs + ") - 1)"
+	}
+	return "len(" + ed.Strs + ")"
+}
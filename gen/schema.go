@@ -0,0 +1,98 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// A SchemaFormat identifies an output format for EmitSchema.
+type SchemaFormat int
+
+// Constants defining the supported schema formats.
+const (
+	// JSONSchema emits a JSON Schema (draft 2020-12) document in JSON, in
+	// which each enumeration is defined in the "$defs" section.
+	JSONSchema SchemaFormat = iota
+
+	// OpenAPI emits an OpenAPI document fragment in YAML, in which each
+	// enumeration is defined in the "components.schemas" section.
+	OpenAPI
+)
+
+// String returns a human-readable name for f.
+func (f SchemaFormat) String() string {
+	switch f {
+	case JSONSchema:
+		return "JSONSchema"
+	case OpenAPI:
+		return "OpenAPI"
+	default:
+		return fmt.Sprintf("SchemaFormat(%d)", f)
+	}
+}
+
+// schemaDef is the schema definition of a single enumeration.
+type schemaDef struct {
+	Type        string   `json:"type" yaml:"type"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Enum        []string `json:"enum" yaml:"enum"`
+	Extensible  bool     `json:"x-extensible,omitempty" yaml:"x-extensible,omitempty"`
+}
+
+// schemaDef returns the schema definition for e, which lists the strings of
+// its valid (non-zero) enumerators.
+func (e *Enum) schemaDef() schemaDef {
+	_, rest := e.extractZero()
+	rest, _, _ = e.sortValues(rest)
+	def := schemaDef{
+		Type:        "string",
+		Description: injectName(e.Doc, e.Type),
+		Extensible:  e.Extensible,
+	}
+	for _, v := range rest {
+		def.Enum = append(def.Enum, v.label())
+	}
+	return def
+}
+
+// EmitSchema writes a schema document in the specified format to w, defining
+// each enumeration in c as a string type whose permitted values are the
+// strings of its valid enumerators. The definitions are named by the type
+// names of the enumerations.
+func (c *Config) EmitSchema(w io.Writer, format SchemaFormat) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	defs := make(map[string]schemaDef)
+	for _, e := range c.Enum {
+		defs[e.Type] = e.schemaDef()
+	}
+	switch format {
+	case JSONSchema:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Schema string               `json:"$schema"`
+			Defs   map[string]schemaDef `json:"$defs"`
+		}{Schema: "https://json-schema.org/draft/2020-12/schema", Defs: defs})
+
+	case OpenAPI:
+		type schemas struct {
+			Schemas map[string]schemaDef `yaml:"schemas"`
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(struct {
+			Components schemas `yaml:"components"`
+		}{Components: schemas{Schemas: defs}}); err != nil {
+			return err
+		}
+		return enc.Close()
+
+	default:
+		return fmt.Errorf("unknown schema format %v", format)
+	}
+}
@@ -0,0 +1,84 @@
+package gen_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestEmitSchema(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{{
+			Type: "Color",
+			Doc:  "A {name} is a colour.",
+			Zero: "Unknown",
+			Values: []*gen.Value{
+				{Name: "Red", Text: "red"},
+				{Name: "Unknown", Text: "?"},
+				{Name: "Green"},
+			},
+		}, {
+			Type:       "Size",
+			Extensible: true,
+			Values:     []*gen.Value{{Name: "Small"}},
+		}},
+	}
+
+	tests := []struct {
+		format gen.SchemaFormat
+		want   string
+	}{
+		{gen.JSONSchema, `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$defs": {
+    "Color": {
+      "type": "string",
+      "description": "A Color is a colour.",
+      "enum": [
+        "red",
+        "Green"
+      ]
+    },
+    "Size": {
+      "type": "string",
+      "enum": [
+        "Small"
+      ],
+      "x-extensible": true
+    }
+  }
+}
+`},
+		{gen.OpenAPI, `components:
+  schemas:
+    Color:
+      type: string
+      description: A Color is a colour.
+      enum:
+        - red
+        - Green
+    Size:
+      type: string
+      enum:
+        - Small
+      x-extensible: true
+`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.format.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := cfg.EmitSchema(&buf, tc.format); err != nil {
+				t.Fatalf("EmitSchema: unexpected error: %v", err)
+			}
+			if got := buf.String(); got != tc.want {
+				t.Errorf("EmitSchema: got\n%s\nwant\n%s", got, tc.want)
+			}
+		})
+	}
+
+	if err := cfg.EmitSchema(&bytes.Buffer{}, gen.SchemaFormat(99)); err == nil {
+		t.Error("EmitSchema with a bogus format did not report an error")
+	}
+}
@@ -0,0 +1,116 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+)
+
+// funcCost is the assumed average size in bytes of the machine code for a
+// generated function or method, used to estimate binary sizes.
+const funcCost = 128
+
+// An EnumSize reports metrics about the code generated for an enumeration.
+type EnumSize struct {
+	Type    string // the enumeration type name
+	Lines   int    // the number of lines of generated source
+	Symbols int    // the number of top-level types, functions, methods, variables, and constants
+	Funcs   int    // the number of functions and methods
+
+	// The number of bytes of text in the string table.
+	StringBytes int
+
+	// The estimated number of bytes of static data for the tables of the
+	// enumeration, including the string table.
+	DataBytes int
+
+	// The estimated contribution of the enumeration to the size of a binary,
+	// comprising its static data and its functions. This is a rough estimate,
+	// suitable for comparing enumerations and configurations, not a measurement.
+	EstimatedSize int
+}
+
+// SizeReport reports metrics about the code generated for each enumeration
+// of c, in order. The metrics reflect the templates and options of c, but not
+// the Format function, if any.
+func (c *Config) SizeReport() ([]EnumSize, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	t, err := c.loadTemplates()
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.fileData()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]EnumSize, len(data.Enums))
+	for i, ed := range data.Enums {
+		var buf bytes.Buffer
+		if err := t.ExecuteTemplate(&buf, "enum", ed); err != nil {
+			return nil, fmt.Errorf("executing templates: %w", err)
+		}
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("go format: %w", err)
+		}
+		es := EnumSize{Type: ed.Type, Lines: bytes.Count(src, []byte("\n"))}
+		f, err := parser.ParseFile(token.NewFileSet(), "", append([]byte("package p\n"), src...), 0)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", ed.Type, err)
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				es.Symbols++
+				es.Funcs++
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if vs, ok := spec.(*ast.ValueSpec); ok {
+						es.Symbols += len(vs.Names)
+					} else {
+						es.Symbols++
+					}
+				}
+			}
+		}
+		es.StringBytes, es.DataBytes = ed.dataSize()
+		es.EstimatedSize = es.DataBytes + es.Funcs*funcCost
+		out[i] = es
+	}
+	return out, nil
+}
+
+// dataSize returns the number of bytes of text in the string table of ed, and
+// the estimated size of all its tables on a 64-bit platform.
+func (ed *EnumData) dataSize() (text, total int) {
+	const sliceHeader, stringHeader, intSize = 24, 16, 8
+	for _, s := range ed.Labels {
+		text += len(s)
+	}
+	n := len(ed.Labels)
+	total = sliceHeader + n*stringHeader + text
+	if ed.HasIndex {
+		total += sliceHeader + n*intSize
+	}
+	if ed.HasCode {
+		// Count the code table and the lookup map, whose entries are assumed to
+		// be about twice the size of the keys and values.
+		total += sliceHeader + n*intSize + 2*n*(intSize+intSize)
+	}
+	for _, a := range ed.Attributes {
+		total += sliceHeader
+		for _, lit := range a.Values {
+			if a.Type == "string" {
+				total += stringHeader + len(lit) - 2 // approximately, less quotes
+			} else {
+				total += intSize
+			}
+		}
+	}
+	return text, total
+}
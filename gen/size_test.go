@@ -0,0 +1,47 @@
+package gen_test
+
+import (
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestSizeReport(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{
+			{Type: "Mood", Values: []*gen.Value{{Name: "Happy"}, {Name: "Sad"}}},
+			{Type: "Size", FromIndex: true, Values: []*gen.Value{{Name: "Small"}, {Name: "Large", Index: ptr(10)}}},
+		},
+	}
+	got, err := cfg.SizeReport()
+	if err != nil {
+		t.Fatalf("SizeReport: unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("SizeReport: got %d results, want 2", len(got))
+	}
+
+	// Mood has a type, 4 methods, the string table, and 2 enumerators.
+	// The strings are "<invalid>", "Happy", and "Sad".
+	want := gen.EnumSize{
+		Type: "Mood", Lines: got[0].Lines, Symbols: 8, Funcs: 4,
+		StringBytes: 17, DataBytes: 24 + 3*16 + 17, EstimatedSize: 89 + 4*128,
+	}
+	if got[0] != want {
+		t.Errorf("Mood: got %+v, want %+v", got[0], want)
+	}
+	if got[0].Lines < 10 {
+		t.Errorf("Mood: got %d lines, want at least 10", got[0].Lines)
+	}
+
+	// Size also has an index table and a FromIndex function.
+	if s := got[1]; s.Type != "Size" || s.Funcs != 5 || s.Symbols != 10 || s.DataBytes != 24+3*16+19+24+3*8 {
+		t.Errorf("Size: got %+v", s)
+	}
+
+	cfg.Enum[0].Values = nil
+	if _, err := cfg.SizeReport(); err == nil {
+		t.Error("SizeReport with an invalid config did not report an error")
+	}
+}
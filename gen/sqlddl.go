@@ -0,0 +1,75 @@
+package gen
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// GenerateSQLDDL writes SQL data definitions for the enumerations of c to w,
+// so that database schema migrations can be kept in sync with the generated
+// types. The permitted values of each enumeration are the strings of its
+// valid enumerators. The dialect must be one of "postgres", "mysql", or
+// "sqlite".
+//
+// For Postgres, each enumeration is defined as a named enumerated type:
+//
+//	CREATE TYPE color AS ENUM ('red', 'green');
+//
+// MySQL and SQLite do not have named enumerated types, so for these dialects
+// each enumeration is defined as a named CHECK constraint on a column of the
+// same name, suitable for inclusion in a CREATE TABLE statement:
+//
+//	CONSTRAINT color_check CHECK (color IN ('red', 'green'))
+//
+// The SQL name of an enumeration is its db-type, if set, or otherwise its type
+// name converted to snake case.
+func (c *Config) GenerateSQLDDL(w io.Writer, dialect string) error {
+	var format func(name, values string) string
+	switch dialect {
+	case "postgres":
+		format = func(name, values string) string {
+			return fmt.Sprintf("CREATE TYPE %s AS ENUM (%s);", name, values)
+		}
+	case "mysql", "sqlite":
+		format = func(name, values string) string {
+			return fmt.Sprintf("CONSTRAINT %[1]s_check CHECK (%[1]s IN (%[2]s))", name, values)
+		}
+	default:
+		return fmt.Errorf("unknown SQL dialect %q", dialect)
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "-- Code generated by enumgen. DO NOT EDIT.\n")
+	for _, e := range c.Enum {
+		name := e.DBType
+		if name == "" {
+			name = snakeCase(e.Type)
+		}
+		fmt.Fprintf(&sb, "\n-- %s\n%s\n", e.Type, format(name, sqlValues(e.enumData().Enumerators)))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// snakeCase converts a Go identifier in mixed case to lower snake case, for
+// example "HTTPStatus" becomes "http_status".
+func snakeCase(s string) string {
+	rs := []rune(s)
+	var sb strings.Builder
+	for i, r := range rs {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := rs[i-1]
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
@@ -0,0 +1,61 @@
+package gen_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestGenerateSQLDDL(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{{
+			Type:   "HTTPStatus",
+			Zero:   "Unknown",
+			Values: []*gen.Value{{Name: "OK", Text: "ok"}, {Name: "Unknown"}, {Name: "Gone", Text: "it's gone"}},
+		}, {
+			Type:   "Mood",
+			GORM:   true,
+			DBType: "mood_type",
+			Values: []*gen.Value{{Name: "Happy"}},
+		}},
+	}
+
+	tests := []struct {
+		dialect string
+		want    string
+	}{
+		{"postgres", `-- Code generated by enumgen. DO NOT EDIT.
+
+-- HTTPStatus
+CREATE TYPE http_status AS ENUM ('ok', 'it''s gone');
+
+-- Mood
+CREATE TYPE mood_type AS ENUM ('Happy');
+`},
+		{"sqlite", `-- Code generated by enumgen. DO NOT EDIT.
+
+-- HTTPStatus
+CONSTRAINT http_status_check CHECK (http_status IN ('ok', 'it''s gone'))
+
+-- Mood
+CONSTRAINT mood_type_check CHECK (mood_type IN ('Happy'))
+`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.dialect, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := cfg.GenerateSQLDDL(&buf, tc.dialect); err != nil {
+				t.Fatalf("GenerateSQLDDL: unexpected error: %v", err)
+			}
+			if got := buf.String(); got != tc.want {
+				t.Errorf("GenerateSQLDDL: got\n%s\nwant\n%s", got, tc.want)
+			}
+		})
+	}
+
+	if err := cfg.GenerateSQLDDL(&bytes.Buffer{}, "oracle"); err == nil {
+		t.Error("GenerateSQLDDL with an unknown dialect did not report an error")
+	}
+}
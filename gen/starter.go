@@ -0,0 +1,97 @@
+package gen
+
+import (
+	_ "embed"
+	"fmt"
+	"go/token"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// starterEnum is the YAML text of the enumeration defined by a starter config,
+// without its type name, listing the options of an enumeration in comments.
+//
+//go:embed starter.yml
+var starterEnum string
+
+// starterConfig is the YAML text of the top-level settings of a starter config,
+// listing the options of a config in comments.
+const starterConfig = `package: %s
+
+# Remove the leading "#" to enable any of these options.
+#
+# registry: true         # generate an Enums map of all the enumerations
+# emit-interface: true   # generate the interface satisfied by all the enumerations
+# interface-name: "Enum" # name of the generated interface (default "EnumType")
+# map-threshold: 32      # decode strings with maps for enumerations this large
+# build-tags: "linux"    # build constraint for the generated files
+# header: "text"         # comment text for the top of the generated files
+# package-doc: "text"    # package doc comment listing the enumerations
+# include:               # other files whose enumerations are included
+#   - "common/enums.yml"
+# bases:                 # named lists of common values for the base option
+#   Common:
+#     - name: Unknown
+
+enum:
+`
+
+// A Starter describes a starter config for a new package, with an example
+// enumeration and comments listing the available options.
+type Starter struct {
+	// The name of the package to generate (required).
+	Package string
+
+	// The path of the config file. If it ends in ".go", the config is a Go
+	// source file with an enumgen:type comment, otherwise it is YAML.
+	Path string
+
+	// The path of the file to generate from the config.
+	Output string
+}
+
+// starterType is the type name of the example enumeration of a Starter.
+const starterType = "Color"
+
+// IsGo reports whether s is a Go source file.
+func (s Starter) IsGo() bool { return strings.HasSuffix(s.Path, ".go") }
+
+// Directives returns the go:generate directives to generate s.Output from the
+// config. They must be added to a Go file in the directory of the config.
+func (s Starter) Directives() string {
+	return "//go:generate -command enumgen go run github.com/creachadair/enumgen@latest\n" +
+		fmt.Sprintf("//go:generate enumgen -config %s -output %s\n",
+			filepath.Base(s.Path), filepath.Base(s.Output))
+}
+
+// Write writes the text of the starter config to w.
+func (s Starter) Write(w io.Writer) error {
+	if !token.IsIdentifier(s.Package) {
+		return fmt.Errorf("invalid package name %q", s.Package)
+	} else if s.Path == "" || s.Output == "" {
+		return fmt.Errorf("starter config and output paths must be set")
+	}
+	var buf strings.Builder
+	if s.IsGo() {
+		fmt.Fprintf(&buf, "package %s\n\n%s\n", s.Package, s.Directives())
+		fmt.Fprintf(&buf, "/*enumgen:type %s\n\n%s*/\n", starterType, starterEnum)
+	} else {
+		fmt.Fprintf(&buf, "# To generate %s from this file, add these directives\n", filepath.Base(s.Output))
+		fmt.Fprintf(&buf, "# to a Go file in package %s:\n#\n", s.Package)
+		for _, line := range strings.SplitAfter(strings.TrimSuffix(s.Directives(), "\n"), "\n") {
+			buf.WriteString("#   " + line)
+		}
+		buf.WriteString("\n\n")
+		fmt.Fprintf(&buf, starterConfig, s.Package)
+		fmt.Fprintf(&buf, "  - type: %s\n", starterType)
+		for _, line := range strings.SplitAfter(starterEnum, "\n") {
+			if strings.TrimSpace(line) != "" {
+				buf.WriteString("    ")
+			}
+			buf.WriteString(line)
+		}
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
@@ -0,0 +1,59 @@
+package gen_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestStarter(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"enums.yml", "enums.go"} {
+		t.Run(name, func(t *testing.T) {
+			s := gen.Starter{
+				Package: "foo",
+				Path:    filepath.Join(dir, name),
+				Output:  filepath.Join(dir, "generated.go"),
+			}
+			var buf bytes.Buffer
+			if err := s.Write(&buf); err != nil {
+				t.Fatalf("Write: unexpected error: %v", err)
+			}
+			if !strings.Contains(buf.String(), "enumgen -config "+name+" -output generated.go") {
+				t.Errorf("Write: missing go:generate directive:\n%s", buf.String())
+			}
+			if err := os.WriteFile(s.Path, buf.Bytes(), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			// The starter config should be valid, and should generate code.
+			var cfg *gen.Config
+			var err error
+			if s.IsGo() {
+				cfg, err = gen.ConfigFromGoFile(s.Path)
+			} else {
+				cfg, err = gen.ConfigFromYAML(s.Path)
+			}
+			if err != nil {
+				t.Fatalf("Loading config: %v", err)
+			}
+			if cfg.Package != "foo" || len(cfg.Enum) != 1 || cfg.Enum[0].Type != "Color" {
+				t.Errorf("Config: got package %q with %d enums, want foo with Color", cfg.Package, len(cfg.Enum))
+			}
+			if err := cfg.Generate(new(bytes.Buffer)); err != nil {
+				t.Errorf("Generate: unexpected error: %v", err)
+			}
+		})
+	}
+
+	t.Run("BadPackage", func(t *testing.T) {
+		s := gen.Starter{Package: "not valid", Path: "enums.yml", Output: "generated.go"}
+		if err := s.Write(new(bytes.Buffer)); err == nil {
+			t.Error("Write: got nil, want error")
+		}
+	})
+}
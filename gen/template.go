@@ -0,0 +1,651 @@
+package gen
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// FileData is the data model for the "file" template, which generates the
+// complete output file for a Config.
+type FileData struct {
+	Config  *Config     // the configuration being generated
+	Imports []string    // import specs for the generated code, as Go source
+	Enums   []*EnumData // the enumerations to generate, in order
+
+	// The provenance directive for the header, recording the content hash.
+	Provenance string
+
+	// The build constraint for the header, or "" if none. This is the
+	// build-tags setting of the config, except for GenerateJSONv2.
+	BuildTags string
+
+	// The formatted package doc comment, or "" if none (see PackageDoc).
+	PackageDoc string
+
+	// Whether to define the InvalidEnumError type, which is used by any
+	// enumeration that sets ParseError to "struct".
+	InvalidError bool
+
+	// The name of the interface satisfied by the enumerations, or "" if it is
+	// not generated (see EmitInterface), and the names of the enumeration
+	// types that satisfy it, which omit none of its methods.
+	Interface      string
+	InterfaceTypes []string
+}
+
+// EnumData is the data model for the "enum" template and the templates it
+// invokes. The fields of the enumeration config are promoted.
+type EnumData struct {
+	*Enum
+
+	Type       string // the name of the generated type
+	Recv       string // the name of the method receiver
+	Comment    string // the formatted doc comment for the type, or ""
+	ValComment string // the formatted doc comment for the values, or ""
+	Field      string // the name of the unexported struct field
+	Base       string // the integer type of the ordinals (see FixedWidth)
+	Strs       string // an expression denoting the string table
+	Idxs       string // an expression denoting the index table (if HasIndex)
+	Vals       string // an expression denoting the table of valid enumerators (if Runtime)
+	Text       string // an expression denoting the byte string table (if TextMarshal)
+	Group      string // the name of the table variable (if Grouped)
+	CtxKey     string // the name of the context key type (if Context)
+	Grouped    bool   // whether the tables are grouped into one variable
+	HasIndex   bool   // whether any enumerator has an explicit index
+
+	HasGRPC  bool   // whether the enumerators have gRPC status codes
+	HasCode  bool   // whether the enumerators have integer codes
+	CodeType string // the integer type of the codes (if HasCode)
+	Codes    string // an expression denoting the code table (if HasCode)
+	ByCode   string // an expression denoting the code lookup map (if HasCode)
+
+	HasDisplay bool   // whether the enumerators have display names
+	Displays   string // an expression denoting the display name table (if HasDisplay)
+
+	// Whether strings are decoded with lookup maps (see MapThreshold), and
+	// expressions denoting the maps for exact and case-folded lookups. The
+	// maps take each string to its position in the string table, less one.
+	MapLookup       bool
+	ByStr, ByFold   string
+	StrMap, FoldMap map[string]int
+
+	// Whether the string table is packed into a single string, and the
+	// name of the table of offsets into it, the element type of the table,
+	// and its contents (if Packed).
+	Packed     bool
+	StrOffs    string
+	OffsetType string
+	PackedStrs string
+	Offsets    []int
+
+	Pointer   bool   // whether the struct field is a pointer to an interned entry
+	EntryType string // the name of the entry type (if Pointer)
+	Entries   string // the name of the entry table, never grouped (if Pointer)
+	Unknowns  string // the name of the table of unknown strings (if UnmarshalUnknown is "keep-text")
+
+	// The name of the case-insensitive parsing function, or "" if none is
+	// to be generated.
+	ParseFunc string
+
+	// The name of the function that finds the enumerator for a string, used
+	// by the decoding methods, or "" if none is to be generated.
+	Find string
+
+	// The JSON Schema definition of the enumeration, if JSONSchema is set.
+	SchemaFragment string
+
+	// The fingerprint of the enumerators, if the Fingerprint option is set.
+	Digest string
+
+	// The attributes of the enumerators, in order by name.
+	Attributes []*AttrData
+
+	Labels  []string // the string labels of all enumerators, by ordinal
+	Indices []int    // the indices of all enumerators, by ordinal
+
+	ZeroValue   *ValueData   // the zero enumerator (ordinal 0)
+	Enumerators []*ValueData // the non-zero enumerators, in order
+}
+
+// ValueData is the data model for a single enumerator.
+type ValueData struct {
+	Enum  *EnumData // the enumeration this enumerator belongs to
+	Value *Value    // the enumerator config; nil for an implicit zero
+
+	Name    string // the variable name of the enumerator, or "" if none
+	Comment string // the formatted doc comment for the enumerator, or ""
+	Doc     string // the doc text of the enumerator, without comment markers, or ""
+	Label   string // the string representation of the enumerator
+	Ordinal int    // the position of the enumerator in the string table
+	Index   int    // the value returned by the Index method
+	Code    int    // the value returned by the Code method (if HasCode)
+
+	// The name of the gRPC status code of the enumerator, and the message for
+	// its status error, or "" if none. These are only set if HasGRPC.
+	GRPCCode, GRPCMessage string
+
+	// The corresponding protobuf enum constant, or "" if none.
+	// This is only set if the enumeration has a Proto setting.
+	Proto string
+
+	// A Go literal for the display names of the enumerator, as an element of
+	// the display name table. This is only set if HasDisplay.
+	Display string
+}
+
+// FlagChoices returns the suffix of the usage text of a flag of the type,
+// listing the strings of the valid enumerators.
+func (ed *EnumData) FlagChoices() string {
+	strs := make([]string, len(ed.Enumerators))
+	for i, v := range ed.Enumerators {
+		strs[i] = v.Label
+	}
+	return " (one of: " + strings.Join(strs, ", ") + ")"
+}
+
+// ExampleData is the data model for the documentation examples of an
+// enumeration (see Enum.Examples).
+type ExampleData struct {
+	// The enumerators printed by the examples, which omit deprecated
+	// enumerators and those whose strings could not be compared as the
+	// output of an example, such as strings with line breaks.
+	Values []*ValueData
+
+	Input   string // a string to parse, or "" if Values is empty
+	Parsed  string // the string of the enumerator that Input parses as
+	Unknown string // a string that is not a case-insensitive match for any enumerator
+}
+
+// Example returns the data for the documentation examples of ed.
+func (ed *EnumData) Example() *ExampleData {
+	xd := &ExampleData{Unknown: "unknown"}
+	for _, v := range ed.Enumerators {
+		s := v.Label
+		if v.Value.Deprecated == "" && s != "" && s == strings.TrimSpace(s) && !strings.ContainsAny(s, "\r\n") {
+			xd.Values = append(xd.Values, v)
+		}
+	}
+	match := func(s string) int {
+		return slices.IndexFunc(ed.Enumerators, func(v *ValueData) bool { return strings.EqualFold(v.Label, s) })
+	}
+	if len(xd.Values) != 0 {
+		xd.Input = strings.ToUpper(xd.Values[0].Label)
+		xd.Parsed = ed.Enumerators[match(xd.Input)].Label
+	}
+	for match(xd.Unknown) >= 0 {
+		xd.Unknown += "?"
+	}
+	return xd
+}
+
+// DocData is the data model for the doc comment of a generated method.
+type DocData struct {
+	*EnumData
+
+	Method string // the name of the method
+	Name   string // the attribute, group, or enumerator of the method, or ""
+}
+
+// DocFor returns the data for the doc comment of the specified method, and of
+// the attribute, group, or enumerator it belongs to if name is given.
+func (ed *EnumData) DocFor(method string, name ...string) *DocData {
+	return &DocData{EnumData: ed, Method: method, Name: strings.Join(name, "")}
+}
+
+// Multiline reports whether the doc comment for v spans multiple lines, or
+// is a deprecation notice, and so must be placed before the enumerator.
+func (v *ValueData) Multiline() bool {
+	return strings.Contains(v.Comment, "\n") || (v.Value != nil && v.Value.Deprecated != "")
+}
+
+// mapLookup reports whether the code generated for e decodes strings with
+// lookup maps, according to the MapThreshold of c.
+func (c *Config) mapLookup(e *Enum) bool {
+	_, rest := e.extractZero()
+	n := c.MapThreshold
+	return n > 0 && len(rest) >= n && !e.Runtime && (e.findsText() || e.parseFunc() != "")
+}
+
+// setLookupMaps enables map lookups for ed, and populates the maps used by
+// its generated code: The exact map is used to decode text, XML, JSON, SQL
+// values, CBOR strings, and lists, and the case-folded map by the parse function.
+// When two enumerators have the same key, the map selects the first of them,
+// as a scan of the string table would.
+func (ed *EnumData) setLookupMaps() {
+	if ed.findsText() {
+		ed.StrMap = make(map[string]int)
+	}
+	if ed.ParseFunc != "" {
+		ed.FoldMap = make(map[string]int)
+	}
+	if ed.StrMap == nil && ed.FoldMap == nil {
+		return // nothing to look up
+	}
+	ed.MapLookup = true
+	ed.ByStr, ed.ByFold = ed.tableName("bystr"), ed.tableName("byfold")
+	for i, s := range ed.Labels[1:] {
+		if _, ok := ed.StrMap[s]; !ok && ed.StrMap != nil {
+			ed.StrMap[s] = i
+		}
+		if _, ok := ed.FoldMap[strings.ToLower(s)]; !ok && ed.FoldMap != nil {
+			ed.FoldMap[strings.ToLower(s)] = i
+		}
+	}
+}
+
+// interfaceName returns the name of the generated interface for c.
+func (c *Config) interfaceName() string {
+	if c.InterfaceName == "" {
+		return "EnumType"
+	}
+	return c.InterfaceName
+}
+
+// fileData constructs the template data model for c.
+func (c *Config) fileData() (*FileData, error) {
+	var imp importTracker
+	for _, e := range c.Enum {
+		e.addImports(&imp)
+	}
+	specs, err := imp.specs()
+	if err != nil {
+		return nil, err
+	}
+	prov, err := c.provenance()
+	if err != nil {
+		return nil, err
+	}
+	fd := &FileData{Config: c, Imports: specs, Provenance: prov, BuildTags: c.BuildTags}
+	for _, e := range c.Enum {
+		ed := e.enumData()
+		if c.mapLookup(e) {
+			ed.setLookupMaps()
+		}
+		fd.Enums = append(fd.Enums, ed)
+		fd.InvalidError = fd.InvalidError || e.ParseError == "struct"
+	}
+	if c.EmitInterface {
+		fd.Interface = c.interfaceName()
+		for _, e := range c.Enum {
+			if len(e.Methods) == 0 {
+				fd.InterfaceTypes = append(fd.InterfaceTypes, e.goType())
+			}
+		}
+	}
+	if c.PackageDoc != "" {
+		fd.PackageDoc = formatDoc(fd.packageDoc())
+	}
+	return fd, nil
+}
+
+// packageDoc returns the text of the package doc comment for fd, comprising
+// the PackageDoc text of its config and a table of the enumerators of each of
+// its enumerations.
+func (fd *FileData) packageDoc() string {
+	var buf strings.Builder
+	buf.WriteString(strings.TrimSpace(fd.Config.PackageDoc))
+	buf.WriteString("\n\n# Enumerations\n")
+	for _, ed := range fd.Enums {
+		fmt.Fprintf(&buf, "\nThe enumerators of type [%s] are:\n\n", ed.Type)
+		var tab strings.Builder
+		tw := tabwriter.NewWriter(&tab, 0, 8, 2, ' ', 0)
+		fmt.Fprint(tw, "Name\tString\tIndex")
+		if ed.HasCode {
+			fmt.Fprint(tw, "\tCode")
+		}
+		fmt.Fprintln(tw)
+		for _, v := range ed.Enumerators {
+			fmt.Fprintf(tw, "%s\t%q\t%d", v.Name, v.Label, v.Index)
+			if ed.HasCode {
+				fmt.Fprintf(tw, "\t%d", v.Code)
+			}
+			fmt.Fprintln(tw)
+		}
+		tw.Flush()
+		for _, line := range strings.SplitAfter(tab.String(), "\n") {
+			if line != "" {
+				buf.WriteString("\t" + line)
+			}
+		}
+	}
+	return buf.String()
+}
+
+// enumData constructs the template data model for e.
+func (e *Enum) enumData() *EnumData {
+	zero, rest := e.extractZero()
+
+	ed := &EnumData{
+		Enum:       e,
+		Type:       e.goType(),
+		Recv:       cmp.Or(e.Receiver, "v"),
+		Comment:    formatDoc(injectName(e.Doc, e.goType())),
+		ValComment: formatDoc(e.ValDoc),
+		Field:      "_" + e.Type,
+		Base:       cmp.Or(e.FixedWidth, baseType(len(e.Values))),
+		Group:      groupName(e.Type),
+		Grouped:    e.Naming == "grouped",
+	}
+	ed.Strs, ed.Idxs, ed.Vals = e.tableName("str"), e.tableName("idx"), e.tableName("vals")
+	if e.Context {
+		ed.CtxKey = e.typeName("ctxkey")
+	}
+	if e.TextMarshal {
+		ed.Text = e.tableName("text")
+	}
+	if e.findsText() {
+		ed.Find = e.ident("find")
+	}
+	if e.Representation == "pointer" {
+		ed.Pointer = true
+		ed.EntryType, ed.Entries = e.typeName("entry"), e.typeName("ents")
+		if e.UnmarshalUnknown == "keep-text" {
+			ed.Unknowns = e.typeName("unknown")
+		}
+	}
+	if e.hasCodes() {
+		ed.HasCode = true
+		ed.CodeType = cmp.Or(e.CodeType, "int")
+		ed.Codes, ed.ByCode = e.tableName("code"), e.tableName("bycode")
+	}
+	ed.HasGRPC = e.hasGRPC()
+	if e.hasDisplay() {
+		ed.HasDisplay = true
+		ed.Displays = e.tableName("display")
+	}
+	ed.ParseFunc = e.parseFunc()
+	if e.JSONSchema {
+		frag, _ := json.Marshal(e.schemaDef()) // cannot fail
+		ed.SchemaFragment = string(frag)
+	}
+
+	// Set up the zero enumerator, which has no name unless one is configured.
+	ed.ZeroValue = &ValueData{Enum: ed, Value: zero, Label: e.zeroLabel(zero)}
+	if zero != nil {
+		ed.ZeroValue.Doc = strings.TrimSpace(injectName(zero.Doc, e.valueName(zero.Name)))
+		ed.ZeroValue.Comment = formatDoc(zero.docText(e.valueName(zero.Name)))
+		if ed.HasGRPC {
+			ed.ZeroValue.setGRPC(zero, e.valueName(zero.Name))
+		}
+	}
+	if ed.HasDisplay {
+		ed.ZeroValue.Display = displayLiteral(zero)
+	}
+	if e.Zero != "" {
+		ed.ZeroValue.Name = e.valueName(e.Zero)
+		if p, ok := e.Proto.constant(e.Zero, false); ok {
+			ed.ZeroValue.Proto = p
+		}
+	}
+	ed.Labels = append(ed.Labels, ed.ZeroValue.Label)
+	ed.ZeroValue.Index = e.indexBase() - 1
+	ed.Indices = append(ed.Indices, ed.ZeroValue.Index)
+
+	// Extract the label strings and indices for the defined enumerators. If
+	// the indices are not based at 1, they do not match the ordinals, so the
+	// index table is required.
+	var idx []int
+	rest, idx, ed.HasIndex = e.sortValues(rest)
+	ed.HasIndex = ed.HasIndex || e.indexBase() != 1
+	for i, v := range rest {
+		fullName := e.valueName(v.Name)
+		vd := &ValueData{
+			Enum:    ed,
+			Value:   v,
+			Name:    fullName,
+			Comment: formatDoc(v.docText(fullName)),
+			Doc:     strings.TrimSpace(injectName(v.Doc, fullName)),
+			Label:   v.label(),
+			Ordinal: i + 1,
+			Index:   idx[i],
+		}
+		if v.Code != nil {
+			vd.Code = *v.Code
+		}
+		if ed.HasGRPC {
+			vd.setGRPC(v, fullName)
+		}
+		if ed.HasDisplay {
+			vd.Display = displayLiteral(v)
+		}
+		vd.Proto, _ = e.Proto.constant(v.Name, true)
+		ed.Enumerators = append(ed.Enumerators, vd)
+		ed.Labels = append(ed.Labels, vd.Label)
+		ed.Indices = append(ed.Indices, vd.Index)
+	}
+	if e.StringTable == "packed" {
+		ed.setPacked()
+	}
+	if e.Fingerprint {
+		ed.Digest = ed.fingerprint()
+	}
+	ed.Attributes = e.attrData(zero, rest)
+	return ed
+}
+
+// setPacked sets up the packed string table of ed from its labels.
+func (ed *EnumData) setPacked() {
+	ed.Packed = true
+	ed.StrOffs = ed.tableName("stroff")
+	ed.PackedStrs = strings.Join(ed.Labels, "")
+	ed.OffsetType = "uint16"
+	if len(ed.PackedStrs) > math.MaxUint16 {
+		ed.OffsetType = "uint32"
+	}
+	var pos int
+	for _, s := range ed.Labels {
+		ed.Offsets = append(ed.Offsets, pos)
+		pos += len(s)
+	}
+	ed.Offsets = append(ed.Offsets, pos)
+}
+
+// fingerprint returns a digest of the names, labels, and indices of the
+// enumerators of ed, in order, as "sha256:" followed by the hex digest.
+func (ed *EnumData) fingerprint() string {
+	h := sha256.New()
+	for _, v := range append([]*ValueData{ed.ZeroValue}, ed.Enumerators...) {
+		fmt.Fprintf(h, "%s\t%q\t%d\n", v.Name, v.Label, v.Index)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// hasCodes reports whether any enumerator of e has an explicit code.
+func (e *Enum) hasCodes() bool {
+	return slices.ContainsFunc(e.Values, func(v *Value) bool { return v.Code != nil })
+}
+
+// hasGRPC reports whether any enumerator of e has a gRPC status code.
+func (e *Enum) hasGRPC() bool {
+	return slices.ContainsFunc(e.Values, func(v *Value) bool { return v.GRPCCode != "" })
+}
+
+// setGRPC populates the gRPC settings of vd from v, whose generated name is
+// fullName. The message is the doc text of v joined into a single line.
+func (vd *ValueData) setGRPC(v *Value, fullName string) {
+	vd.GRPCCode = v.GRPCCode
+	vd.GRPCMessage = strings.Join(strings.Fields(injectName(v.Doc, fullName)), " ")
+}
+
+// hasDisplay reports whether any enumerator of e has display names.
+func (e *Enum) hasDisplay() bool {
+	return slices.ContainsFunc(e.Values, func(v *Value) bool { return len(v.Display) != 0 })
+}
+
+// displayLiteral returns a Go literal for the display names of v, as an
+// element of a []map[string]string, or "nil" if v has none.
+func displayLiteral(v *Value) string {
+	if v == nil || len(v.Display) == 0 {
+		return "nil"
+	}
+	var sb strings.Builder
+	sb.WriteString("{")
+	for i, lang := range slices.Sorted(maps.Keys(v.Display)) {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%q: %q", lang, v.Display[lang])
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// parseFunc returns the name of the case-insensitive parsing function for e,
+// or "" if none is required.
+func (e *Enum) parseFunc() string {
+	if e.Constructor || e.Constructors.New {
+		return e.ident("New")
+	} else if e.Providers != nil {
+		return e.ident("new")
+	} else if !e.Runtime && (e.FlagValue || e.Constructors.Parse || e.Constructors.Must) {
+		return e.ident("new")
+	}
+	return ""
+}
+
+// findsText reports whether the code generated for e decodes strings by
+// exact match, other than through the runtime package, and so requires a
+// find function.
+func (e *Enum) findsText() bool {
+	return (!e.Runtime && (e.ParseList || e.TextMarshal)) || e.XML || e.JSONv2 || e.SQL || e.GORM || (e.CBOR && !e.CBORCode)
+}
+
+// indexBase returns the index of the first non-zero enumerator of e, unless
+// its index is set explicitly. The index of the zero enumerator is one less.
+func (e *Enum) indexBase() int {
+	if e.IndexBase != nil {
+		return *e.IndexBase
+	}
+	return 1
+}
+
+// indices returns the effective indices of the non-zero enumerators in rest,
+// and reports whether any of them has an explicit index.
+func (e *Enum) indices(rest []*Value) ([]int, bool) {
+	out := make([]int, len(rest))
+	curIndex, setIndex := e.indexBase(), false
+	for i, v := range rest {
+		if v.Index != nil {
+			curIndex = *v.Index
+			setIndex = true
+		}
+		out[i] = curIndex
+		curIndex++
+	}
+	return out, setIndex
+}
+
+// sortValues returns the non-zero enumerators rest of e, as returned by
+// extractZero, ordered by the sort setting of e, and the index of each, as
+// assigned by indices in the original order. It also reports whether the
+// indices must be recorded in an index table, because some index is set
+// explicitly or the sorted indices do not ascend from 1.
+func (e *Enum) sortValues(rest []*Value) ([]*Value, []int, bool) {
+	idx, hasIndex := e.indices(rest)
+	var key func(i int) string
+	switch e.Sort {
+	case "", "none":
+		return rest, idx, hasIndex
+	case "name":
+		key = func(i int) string { return rest[i].Name }
+	case "text":
+		key = func(i int) string { return rest[i].label() }
+	}
+	perm := make([]int, len(rest))
+	for i := range perm {
+		perm[i] = i
+	}
+	slices.SortStableFunc(perm, func(a, b int) int {
+		if key == nil { // sort by index
+			return cmp.Compare(idx[a], idx[b])
+		}
+		return cmp.Compare(key(a), key(b))
+	})
+	sorted := make([]*Value, len(rest))
+	sortedIdx := make([]int, len(rest))
+	for i, j := range perm {
+		sorted[i], sortedIdx[i] = rest[j], idx[j]
+		hasIndex = hasIndex || sortedIdx[i] != i+1
+	}
+	return sorted, sortedIdx, hasIndex
+}
+
+// sqlValues renders the labels of vs as a comma-separated list of SQL string
+// literals.
+func sqlValues(vs []*ValueData) string {
+	quoted := make([]string, len(vs))
+	for i, v := range vs {
+		quoted[i] = "'" + strings.ReplaceAll(v.Label, "'", "''") + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// loadTemplates constructs the code templates for c, consisting of the
+// built-in templates updated by any templates defined by the config.
+func (c *Config) loadTemplates() (*template.Template, error) {
+	t := template.New("").Funcs(template.FuncMap{
+		"quote":     strconv.Quote,
+		"doc":       formatDoc,
+		"sqlvalues": sqlValues,
+	})
+	ents, err := fs.ReadDir(templateFS, "templates")
+	if err != nil {
+		return nil, err
+	}
+	for _, ent := range ents {
+		text, err := fs.ReadFile(templateFS, "templates/"+ent.Name())
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(ent.Name(), ".tmpl")
+		if _, err := t.New(name).Parse(string(text)); err != nil {
+			return nil, fmt.Errorf("built-in template %q: %w", name, err)
+		}
+	}
+
+	// Apply user-provided templates in order by name, so that the results are
+	// deterministic if a template also contains nested definitions.
+	for _, name := range slices.Sorted(maps.Keys(c.Templates)) {
+		if _, err := t.New(name).Parse(c.Templates[name]); err != nil {
+			return nil, fmt.Errorf("template %q: %w", name, err)
+		}
+	}
+	return t, nil
+}
+
+// ReadTemplates reads the template files (*.tmpl) from the specified
+// directory, and returns a map from template name to template text suitable
+// for use in the Templates field of a Config. The name of each template is the
+// base name of the file without the extension.
+func ReadTemplates(dir string) (map[string]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string)
+	for _, path := range paths {
+		text, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		out[strings.TrimSuffix(filepath.Base(path), ".tmpl")] = string(text)
+	}
+	return out, nil
+}
@@ -1,32 +1,238 @@
 // Code generated by enumgen. DO NOT EDIT.
+//enumgen:provenance {"version":"(devel)","config":"gentest.yml","hash":"sha256:0175b31f8903ae5a93b04771e9a2eb0de8088898a1cb96ab36a97eba52906a65"}
+// Test enumerations for the gen package.
+//
+// See gentest.yml for the configuration.
+
+//go:build go1.23
 
 package testdata
 
 import (
+	"context"
+	"database/sql/driver"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/creachadair/enumgen/enum"
 )
 
-type E1 struct{ _E1 uint8 }
+type E1 struct{ _E1 *_entry_E1 }
 
 // Enum returns the name of the enumeration type for E1.
 func (E1) Enum() string { return "E1" }
 
 // String returns the string representation of E1 v.
-func (v E1) String() string { return _str_E1[v._E1] }
+func (v E1) String() string {
+	if v._E1 == nil {
+		return _str_E1[0]
+	}
+	return v._E1.str
+}
 
 // Valid reports whether v is a valid non-zero E1 value.
-func (v E1) Valid() bool { return v._E1 > 0 && int(v._E1) < len(_str_E1) }
+func (v E1) Valid() bool { return v._E1 != nil && v._E1.ord != 0 }
 
 // Index returns the integer index of E1 v.
-func (v E1) Index() int { return int(v._E1) }
+func (v E1) Index() int { return int(v._E1.ordinal()) }
+
+// E1Choice is one of the choices listed by E1Choices, for use in
+// templates that render a form control, such as an HTML <select> element.
+type E1Choice struct {
+	Value    E1     // the enumerator
+	Text     string // the string of the enumerator
+	Selected bool   // whether the enumerator is one of those selected
+}
+
+// E1Choices returns a choice for each valid enumerator of E1, in
+// order, marking those equal to any of selected. To use it in a template,
+// add it to the template's FuncMap:
+//
+//	t.Funcs(template.FuncMap{"E1Choices": E1Choices})
+func E1Choices(selected ...E1) []E1Choice {
+	out := make([]E1Choice, 0, 3)
+	for i := 1; i < len(_str_E1); i++ {
+		c := E1Choice{Value: E1{&_ents_E1[i]}, Text: _str_E1[i]}
+		c.Selected = slices.Contains(selected, c.Value)
+		out = append(out, c)
+	}
+	return out
+}
+
+// IsA reports whether v is A.
+func (v E1) IsA() bool { return v == A }
+
+// IsB reports whether v is B.
+func (v E1) IsB() bool { return v == B }
+
+// IsC reports whether v is C.
+func (v E1) IsC() bool { return v == C }
+
+// ErrInvalidE1 is the error wrapped by the errors reported when a
+// string does not match any E1 enumerator.
+var ErrInvalidE1 = errors.New("invalid value for E1")
+
+// invalidE1 returns the error reported when s does not match any
+// E1 enumerator.
+func invalidE1(s string) error {
+	return fmt.Errorf("%w: %q", ErrInvalidE1, s)
+}
+
+// findE1 returns the position of the enumerator of E1 whose string
+// is text, less one, and reports whether there is one.
+func findE1(text string) (int, bool) {
+	i, ok := _bystr_E1[text]
+	return i, ok
+}
+
+// ParseE1List parses s as a list of E1 enumerators separated by
+// sep, or by "," if sep == "". Each element is trimmed of surrounding
+// whitespace, and empty elements are skipped. Each remaining element must
+// exactly match the string representation of a non-zero enumerator.
+// Repeated enumerators are included in the result each time they occur.
+func ParseE1List(s, sep string) ([]E1, error) {
+	if sep == "" {
+		sep = ","
+	}
+	var out []E1
+next:
+	for _, elt := range strings.Split(s, sep) {
+		elt = strings.TrimSpace(elt)
+		if elt == "" {
+			continue
+		}
+		if i, ok := findE1(elt); ok {
+			out = append(out, E1{&_ents_E1[i+1]})
+			continue next
+		}
+		return nil, invalidE1(elt)
+	}
+	return out, nil
+}
+
+// NumE1 is the number of valid E1 enumerators.
+// An array of this length can be indexed by the AsArrayIndex method.
+const NumE1 = 3
+
+// AsArrayIndex returns a dense 0-based index for v, in the range
+// 0 ≤ i < NumE1, in the order the enumerators are defined.
+// It panics if v is not a valid enumerator.
+func (v E1) AsArrayIndex() int {
+	if !v.Valid() {
+		panic("E1: AsArrayIndex of invalid enumerator")
+	}
+	return int(v._E1.ordinal()) - 1
+}
+
+// AppendText appends the text encoding of the E1 enumerator to b.
+// It does not allocate if b has enough capacity.
+// It satisfies the encoding.TextAppender interface.
+func (v E1) AppendText(b []byte) ([]byte, error) { return append(b, v.String()...), nil }
+
+// MarshalText encodes the value of the E1 enumerator as text.
+// Except for an unknown string kept by UnmarshalText, it does not allocate.
+// The result is a slice of a shared table, which the caller must not modify.
+// It satisfies the encoding.TextMarshaler interface.
+func (v E1) MarshalText() ([]byte, error) {
+	if v._E1 != nil && v._E1.ord == 0 {
+		return []byte(v._E1.str), nil
+	}
+	b := _text_E1[v._E1.ordinal()]
+	return b[:len(b):len(b)], nil
+}
 
+// UnarshalText decodes the value of the E1 enumerator from a string.
+// A string that does not encode a known enumerator decodes to a value that
+// is not valid, but retains the string.
+// An empty slice decodes to the zero value.
+// This method satisfies the encoding.TextUnmarshaler interface.
+func (v *E1) UnmarshalText(data []byte) error {
+	*v = E1{}
+	text := string(data)
+	if text == "" || text == _str_E1[0] {
+		return nil
+	}
+	if i, ok := findE1(text); ok {
+		*v = E1{&_ents_E1[i+1]}
+		return nil
+	}
+	*v = unknownE1(text)
+	return nil
+}
+
+// Format implements the fmt.Formatter interface for E1. The %s and %q
+// verbs format the string of v, %d formats its index, and %v formats its type
+// and string, as E1(text). Other verbs are reported as errors.
+func (v E1) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 's', 'q':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), v.String())
+	case 'd':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), v.Index())
+	case 'v':
+		fmt.Fprintf(f, "E1(%s)", v.String())
+	default:
+		fmt.Fprintf(f, "%%!%c(E1=%s)", verb, v.String())
+	}
+}
+
+// _entry_E1 is an interned entry of the string table of E1.
+// Each non-zero enumerator of E1 points to its own entry.
+type _entry_E1 struct {
+	str string
+	ord uint8
+}
+
+// ordinal returns the position of e in the string table, or 0 if e is nil.
+func (e *_entry_E1) ordinal() uint8 {
+	if e == nil {
+		return 0
+	}
+	return e.ord
+}
+
+// unknownE1 returns a E1 that is not valid, but whose string is
+// text, for strings that do not encode a known enumerator. Values with the same
+// string are equal, since their entries are interned in
+// _unknown_E1.
+func unknownE1(text string) E1 {
+	_unknown_E1.Lock()
+	defer _unknown_E1.Unlock()
+	ent, ok := _unknown_E1.ents[text]
+	if !ok {
+		if _unknown_E1.ents == nil {
+			_unknown_E1.ents = make(map[string]*_entry_E1)
+		}
+		ent = &_entry_E1{str: text}
+		_unknown_E1.ents[text] = ent
+	}
+	return E1{ent}
+}
+
+// _unknown_E1 holds the entries for the unknown strings of E1.
+// Their ordinal is 0, so apart from their strings they behave as the zero value.
+var _unknown_E1 struct {
+	sync.Mutex
+	ents map[string]*_entry_E1
+}
 var (
-	_str_E1 = []string{"<invalid>", "alpha", "bravo", "C"}
+	_str_E1   = []string{"<invalid>", "alpha", "bravo", "C"}
+	_text_E1  = [][]byte{[]byte("<invalid>"), []byte("alpha"), []byte("bravo"), []byte("C")}
+	_bystr_E1 = map[string]int{"C": 2, "alpha": 0, "bravo": 1}
+	_ents_E1  = []_entry_E1{{"<invalid>", 0}, {"alpha", 1}, {"bravo", 2}, {"C", 3}}
 
-	A = E1{1}
-	B = E1{2}
-	C = E1{3}
+	A = E1{&_ents_E1[1]}
+	B = E1{&_ents_E1[2]}
+	C = E1{&_ents_E1[3]}
 )
 
 type E2 struct{ _E2 uint8 }
@@ -35,46 +241,225 @@ type E2 struct{ _E2 uint8 }
 func (E2) Enum() string { return "E2" }
 
 // String returns the string representation of E2 v.
-func (v E2) String() string { return _str_E2[v._E2] }
+func (v E2) String() string { return _str_E2[_stroff_E2[v._E2]:_stroff_E2[v._E2+1]] }
 
 // Valid reports whether v is a valid non-zero E2 value.
-func (v E2) Valid() bool { return v._E2 > 0 && int(v._E2) < len(_str_E2) }
+func (v E2) Valid() bool { return v._E2 > 0 && int(v._E2) < (len(_stroff_E2)-1) }
 
 // Index returns the integer index of E2 v.
 func (v E2) Index() int { return int(v._E2) }
 
+// IsAll reports whether v belongs to the All group of E2.
+func (v E2) IsAll() bool {
+	switch v {
+	case E2_B, E2_A:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFirst reports whether v belongs to the First group of E2.
+func (v E2) IsFirst() bool {
+	switch v {
+	case E2_A:
+		return true
+	default:
+		return false
+	}
+}
+
+// The enumerators of each group of E2, in the order listed in the config.
+var (
+	E2All   = []E2{E2_B, E2_A}
+	E2First = []E2{E2_A}
+)
+
+// IsInvalid reports whether v is E2_Invalid.
+func (v E2) IsInvalid() bool { return v == E2_Invalid }
+
+// IsA reports whether v is E2_A.
+func (v E2) IsA() bool { return v == E2_A }
+
+// IsB reports whether v is E2_B.
+func (v E2) IsB() bool { return v == E2_B }
+
+// Display returns the display name of E2 v in the language lang, a
+// language tag such as "en" or "pt-BR". If v has no display name for lang, it
+// falls back to the base language of lang, then to "en", and
+// finally to the string representation of v.
+func (v E2) Display(lang string) string {
+	names := _display_E2[v._E2]
+	if s, ok := names[lang]; ok {
+		return s
+	}
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		if s, ok := names[base]; ok {
+			return s
+		}
+	}
+	if s, ok := names["en"]; ok {
+		return s
+	}
+	return v.String()
+}
+
+// invalidE2 returns the error reported when s does not match any
+// E2 enumerator.
+func invalidE2(s string) error {
+	return &InvalidEnumError{Type: "E2", Input: s}
+}
+
+// newE2 returns the first enumerator of E2 whose string is a
+// case-insensitive match for s. If no enumerator matches, it returns the
+// zero enumerator.
+func newE2(s string) E2 {
+	for i := 0; i+2 < len(_stroff_E2); i++ {
+		opt := _str_E2[_stroff_E2[i+1]:_stroff_E2[i+2]]
+		if strings.EqualFold(opt, s) {
+			return E2{uint8(i + 1)}
+		}
+	}
+	return E2{}
+}
+
+// findE2 returns the position of the enumerator of E2 whose string
+// is text, less one, and reports whether there is one.
+func findE2(text string) (int, bool) {
+	for i := 0; i+2 < len(_stroff_E2); i++ {
+		opt := _str_E2[_stroff_E2[i+1]:_stroff_E2[i+2]]
+		if opt == text {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// ProvideDefaultE2 returns the default E2 enumerator, E2_B.
+// It is suitable for use as a provider with dependency injection frameworks
+// such as github.com/google/wire and go.uber.org/fx.
+func ProvideDefaultE2() E2 { return E2_B }
+
+// ProvideE2FromEnv returns the first enumerator of E2 whose string
+// is a case-insensitive match for the value of the ENUMGEN_TEST_E2 environment
+// variable. If the variable is unset or empty, it returns E2_B.
+// It reports an error if the value does not match any enumerator.
+// It is suitable for use as a provider with dependency injection frameworks
+// such as github.com/google/wire and go.uber.org/fx.
+func ProvideE2FromEnv() (E2, error) {
+	s := os.Getenv("ENUMGEN_TEST_E2")
+	if s == "" {
+		return E2_B, nil
+	}
+	if e := newE2(s); e.Valid() {
+		return e, nil
+	}
+	return E2{}, fmt.Errorf("invalid value for ENUMGEN_TEST_E2: %q", s)
+}
+
+// AppendText appends the text encoding of the E2 enumerator to b.
+// It does not allocate if b has enough capacity.
+// It satisfies the encoding.TextAppender interface.
+func (v E2) AppendText(b []byte) ([]byte, error) { return append(b, v.String()...), nil }
+
+// MarshalText encodes the value of the E2 enumerator as text.
+// It does not allocate. The result is a slice of a shared table, which the
+// caller must not modify.
+// It satisfies the encoding.TextMarshaler interface.
+func (v E2) MarshalText() ([]byte, error) {
+	i := v._E2
+	return _text_E2[_stroff_E2[i]:_stroff_E2[i+1]:_stroff_E2[i+1]], nil
+}
+
+// UnarshalText decodes the value of the E2 enumerator from a string.
+// It reports an error if data does not encode a known enumerator.
+// An empty slice decodes to the zero value.
+// This method satisfies the encoding.TextUnmarshaler interface.
+func (v *E2) UnmarshalText(data []byte) error {
+	*v = E2{}
+	text := string(data)
+	if text == "" || text == _str_E2[_stroff_E2[0]:_stroff_E2[1]] {
+		return nil
+	}
+	if i, ok := findE2(text); ok {
+		*v = E2{uint8(i + 1)}
+		return nil
+	}
+	return invalidE2(text)
+}
+
 var (
-	_str_E2 = []string{"<invalid>", "A", "B"}
+	_str_E2     = "<invalid>AB"
+	_stroff_E2  = []uint16{0, 9, 10, 11}
+	_text_E2    = []byte("<invalid>AB")
+	_display_E2 = []map[string]string{nil, {"de": "Apfel", "en": "Apple", "pt-BR": "Maçã"}, {"de": "Birne"}}
 
 	E2_Invalid = E2{0}
 	E2_A       = E2{1}
 	E2_B       = E2{2}
 )
 
-type E3 struct{ _E3 uint8 }
+// The representation of E3 is fixed as uint32, so its size
+// does not change as enumerators are added.
+type E3 struct{ _E3 uint32 }
 
 // Enum returns the name of the enumeration type for E3.
 func (E3) Enum() string { return "E3" }
 
-// String returns the string representation of E3 v.
-func (v E3) String() string { return _str_E3[v._E3] }
+// String returns the string representation of E3 e3.
+func (e3 E3) String() string { return _str_E3[e3._E3] }
 
-// Valid reports whether v is a valid non-zero E3 value.
-func (v E3) Valid() bool { return v._E3 > 0 && int(v._E3) < len(_str_E3) }
+// Valid reports whether e3 is a valid non-zero E3 value.
+func (e3 E3) Valid() bool { return e3._E3 > 0 && int(e3._E3) < len(_str_E3) }
 
-// Index returns the integer index of E3 v.
-func (v E3) Index() int { return int(v._E3) }
+// Enumerators returns the valid enumerators of E3, in order.
+// The caller must not modify the result.
+// It satisfies the enum.Enum interface.
+func (E3) Enumerators() []E3 { return _vals_E3 }
 
-// newE3 returns the first enumerator of E3 whose string is a
-// case-insensitive match for s. If no enumerator matches, it returns the
-// zero enumerator.
-func newE3(s string) E3 {
+// Index returns the integer index of E3 e3.
+func (e3 E3) Index() int { return int(e3._E3) }
+
+// E3Strings returns the strings of the valid enumerators of E3,
+// in order. The caller may modify the returned slice.
+func E3Strings() []string { return append([]string(nil), _str_E3[1:]...) }
+
+// E3Names returns the names of the valid enumerators of E3,
+// in order. The caller may modify the returned slice.
+func E3Names() []string {
+	return []string{"X", "Y"}
+}
+
+// E3Completions returns the strings of the valid enumerators of
+// E3 that begin with prefix, in order, for use in shell completion.
+func E3Completions(prefix string) []string {
+	var out []string
+	for i := 1; i < len(_str_E3); i++ {
+		if opt := _str_E3[i]; strings.HasPrefix(opt, prefix) {
+			out = append(out, opt)
+		}
+	}
+	return out
+}
+
+// findE3 returns the position of the enumerator of E3 whose string
+// is text, less one, and reports whether there is one.
+func findE3(text string) (int, bool) {
 	for i, opt := range _str_E3[1:] {
-		if strings.EqualFold(opt, s) {
-			return E3{uint8(i + 1)}
+		if opt == text {
+			return i, true
 		}
 	}
-	return E3{0}
+	return 0, false
+}
+
+// ParseE3List parses s as a list of E3 enumerators separated by
+// sep, or by "," if sep == "". Each element is trimmed of surrounding
+// whitespace, and empty elements are skipped. Each remaining element must
+// exactly match the string representation of a non-zero enumerator.
+// It is an error for an enumerator to occur more than once.
+func ParseE3List(s, sep string) ([]E3, error) {
+	return enum.ParseList[E3](s, sep, true)
 }
 
 // E3FromIndex returns the first enumerator of E3 whose index equals v.
@@ -84,49 +469,108 @@ func E3FromIndex(v int) E3 {
 	if v <= 0 || v >= len(_str_E3) {
 		return zero
 	}
-	return E3{uint8(v)}
+	return E3{uint32(v)}
 }
 
 // Set implements part of the flag.Value interface for E3.
 // A value must equal the string representation of an enumerator.
-func (v *E3) Set(s string) error {
-	if e := newE3(s); e.Valid() {
-		*v = e
-		return nil
+func (e3 *E3) Set(s string) error {
+	e, err := enum.Parse[E3](s)
+	if err == nil {
+		*e3 = e
 	}
-	return fmt.Errorf("invalid value for E3: %q", s)
+	return err
 }
 
+// AppendText appends the text encoding of the E3 enumerator to b.
+// It does not allocate if b has enough capacity.
+// It satisfies the encoding.TextAppender interface.
+func (e3 E3) AppendText(b []byte) ([]byte, error) { return append(b, e3.String()...), nil }
+
 // MarshalText encodes the value of the E3 enumerator as text.
+// It does not allocate. The result is a slice of a shared table, which the
+// caller must not modify.
 // It satisfies the encoding.TextMarshaler interface.
-func (v E3) MarshalText() ([]byte, error) { return []byte(v.String()), nil }
+func (e3 E3) MarshalText() ([]byte, error) {
+	b := _text_E3[e3._E3]
+	return b[:len(b):len(b)], nil
+}
 
 // UnarshalText decodes the value of the E3 enumerator from a string.
 // It reports an error if data does not encode a known enumerator.
 // An empty slice decodes to the zero value.
 // This method satisfies the encoding.TextUnmarshaler interface.
-func (v *E3) UnmarshalText(data []byte) error {
-	*v = E3{}
-	text := string(data)
-	if text == "" || text == _str_E3[0] {
+func (e3 *E3) UnmarshalText(data []byte) error {
+	return enum.UnmarshalText(data, e3)
+}
+
+// SetQuery sets the value of key in q to the string of E3 e3, replacing
+// any existing values.
+func (e3 E3) SetQuery(q url.Values, key string) { q.Set(key, e3.String()) }
+
+// E3FromQuery returns the E3 enumerator whose string is the value of
+// key in q, as decoded by UnmarshalText. If q has no value for key, it returns
+// the zero enumerator.
+func E3FromQuery(q url.Values, key string) (E3, error) {
+	var v E3
+	err := v.UnmarshalText([]byte(q.Get(key)))
+	return v, err
+}
+
+// MarshalXML encodes the value of the E3 enumerator as the text of an
+// XML element. It satisfies the xml.Marshaler interface.
+func (e3 E3) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(e3.String(), start)
+}
+
+// UnmarshalXML decodes the value of the E3 enumerator from the text of
+// an XML element, with the same rules as UnmarshalXMLAttr.
+// It satisfies the xml.Unmarshaler interface.
+func (e3 *E3) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var text string
+	if err := d.DecodeElement(&text, &start); err != nil {
+		return err
+	}
+	return e3.UnmarshalXMLAttr(xml.Attr{Name: start.Name, Value: text})
+}
+
+// MarshalXMLAttr encodes the value of the E3 enumerator as an XML
+// attribute. It satisfies the xml.MarshalerAttr interface.
+func (e3 E3) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: e3.String()}, nil
+}
+
+// UnmarshalXMLAttr decodes the value of the E3 enumerator from an XML
+// attribute. It reports an error if the value does not encode a known
+// enumerator. An empty value decodes to the zero value.
+// It satisfies the xml.UnmarshalerAttr interface.
+func (e3 *E3) UnmarshalXMLAttr(attr xml.Attr) error {
+	*e3 = E3{}
+	if attr.Value == "" || attr.Value == _str_E3[0] {
 		return nil
 	}
-	for i, opt := range _str_E3[1:] {
-		if opt == text {
-			v._E3 = uint8(i + 1)
-			return nil
-		}
+	if i, ok := findE3(attr.Value); ok {
+		*e3 = E3{uint32(i + 1)}
+		return nil
 	}
-	return fmt.Errorf("invalid value for E3: %q", text)
+	return fmt.Errorf("invalid value for E3: %q", attr.Value)
 }
 
+// LogValue returns the value of the E3 enumerator for logging.
+// It satisfies the slog.LogValuer interface.
+// The value is the string of e3.
+func (e3 E3) LogValue() slog.Value { return slog.StringValue(e3.String()) }
+
 var (
-	_str_E3 = []string{"<invalid>", "foo", "bar"}
+	_str_E3  = []string{"none", "foo", "bar"}
+	_text_E3 = [][]byte{[]byte("none"), []byte("foo"), []byte("bar")}
+	_vals_E3 = []E3{{1}, {2}}
 
 	X = E3{1}
 	Y = E3{2}
 )
 
+//enumgen:extensible
 type Count struct{ _Count uint8 }
 
 // Enum returns the name of the enumeration type for Count.
@@ -139,16 +583,1464 @@ func (v Count) String() string { return _str_Count[v._Count] }
 func (v Count) Valid() bool { return v._Count > 0 && int(v._Count) < len(_str_Count) }
 
 // Index returns the integer index of Count v.
-func (v Count) Index() int { return int(v._Count) }
+func (v Count) Index() int { return _idx_Count[v._Count] }
+
+// CountDocs returns a map from each documented enumerator of Count to
+// the text of its doc comment. The caller may modify the result.
+func CountDocs() map[Count]string {
+	return map[Count]string{
+		Zero: "Nothing to see here",
+		One:  "The very loneliest",
+	}
+}
+
+// CountDeprecations returns a map from each deprecated enumerator of
+// Count to the text explaining its deprecation. The caller may modify the
+// result.
+func CountDeprecations() map[Count]string {
+	return map[Count]string{
+		Two: "Use One twice.",
+	}
+}
+
+// CountFromIndex returns the first enumerator of Count whose index equals v.
+// If no enumerator matches, it returns the zero enumerator.
+func CountFromIndex(v int) Count {
+	var zero Count
+	switch v {
+	case One.Index():
+		return One
+	case Two.Index():
+		return Two
+	default:
+		return zero
+	}
+}
+
+// LogValue returns the value of the Count enumerator for logging.
+// It satisfies the slog.LogValuer interface.
+// The value is a group containing the type name and the string of v.
+func (v Count) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("type", "Count"), slog.String("value", v.String()))
+}
+
+// CountJSONSchemaFragment returns a JSON Schema definition for the string
+// representation of Count, whose permitted values are the strings of the
+// valid enumerators. It is suitable for validating request bodies at runtime.
+func CountJSONSchemaFragment() string {
+	return "{\"type\":\"string\",\"enum\":[\"lonely\",\"tango\"],\"x-extensible\":true}"
+}
 
 var (
 	_str_Count = []string{"zilch", "lonely", "tango"}
+	_idx_Count = []int{-1, 0, 1}
 
 	Zero = Count{0} // Nothing to see here
 	One  = Count{1} // The very loneliest
-	Two  = Count{2}
+	// Deprecated: Use One twice.
+	Two = Count{2}
+)
+
+type Hashed struct{ _Hashed uint8 }
+
+// Enum returns the name of the enumeration type for Hashed.
+func (Hashed) Enum() string { return "Hashed" }
+
+// String returns the string representation of Hashed v.
+func (v Hashed) String() string { return _str_Hashed_644ae255[v._Hashed] }
+
+// Valid reports whether v is a valid non-zero Hashed value.
+func (v Hashed) Valid() bool { return v._Hashed > 0 && int(v._Hashed) < len(_str_Hashed_644ae255) }
+
+// Enumerators returns the valid enumerators of Hashed, in order.
+// The caller must not modify the result.
+// It satisfies the enum.Enum interface.
+func (Hashed) Enumerators() []Hashed { return _vals_Hashed_644ae255 }
+
+// Index returns the integer index of Hashed v.
+func (v Hashed) Index() int { return _idx_Hashed_644ae255[v._Hashed] }
+
+// NewHashed returns the first enumerator of Hashed whose string is a
+// case-insensitive match for s. If no enumerator matches, it returns the
+// zero enumerator.
+func NewHashed(s string) Hashed {
+	return enum.Lookup[Hashed](s)
+}
+
+// ParseHashed returns the first enumerator of Hashed whose string is a
+// case-insensitive match for s. If no enumerator matches, it reports an error.
+func ParseHashed(s string) (Hashed, error) {
+	return enum.Parse[Hashed](s)
+}
+
+// MustHashed returns the first enumerator of Hashed whose string is a
+// case-insensitive match for s. If no enumerator matches, it panics.
+func MustHashed(s string) Hashed {
+	return enum.Must[Hashed](s)
+}
+
+// _ctxkey_Hashed_644ae255 is the type of the context key for Hashed values.
+type _ctxkey_Hashed_644ae255 struct{}
+
+// WithHashed returns a copy of ctx that carries the Hashed enumerator v.
+func WithHashed(ctx context.Context, v Hashed) context.Context {
+	return context.WithValue(ctx, _ctxkey_Hashed_644ae255{}, v)
+}
+
+// HashedFromContext returns the Hashed enumerator carried by ctx, and
+// reports whether ctx carries one. If not, it returns the zero enumerator.
+func HashedFromContext(ctx context.Context) (Hashed, bool) {
+	v, ok := ctx.Value(_ctxkey_Hashed_644ae255{}).(Hashed)
+	return v, ok
+}
+
+var (
+	_str_Hashed_644ae255  = []string{"<invalid>", "H1", "H2"}
+	_idx_Hashed_644ae255  = []int{0, 3, 4}
+	_vals_Hashed_644ae255 = []Hashed{{1}, {2}}
+
+	H1 = Hashed{1}
+	H2 = Hashed{2}
 )
 
+type Grouped struct{ _Grouped *_enumgen_Grouped_entry }
+
+// Enum returns the name of the enumeration type for Grouped.
+func (Grouped) Enum() string { return "Grouped" }
+
+// String returns the string representation of Grouped v.
+func (v Grouped) String() string {
+	if v._Grouped == nil {
+		return _enumgen_Grouped.str[_enumgen_Grouped.stroff[0]:_enumgen_Grouped.stroff[1]]
+	}
+	return v._Grouped.str
+}
+
+// Valid reports whether v is a valid non-zero Grouped value.
+func (v Grouped) Valid() bool { return v._Grouped != nil }
+
+// Enumerators returns the valid enumerators of Grouped, in order.
+// The caller must not modify the result.
+// It satisfies the enum.Enum interface.
+func (Grouped) Enumerators() []Grouped { return _enumgen_Grouped.vals }
+
+// Index returns the integer index of Grouped v.
+func (v Grouped) Index() int { return _enumgen_Grouped.idx[v._Grouped.ordinal()] }
+
+// Code returns the integer code of Grouped v, or 0 if v is not valid.
+func (v Grouped) Code() int8 { return _enumgen_Grouped.code[v._Grouped.ordinal()] }
+
+// GroupedFromCode returns the enumerator of Grouped whose code is c.
+// If no enumerator matches, it returns the zero enumerator.
+func GroupedFromCode(c int8) Grouped { return _enumgen_Grouped.bycode()[c] }
+
+// Rank returns the rank attribute of Grouped v.
+func (v Grouped) Rank() int64 { return _enumgen_Grouped.attr_rank[v._Grouped.ordinal()] }
+
+// findGrouped returns the position of the enumerator of Grouped whose string
+// is text, less one, and reports whether there is one.
+func findGrouped(text string) (int, bool) {
+	for i := 0; i+2 < len(_enumgen_Grouped.stroff); i++ {
+		opt := _enumgen_Grouped.str[_enumgen_Grouped.stroff[i+1]:_enumgen_Grouped.stroff[i+2]]
+		if opt == text {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// GroupedFromIndex returns the first enumerator of Grouped whose index equals v.
+// If no enumerator matches, it returns the zero enumerator.
+func GroupedFromIndex(v int) Grouped {
+	var zero Grouped
+	switch v {
+	case G1.Index():
+		return G1
+	case G2.Index():
+		return G2
+	default:
+		return zero
+	}
+}
+
+// MigrateOldGroupedIndex returns the enumerator of Grouped whose index was
+// old before the indices of Grouped were compacted. If old was not changed,
+// it returns the enumerator whose index is old. If no enumerator matches, it
+// returns the zero enumerator.
+func MigrateOldGroupedIndex(old int) Grouped {
+	switch old {
+	case 3:
+		return G2
+	}
+	for i := 1; i < (len(_enumgen_Grouped.stroff) - 1); i++ {
+		if v := (Grouped{&_enumgen_Grouped_ents[i]}); v.Index() == old {
+			return v
+		}
+	}
+	return Grouped{}
+}
+
+// AppendText appends the text encoding of the Grouped enumerator to b.
+// It does not allocate if b has enough capacity.
+// It satisfies the encoding.TextAppender interface.
+func (v Grouped) AppendText(b []byte) ([]byte, error) { return append(b, v.String()...), nil }
+
+// MarshalText encodes the value of the Grouped enumerator as text.
+// It does not allocate. The result is a slice of a shared table, which the
+// caller must not modify.
+// It satisfies the encoding.TextMarshaler interface.
+func (v Grouped) MarshalText() ([]byte, error) {
+	i := v._Grouped.ordinal()
+	return _enumgen_Grouped.text[_enumgen_Grouped.stroff[i]:_enumgen_Grouped.stroff[i+1]:_enumgen_Grouped.stroff[i+1]], nil
+}
+
+// UnarshalText decodes the value of the Grouped enumerator from a string.
+// It reports an error if data does not encode a known enumerator.
+// An empty slice decodes to the zero value.
+// This method satisfies the encoding.TextUnmarshaler interface.
+func (v *Grouped) UnmarshalText(data []byte) error {
+	return enum.UnmarshalText(data, v)
+}
+
+// MarshalCBOR encodes the value of the Grouped enumerator as a CBOR integer,
+// its code. The zero enumerator is encoded as null.
+// It satisfies the cbor.Marshaler interface of github.com/fxamacker/cbor.
+func (v Grouped) MarshalCBOR() ([]byte, error) {
+	if !v.Valid() {
+		return []byte{0xf6}, nil // null
+	}
+	if c := int64(_enumgen_Grouped.code[v._Grouped.ordinal()]); c < 0 {
+		return appendCBORGrouped(nil, 1, uint64(-1-c)), nil
+	} else {
+		return appendCBORGrouped(nil, 0, uint64(c)), nil
+	}
+}
+
+// UnmarshalCBOR decodes the value of the Grouped enumerator from a CBOR
+// integer, its code.
+// It reports an error if data does not encode a known enumerator.
+// A null or undefined value decodes to the zero value.
+// It satisfies the cbor.Unmarshaler interface of github.com/fxamacker/cbor.
+func (v *Grouped) UnmarshalCBOR(data []byte) error {
+	*v = Grouped{}
+	if len(data) == 1 && (data[0] == 0xf6 || data[0] == 0xf7) {
+		return nil // null or undefined
+	}
+	major, n, rest, err := readCBORGrouped(data)
+	if err != nil {
+		return err
+	}
+	var c int64
+	switch {
+	case len(rest) != 0:
+		return fmt.Errorf("invalid CBOR for Grouped: %d extra bytes", len(rest))
+	case major == 0 && n <= math.MaxInt64:
+		c = int64(n)
+	case major == 1 && n <= math.MaxInt64:
+		c = -1 - int64(n)
+	default:
+		return fmt.Errorf("invalid CBOR for Grouped: want an integer code")
+	}
+	if int64(int8(c)) == c {
+		if e, ok := _enumgen_Grouped.bycode()[int8(c)]; ok {
+			*v = e
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid code for Grouped: %d", c)
+}
+
+// appendCBORGrouped appends to b the head of a CBOR data item with the
+// given major type and argument n, in its shortest form.
+func appendCBORGrouped(b []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(b, major<<5|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, major<<5|24, byte(n))
+	case n <= math.MaxUint16:
+		return append(b, major<<5|25, byte(n>>8), byte(n))
+	case n <= math.MaxUint32:
+		return append(b, major<<5|26, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(b, major<<5|27, byte(n>>56), byte(n>>48), byte(n>>40), byte(n>>32),
+		byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+// readCBORGrouped decodes the head of the CBOR data item in data, and
+// returns its major type and argument, and the remainder of data. It does not
+// support items of indefinite length.
+func readCBORGrouped(data []byte) (major byte, n uint64, rest []byte, err error) {
+	if len(data) == 0 {
+		return 0, 0, nil, fmt.Errorf("invalid CBOR for Grouped: no data")
+	}
+	major, info := data[0]>>5, data[0]&0x1f
+	switch {
+	case info < 24:
+		return major, uint64(info), data[1:], nil
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(data) < 1+size {
+			return 0, 0, nil, fmt.Errorf("invalid CBOR for Grouped: truncated data")
+		}
+		for _, b := range data[1 : 1+size] {
+			n = n<<8 | uint64(b)
+		}
+		return major, n, data[1+size:], nil
+	}
+	return 0, 0, nil, fmt.Errorf("invalid CBOR for Grouped: unsupported item")
+}
+
+// _enumgen_Grouped_ctxkey is the type of the context key for Grouped values.
+type _enumgen_Grouped_ctxkey struct{}
+
+// WithGrouped returns a copy of ctx that carries the Grouped enumerator v.
+func WithGrouped(ctx context.Context, v Grouped) context.Context {
+	return context.WithValue(ctx, _enumgen_Grouped_ctxkey{}, v)
+}
+
+// GroupedFromContext returns the Grouped enumerator carried by ctx, and
+// reports whether ctx carries one. If not, it returns the zero enumerator.
+func GroupedFromContext(ctx context.Context) (Grouped, bool) {
+	v, ok := ctx.Value(_enumgen_Grouped_ctxkey{}).(Grouped)
+	return v, ok
+}
+
+// Value encodes the Grouped enumerator as its string representation for
+// storage in a database. The zero enumerator is stored as NULL.
+// It satisfies the driver.Valuer interface.
+func (v Grouped) Value() (driver.Value, error) {
+	if !v.Valid() {
+		return nil, nil
+	}
+	return v.String(), nil
+}
+
+// Scan decodes a Grouped enumerator from a database value, which must be a
+// string, a byte slice, or NULL. NULL and empty values decode to the zero
+// enumerator. It satisfies the sql.Scanner interface.
+func (v *Grouped) Scan(src any) error {
+	var text string
+	switch t := src.(type) {
+	case nil:
+	case string:
+		text = t
+	case []byte:
+		text = string(t)
+	default:
+		return fmt.Errorf("cannot scan %T into Grouped", src)
+	}
+	*v = Grouped{}
+	if text == "" || text == _enumgen_Grouped.str[_enumgen_Grouped.stroff[0]:_enumgen_Grouped.stroff[1]] {
+		return nil
+	}
+	if i, ok := findGrouped(text); ok {
+		*v = Grouped{&_enumgen_Grouped_ents[i+1]}
+		return nil
+	}
+	return fmt.Errorf("invalid value for Grouped: %q", text)
+}
+
+// _enumgen_Grouped_entry is an interned entry of the string table of Grouped.
+// Each non-zero enumerator of Grouped points to its own entry.
+type _enumgen_Grouped_entry struct {
+	str string
+	ord uint8
+}
+
+// ordinal returns the position of e in the string table, or 0 if e is nil.
+func (e *_enumgen_Grouped_entry) ordinal() uint8 {
+	if e == nil {
+		return 0
+	}
+	return e.ord
+}
+
+var (
+	_enumgen_Grouped = struct {
+		str       string
+		stroff    []uint16
+		text      []byte
+		idx       []int
+		code      []int8
+		bycode    func() map[int8]Grouped
+		vals      []Grouped
+		attr_rank []int64
+	}{
+		str:    "<invalid>firstsecond",
+		stroff: []uint16{0, 9, 14, 20},
+		text:   []byte("<invalid>firstsecond"),
+		idx:    []int{0, 1, 5},
+		code:   []int8{0, -20, 10},
+		bycode: sync.OnceValue(func() map[int8]Grouped {
+			return map[int8]Grouped{-20: {&_enumgen_Grouped_ents[1]}, 10: {&_enumgen_Grouped_ents[2]}}
+		}),
+		vals:      []Grouped{{&_enumgen_Grouped_ents[1]}, {&_enumgen_Grouped_ents[2]}},
+		attr_rank: []int64{0, -3, 0},
+	}
+	_enumgen_Grouped_ents = []_enumgen_Grouped_entry{{"<invalid>", 0}, {"first", 1}, {"second", 2}}
+
+	G1 = Grouped{&_enumgen_Grouped_ents[1]}
+	G2 = Grouped{&_enumgen_Grouped_ents[2]}
+)
+
+type Status struct{ _Status uint8 }
+
+// Enum returns the name of the enumeration type for Status.
+func (Status) Enum() string { return "Status" }
+
+// Label returns the string representation of Status st.
+func (st Status) Label() string {
+	return _str_Status[_stroff_Status[st._Status]:_stroff_Status[st._Status+1]]
+}
+
+// Valid reports whether st is a valid non-zero Status value.
+func (st Status) Valid() bool { return st._Status > 0 && int(st._Status) < (len(_stroff_Status)-1) }
+
+// StatusStrings returns the strings of the valid enumerators of Status,
+// in order. The caller may modify the returned slice.
+func StatusStrings() []string {
+	out := make([]string, 0, 3)
+	for i := 0; i+2 < len(_stroff_Status); i++ {
+		opt := _str_Status[_stroff_Status[i+1]:_stroff_Status[i+2]]
+		out = append(out, opt)
+	}
+	return out
+}
+
+// StatusNames returns the names of the valid enumerators of Status,
+// in order. The caller may modify the returned slice.
+func StatusNames() []string {
+	return []string{"OK", "NotFound", "Teapot"}
+}
+
+// StatusCompletions returns the strings of the valid enumerators of
+// Status that begin with prefix, in order, for use in shell completion.
+func StatusCompletions(prefix string) []string {
+	var out []string
+	for i := 1; i < (len(_stroff_Status) - 1); i++ {
+		if opt := _str_Status[_stroff_Status[i]:_stroff_Status[i+1]]; strings.HasPrefix(opt, prefix) {
+			out = append(out, opt)
+		}
+	}
+	return out
+}
+
+// StatusChoice is one of the choices listed by StatusChoices, for use in
+// templates that render a form control, such as an HTML <select> element.
+type StatusChoice struct {
+	Value    Status // the enumerator
+	Text     string // the string of the enumerator
+	Selected bool   // whether the enumerator is one of those selected
+}
+
+// StatusChoices returns a choice for each valid enumerator of Status, in
+// order, marking those equal to any of selected. To use it in a template,
+// add it to the template's FuncMap:
+//
+//	t.Funcs(template.FuncMap{"StatusChoices": StatusChoices})
+func StatusChoices(selected ...Status) []StatusChoice {
+	out := make([]StatusChoice, 0, 3)
+	for i := 1; i < (len(_stroff_Status) - 1); i++ {
+		c := StatusChoice{Value: Status{uint8(i)}, Text: _str_Status[_stroff_Status[i]:_stroff_Status[i+1]]}
+		c.Selected = slices.Contains(selected, c.Value)
+		out = append(out, c)
+	}
+	return out
+}
+
+// StatusFingerprint is a digest of the names, strings, and indices of the
+// enumerators of Status, in order. Programs built from different definitions
+// of Status can compare fingerprints to check that they agree.
+const StatusFingerprint = "sha256:94fdb4c59fb48ba8a7d7561da2d05bbc55a34464ed0ccd35cb34d62845dd4c54"
+
+// Code returns the integer code of Status st, or 0 if st is not valid.
+func (st Status) Code() int { return _code_Status[st._Status] }
+
+// StatusFromCode returns the enumerator of Status whose code is c.
+// If no enumerator matches, it returns the zero enumerator.
+func StatusFromCode(c int) Status { return _bycode_Status()[c] }
+
+// Reason returns the reason attribute of Status st.
+func (st Status) Reason() string { return _attr_reason_Status[st._Status] }
+
+// Retry returns the retry attribute of Status st.
+func (st Status) Retry() bool { return _attr_retry_Status[st._Status] }
+
+// Weight returns the weight attribute of Status st.
+func (st Status) Weight() float64 { return _attr_weight_Status[st._Status] }
+
+// NewStatus returns the first enumerator of Status whose string is a
+// case-insensitive match for s. If no enumerator matches, it returns the
+// zero enumerator.
+func NewStatus(s string) Status {
+	if i, ok := _byfold_Status()[strings.ToLower(s)]; ok {
+		return Status{uint8(i + 1)}
+	}
+
+	// Fall back to a scan for strings whose case folding differs from their
+	// lower-case form.
+	for i := 0; i+2 < len(_stroff_Status); i++ {
+		opt := _str_Status[_stroff_Status[i+1]:_stroff_Status[i+2]]
+		if strings.EqualFold(opt, s) {
+			return Status{uint8(i + 1)}
+		}
+	}
+	return Status{}
+}
+
+// findStatus returns the position of the enumerator of Status whose string
+// is text, less one, and reports whether there is one.
+func findStatus(text string) (int, bool) {
+	i, ok := _bystr_Status()[text]
+	return i, ok
+}
+
+// MarshalCBOR encodes the value of the Status enumerator as a CBOR text
+// string, its string representation. The zero enumerator is encoded as null.
+// It satisfies the cbor.Marshaler interface of github.com/fxamacker/cbor.
+func (st Status) MarshalCBOR() ([]byte, error) {
+	if !st.Valid() {
+		return []byte{0xf6}, nil // null
+	}
+	s := st.Label()
+	return append(appendCBORStatus(make([]byte, 0, 9+len(s)), 3, uint64(len(s))), s...), nil
+}
+
+// UnmarshalCBOR decodes the value of the Status enumerator from a CBOR
+// text string, its string representation.
+// It reports an error if data does not encode a known enumerator.
+// A null or undefined value decodes to the zero value.
+// It satisfies the cbor.Unmarshaler interface of github.com/fxamacker/cbor.
+func (st *Status) UnmarshalCBOR(data []byte) error {
+	*st = Status{}
+	if len(data) == 1 && (data[0] == 0xf6 || data[0] == 0xf7) {
+		return nil // null or undefined
+	}
+	major, n, rest, err := readCBORStatus(data)
+	if err != nil {
+		return err
+	}
+	if major != 3 || uint64(len(rest)) != n {
+		return fmt.Errorf("invalid CBOR for Status: want a text string")
+	}
+	text := string(rest)
+	if text == "" || text == _str_Status[_stroff_Status[0]:_stroff_Status[1]] {
+		return nil
+	}
+	if i, ok := findStatus(text); ok {
+		*st = Status{uint8(i + 1)}
+		return nil
+	}
+	return fmt.Errorf("invalid value for Status: %q", text)
+}
+
+// appendCBORStatus appends to b the head of a CBOR data item with the
+// given major type and argument n, in its shortest form.
+func appendCBORStatus(b []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(b, major<<5|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, major<<5|24, byte(n))
+	case n <= math.MaxUint16:
+		return append(b, major<<5|25, byte(n>>8), byte(n))
+	case n <= math.MaxUint32:
+		return append(b, major<<5|26, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(b, major<<5|27, byte(n>>56), byte(n>>48), byte(n>>40), byte(n>>32),
+		byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+// readCBORStatus decodes the head of the CBOR data item in data, and
+// returns its major type and argument, and the remainder of data. It does not
+// support items of indefinite length.
+func readCBORStatus(data []byte) (major byte, n uint64, rest []byte, err error) {
+	if len(data) == 0 {
+		return 0, 0, nil, fmt.Errorf("invalid CBOR for Status: no data")
+	}
+	major, info := data[0]>>5, data[0]&0x1f
+	switch {
+	case info < 24:
+		return major, uint64(info), data[1:], nil
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(data) < 1+size {
+			return 0, 0, nil, fmt.Errorf("invalid CBOR for Status: truncated data")
+		}
+		for _, b := range data[1 : 1+size] {
+			n = n<<8 | uint64(b)
+		}
+		return major, n, data[1+size:], nil
+	}
+	return 0, 0, nil, fmt.Errorf("invalid CBOR for Status: unsupported item")
+}
+
+var (
+	_str_Status         = "UnknownOKNotFoundTeapot"
+	_stroff_Status      = []uint16{0, 7, 9, 17, 23}
+	_bystr_Status       = sync.OnceValue(func() map[string]int { return map[string]int{"NotFound": 1, "OK": 0, "Teapot": 2} })
+	_byfold_Status      = sync.OnceValue(func() map[string]int { return map[string]int{"notfound": 1, "ok": 0, "teapot": 2} })
+	_code_Status        = []int{0, 200, 404, 418}
+	_bycode_Status      = sync.OnceValue(func() map[int]Status { return map[int]Status{200: {1}, 404: {2}, 418: {3}} })
+	_attr_reason_Status = []string{"", "fine", "missing", ""}
+	_attr_retry_Status  = []bool{false, false, false, true}
+	_attr_weight_Status = []float64{0, 1, 0.5, 0}
+
+	Unknown  = Status{0}
+	OK       = Status{1}
+	NotFound = Status{2}
+	Teapot   = Status{3}
+)
+
+type secret struct{ _Secret uint8 }
+
+// Enum returns the name of the enumeration type for secret.
+func (secret) Enum() string { return "secret" }
+
+// String returns the string representation of secret v.
+func (v secret) String() string { return _str_Secret[v._Secret] }
+
+// Valid reports whether v is a valid non-zero secret value.
+func (v secret) Valid() bool { return v._Secret > 0 && int(v._Secret) < len(_str_Secret) }
+
+// Index returns the integer index of secret v.
+func (v secret) Index() int { return int(v._Secret) }
+
+// errInvalidSecret is the error wrapped by the errors reported when a
+// string does not match any secret enumerator.
+var errInvalidSecret = errors.New("invalid value for secret")
+
+// invalidSecret returns the error reported when s does not match any
+// secret enumerator.
+func invalidSecret(s string) error {
+	return fmt.Errorf("%w: %q", errInvalidSecret, s)
+}
+
+// newSecret returns the first enumerator of secret whose string is a
+// case-insensitive match for s. If no enumerator matches, it returns the
+// zero enumerator.
+func newSecret(s string) secret {
+	for i, opt := range _str_Secret[1:] {
+		if strings.EqualFold(opt, s) {
+			return secret{uint8(i + 1)}
+		}
+	}
+	return secret{}
+}
+
+// findSecret returns the position of the enumerator of secret whose string
+// is text, less one, and reports whether there is one.
+func findSecret(text string) (int, bool) {
+	for i, opt := range _str_Secret[1:] {
+		if opt == text {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// parseSecret returns the first enumerator of secret whose string is a
+// case-insensitive match for s. If no enumerator matches, it reports an error.
+func parseSecret(s string) (secret, error) {
+	if e := newSecret(s); e.Valid() {
+		return e, nil
+	}
+	return secret{}, invalidSecret(s)
+}
+
+// mustSecret returns the first enumerator of secret whose string is a
+// case-insensitive match for s. If no enumerator matches, it panics.
+func mustSecret(s string) secret {
+	e := newSecret(s)
+	if !e.Valid() {
+		panic(fmt.Sprintf("invalid value for secret: %q", s))
+	}
+	return e
+}
+
+// parseSecretList parses s as a list of secret enumerators separated by
+// sep, or by "," if sep == "". Each element is trimmed of surrounding
+// whitespace, and empty elements are skipped. Each remaining element must
+// exactly match the string representation of a non-zero enumerator.
+// Repeated enumerators are included in the result each time they occur.
+func parseSecretList(s, sep string) ([]secret, error) {
+	if sep == "" {
+		sep = ","
+	}
+	var out []secret
+next:
+	for _, elt := range strings.Split(s, sep) {
+		elt = strings.TrimSpace(elt)
+		if elt == "" {
+			continue
+		}
+		if i, ok := findSecret(elt); ok {
+			out = append(out, secret{uint8(i + 1)})
+			continue next
+		}
+		return nil, invalidSecret(elt)
+	}
+	return out, nil
+}
+
+// numSecret is the number of valid secret enumerators.
+// An array of this length can be indexed by the AsArrayIndex method.
+const numSecret = 2
+
+// AsArrayIndex returns a dense 0-based index for v, in the range
+// 0 ≤ i < numSecret, in the order the enumerators are defined.
+// It panics if v is not a valid enumerator.
+func (v secret) AsArrayIndex() int {
+	if !v.Valid() {
+		panic("secret: AsArrayIndex of invalid enumerator")
+	}
+	return int(v._Secret) - 1
+}
+
+// _ctxkey_Secret is the type of the context key for secret values.
+type _ctxkey_Secret struct{}
+
+// withSecret returns a copy of ctx that carries the secret enumerator v.
+func withSecret(ctx context.Context, v secret) context.Context {
+	return context.WithValue(ctx, _ctxkey_Secret{}, v)
+}
+
+// secretFromContext returns the secret enumerator carried by ctx, and
+// reports whether ctx carries one. If not, it returns the zero enumerator.
+func secretFromContext(ctx context.Context) (secret, bool) {
+	v, ok := ctx.Value(_ctxkey_Secret{}).(secret)
+	return v, ok
+}
+
+var (
+	_str_Secret = []string{"<invalid>", "Hidden", "Private"}
+
+	hidden  = secret{1}
+	private = secret{2}
+)
+
+type Sorted struct{ _Sorted uint8 }
+
+// Enum returns the name of the enumeration type for Sorted.
+func (Sorted) Enum() string { return "Sorted" }
+
+// String returns the string representation of Sorted v.
+func (v Sorted) String() string { return _str_Sorted[v._Sorted] }
+
+// Valid reports whether v is a valid non-zero Sorted value.
+func (v Sorted) Valid() bool { return v._Sorted > 0 && int(v._Sorted) < len(_str_Sorted) }
+
+// Index returns the integer index of Sorted v.
+func (v Sorted) Index() int { return _idx_Sorted[v._Sorted] }
+
+// SortedStrings returns the strings of the valid enumerators of Sorted,
+// in order. The caller may modify the returned slice.
+func SortedStrings() []string { return append([]string(nil), _str_Sorted[1:]...) }
+
+// findSorted returns the position of the enumerator of Sorted whose string
+// is text, less one, and reports whether there is one.
+func findSorted(text string) (int, bool) {
+	i, ok := _bystr_Sorted[text]
+	return i, ok
+}
+
+// SortedFromIndex returns the first enumerator of Sorted whose index equals v.
+// If no enumerator matches, it returns the zero enumerator.
+func SortedFromIndex(v int) Sorted {
+	var zero Sorted
+	switch v {
+	case Cherry.Index():
+		return Cherry
+	case Banana.Index():
+		return Banana
+	case Apple.Index():
+		return Apple
+	default:
+		return zero
+	}
+}
+
+// AppendText appends the text encoding of the Sorted enumerator to b.
+// It does not allocate if b has enough capacity.
+// It satisfies the encoding.TextAppender interface.
+func (v Sorted) AppendText(b []byte) ([]byte, error) { return append(b, v.String()...), nil }
+
+// MarshalText encodes the value of the Sorted enumerator as text.
+// It does not allocate. The result is a slice of a shared table, which the
+// caller must not modify.
+// It satisfies the encoding.TextMarshaler interface.
+func (v Sorted) MarshalText() ([]byte, error) {
+	b := _text_Sorted[v._Sorted]
+	return b[:len(b):len(b)], nil
+}
+
+// UnarshalText decodes the value of the Sorted enumerator from a string.
+// A string that does not encode a known enumerator decodes to the zero value.
+// An empty slice decodes to the zero value.
+// This method satisfies the encoding.TextUnmarshaler interface.
+func (v *Sorted) UnmarshalText(data []byte) error {
+	*v = Sorted{}
+	text := string(data)
+	if text == "" || text == _str_Sorted[0] {
+		return nil
+	}
+	if i, ok := findSorted(text); ok {
+		*v = Sorted{uint8(i + 1)}
+		return nil
+	}
+	return nil
+}
+
+var (
+	_str_Sorted   = []string{"<invalid>", "xigua", "yam", "zucchini"}
+	_text_Sorted  = [][]byte{[]byte("<invalid>"), []byte("xigua"), []byte("yam"), []byte("zucchini")}
+	_bystr_Sorted = map[string]int{"xigua": 0, "yam": 1, "zucchini": 2}
+	_idx_Sorted   = []int{0, 8, 7, 1}
+
+	Cherry = Sorted{1}
+	Banana = Sorted{2}
+	Apple  = Sorted{3}
+)
+
+type Shade struct{ _Shade *_entry_Shade }
+
+// Enum returns the name of the enumeration type for Shade.
+func (Shade) Enum() string { return "Shade" }
+
+// String returns the string representation of Shade v.
+func (v Shade) String() string {
+	if v._Shade == nil {
+		return _str_Shade[0]
+	}
+	return v._Shade.str
+}
+
+// Valid reports whether v is a valid non-zero Shade value.
+func (v Shade) Valid() bool { return v._Shade != nil }
+
+// Index returns the integer index of Shade v.
+func (v Shade) Index() int { return int(v._Shade.ordinal()) }
+
+// NewShade returns the first enumerator of Shade whose string is a
+// case-insensitive match for s. If no enumerator matches, it returns the
+// zero enumerator.
+func NewShade(s string) Shade {
+	for i, opt := range _str_Shade[1:] {
+		if strings.EqualFold(opt, s) {
+			return Shade{&_ents_Shade[i+1]}
+		}
+	}
+	return Shade{}
+}
+
+// ParseShade returns the first enumerator of Shade whose string is a
+// case-insensitive match for s. If no enumerator matches, it reports an error.
+func ParseShade(s string) (Shade, error) {
+	if e := NewShade(s); e.Valid() {
+		return e, nil
+	}
+	return Shade{}, fmt.Errorf("invalid value for Shade: %q", s)
+}
+
+// MustShade returns the first enumerator of Shade whose string is a
+// case-insensitive match for s. If no enumerator matches, it panics.
+func MustShade(s string) Shade {
+	e := NewShade(s)
+	if !e.Valid() {
+		panic(fmt.Sprintf("invalid value for Shade: %q", s))
+	}
+	return e
+}
+
+// ProvideShadeFromEnv returns the first enumerator of Shade whose string
+// is a case-insensitive match for the value of the ENUMGEN_TEST_SHADE environment
+// variable. If the variable is unset or empty, it returns the zero enumerator.
+// It reports an error if the value does not match any enumerator.
+// It is suitable for use as a provider with dependency injection frameworks
+// such as github.com/google/wire and go.uber.org/fx.
+func ProvideShadeFromEnv() (Shade, error) {
+	s := os.Getenv("ENUMGEN_TEST_SHADE")
+	if s == "" {
+		return Shade{}, nil
+	}
+	if e := NewShade(s); e.Valid() {
+		return e, nil
+	}
+	return Shade{}, fmt.Errorf("invalid value for ENUMGEN_TEST_SHADE: %q", s)
+}
+
+// Set implements part of the flag.Value interface for Shade.
+// A value must equal the string representation of an enumerator.
+func (v *Shade) Set(s string) error {
+	if e := NewShade(s); e.Valid() {
+		*v = e
+		return nil
+	}
+	return fmt.Errorf("invalid value for Shade: %q", s)
+}
+
+// _entry_Shade is an interned entry of the string table of Shade.
+// Each non-zero enumerator of Shade points to its own entry.
+type _entry_Shade struct {
+	str string
+	ord uint8
+}
+
+// ordinal returns the position of e in the string table, or 0 if e is nil.
+func (e *_entry_Shade) ordinal() uint8 {
+	if e == nil {
+		return 0
+	}
+	return e.ord
+}
+
+var (
+	_str_Shade  = []string{"<invalid>", "Light", "Dark"}
+	_ents_Shade = []_entry_Shade{{"<invalid>", 0}, {"Light", 1}, {"Dark", 2}}
+
+	Light = Shade{&_ents_Shade[1]}
+	Dark  = Shade{&_ents_Shade[2]}
+)
+
+type Phase struct{ _Phase uint8 }
+
+// Enum returns the name of the enumeration type for Phase.
+func (Phase) Enum() string { return "Phase" }
+
+// String returns the string representation of Phase v.
+func (v Phase) String() string { return _str_Phase[v._Phase] }
+
+// Valid reports whether v is a valid non-zero Phase value.
+func (v Phase) Valid() bool { return v._Phase > 0 && int(v._Phase) < len(_str_Phase) }
+
+// Enumerators returns the valid enumerators of Phase, in order.
+// The caller must not modify the result.
+// It satisfies the enum.Enum interface.
+func (Phase) Enumerators() []Phase { return _vals_Phase }
+
+// Index returns the integer index of Phase v.
+func (v Phase) Index() int { return int(v._Phase) }
+
+// newPhase returns the first enumerator of Phase whose string is a
+// case-insensitive match for s. If no enumerator matches, it returns the
+// zero enumerator.
+func newPhase(s string) Phase {
+	return enum.Lookup[Phase](s)
+}
+
+// ParsePhase returns the first enumerator of Phase whose string is a
+// case-insensitive match for s. If no enumerator matches, it reports an error.
+func ParsePhase(s string) (Phase, error) {
+	return enum.Parse[Phase](s)
+}
+
+// MustPhase returns the first enumerator of Phase whose string is a
+// case-insensitive match for s. If no enumerator matches, it panics.
+func MustPhase(s string) Phase {
+	return enum.Must[Phase](s)
+}
+
+// ProvidePhaseFromEnv returns the first enumerator of Phase whose string
+// is a case-insensitive match for the value of the ENUMGEN_TEST_PHASE environment
+// variable. If the variable is unset or empty, it returns the zero enumerator.
+// It reports an error if the value does not match any enumerator.
+// It is suitable for use as a provider with dependency injection frameworks
+// such as github.com/google/wire and go.uber.org/fx.
+func ProvidePhaseFromEnv() (Phase, error) {
+	s := os.Getenv("ENUMGEN_TEST_PHASE")
+	if s == "" {
+		return Phase{}, nil
+	}
+	if e := newPhase(s); e.Valid() {
+		return e, nil
+	}
+	return Phase{}, fmt.Errorf("invalid value for ENUMGEN_TEST_PHASE: %q", s)
+}
+
+var (
+	_str_Phase  = []string{"<invalid>", "Waxing", "Waning"}
+	_vals_Phase = []Phase{{1}, {2}}
+
+	Waxing = Phase{1}
+	Waning = Phase{2}
+)
+
+type Port struct{ _Port uint8 }
+
+// Enum returns the name of the enumeration type for Port.
+func (Port) Enum() string { return "Port" }
+
+// String returns the string representation of Port v.
+func (v Port) String() string { return _str_Port[v._Port] }
+
+// Valid reports whether v is a valid non-zero Port value.
+func (v Port) Valid() bool { return v._Port > 0 && int(v._Port) < len(_str_Port) }
+
+// Index returns the integer index of Port v.
+func (v Port) Index() int { return int(v._Port) }
+
+// Code returns the integer code of Port v, or 0 if v is not valid.
+func (v Port) Code() uint16 { return _code_Port[v._Port] }
+
+// PortFromCode returns the enumerator of Port whose code is c.
+// If no enumerator matches, it returns the zero enumerator.
+func PortFromCode(c uint16) Port { return _bycode_Port[c] }
+
+// Value returns the numeric value of Port v, which is its code.
+func (v Port) Value() uint16 { return _code_Port[v._Port] }
+
+// PortFromValue returns the enumerator of Port whose value is c.
+// It is equivalent to PortFromCode.
+func PortFromValue(c uint16) Port { return PortFromCode(c) }
+
+// The codes of the enumerators of Port, as returned by the Code and
+// Value methods.
+const (
+	HTTPCode  uint16 = 80
+	HTTPSCode uint16 = 443
+)
+
+var (
+	_str_Port    = []string{"<invalid>", "HTTP", "HTTPS"}
+	_code_Port   = []uint16{0, 80, 443}
+	_bycode_Port = map[uint16]Port{80: {1}, 443: {2}}
+
+	HTTP  = Port{1}
+	HTTPS = Port{2}
+)
+
+type Tide struct{ _Tide uint8 }
+
+// Enum returns the name of the enumeration type for Tide.
+func (Tide) Enum() string { return "Tide" }
+
+// String returns the string representation of Tide v.
+func (v Tide) String() string { return _str_Tide[v._Tide] }
+
+// Valid reports whether v is a valid non-zero Tide value.
+func (v Tide) Valid() bool { return v._Tide > 0 && int(v._Tide) < len(_str_Tide) }
+
+// Index returns the integer index of Tide v.
+func (v Tide) Index() int { return int(v._Tide) }
+
+// findTide returns the position of the enumerator of Tide whose string
+// is text, less one, and reports whether there is one.
+func findTide(text string) (int, bool) {
+	for i, opt := range _str_Tide[1:] {
+		if opt == text {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// AppendText appends the text encoding of the Tide enumerator to b.
+// It does not allocate if b has enough capacity.
+// It satisfies the encoding.TextAppender interface.
+func (v Tide) AppendText(b []byte) ([]byte, error) { return append(b, v.String()...), nil }
+
+// MarshalText encodes the value of the Tide enumerator as text.
+// It does not allocate. The result is a slice of a shared table, which the
+// caller must not modify.
+// It satisfies the encoding.TextMarshaler interface.
+func (v Tide) MarshalText() ([]byte, error) {
+	b := _text_Tide[v._Tide]
+	return b[:len(b):len(b)], nil
+}
+
+// UnarshalText decodes the value of the Tide enumerator from a string.
+// A string that does not encode a known enumerator decodes to the zero value.
+// An empty slice decodes to the zero value.
+// This method satisfies the encoding.TextUnmarshaler interface.
+func (v *Tide) UnmarshalText(data []byte) error {
+	*v = Tide{}
+	text := string(data)
+	if text == "" || text == _str_Tide[0] {
+		return nil
+	}
+	if i, ok := findTide(text); ok {
+		*v = Tide{uint8(i + 1)}
+		return nil
+	}
+	return nil
+}
+
+// MarshalXML encodes the value of the Tide enumerator as the text of an
+// XML element. It satisfies the xml.Marshaler interface.
+func (v Tide) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(v.String(), start)
+}
+
+// UnmarshalXML decodes the value of the Tide enumerator from the text of
+// an XML element, with the same rules as UnmarshalXMLAttr.
+// It satisfies the xml.Unmarshaler interface.
+func (v *Tide) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var text string
+	if err := d.DecodeElement(&text, &start); err != nil {
+		return err
+	}
+	return v.UnmarshalXMLAttr(xml.Attr{Name: start.Name, Value: text})
+}
+
+// MarshalXMLAttr encodes the value of the Tide enumerator as an XML
+// attribute. It satisfies the xml.MarshalerAttr interface.
+func (v Tide) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: v.String()}, nil
+}
+
+// UnmarshalXMLAttr decodes the value of the Tide enumerator from an XML
+// attribute. An empty value decodes to the zero value.
+// A string that does not encode a known enumerator decodes to the zero value.
+// It satisfies the xml.UnmarshalerAttr interface.
+func (v *Tide) UnmarshalXMLAttr(attr xml.Attr) error {
+	*v = Tide{}
+	if attr.Value == "" || attr.Value == _str_Tide[0] {
+		return nil
+	}
+	if i, ok := findTide(attr.Value); ok {
+		*v = Tide{uint8(i + 1)}
+		return nil
+	}
+	return nil
+}
+
+var (
+	_str_Tide  = []string{"<invalid>", "Ebb", "Flood"}
+	_text_Tide = [][]byte{[]byte("<invalid>"), []byte("Ebb"), []byte("Flood")}
+
+	Ebb   = Tide{1}
+	Flood = Tide{2}
+)
+
+type Grain struct{ _Grain *_entry_Grain }
+
+// Enum returns the name of the enumeration type for Grain.
+func (Grain) Enum() string { return "Grain" }
+
+// String returns the string representation of Grain v.
+func (v Grain) String() string {
+	if v._Grain == nil {
+		return _str_Grain[0]
+	}
+	return v._Grain.str
+}
+
+// Valid reports whether v is a valid non-zero Grain value.
+func (v Grain) Valid() bool { return v._Grain != nil && v._Grain.ord != 0 }
+
+// Index returns the integer index of Grain v.
+func (v Grain) Index() int { return int(v._Grain.ordinal()) }
+
+// findGrain returns the position of the enumerator of Grain whose string
+// is text, less one, and reports whether there is one.
+func findGrain(text string) (int, bool) {
+	for i, opt := range _str_Grain[1:] {
+		if opt == text {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// AppendText appends the text encoding of the Grain enumerator to b.
+// It does not allocate if b has enough capacity.
+// It satisfies the encoding.TextAppender interface.
+func (v Grain) AppendText(b []byte) ([]byte, error) { return append(b, v.String()...), nil }
+
+// MarshalText encodes the value of the Grain enumerator as text.
+// Except for an unknown string kept by UnmarshalText, it does not allocate.
+// The result is a slice of a shared table, which the caller must not modify.
+// It satisfies the encoding.TextMarshaler interface.
+func (v Grain) MarshalText() ([]byte, error) {
+	if v._Grain != nil && v._Grain.ord == 0 {
+		return []byte(v._Grain.str), nil
+	}
+	b := _text_Grain[v._Grain.ordinal()]
+	return b[:len(b):len(b)], nil
+}
+
+// UnarshalText decodes the value of the Grain enumerator from a string.
+// A string that does not encode a known enumerator decodes to a value that
+// is not valid, but retains the string.
+// An empty slice decodes to the zero value.
+// This method satisfies the encoding.TextUnmarshaler interface.
+func (v *Grain) UnmarshalText(data []byte) error {
+	*v = Grain{}
+	text := string(data)
+	if text == "" || text == _str_Grain[0] {
+		return nil
+	}
+	if i, ok := findGrain(text); ok {
+		*v = Grain{&_ents_Grain[i+1]}
+		return nil
+	}
+	*v = unknownGrain(text)
+	return nil
+}
+
+// MarshalXML encodes the value of the Grain enumerator as the text of an
+// XML element. It satisfies the xml.Marshaler interface.
+func (v Grain) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(v.String(), start)
+}
+
+// UnmarshalXML decodes the value of the Grain enumerator from the text of
+// an XML element, with the same rules as UnmarshalXMLAttr.
+// It satisfies the xml.Unmarshaler interface.
+func (v *Grain) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var text string
+	if err := d.DecodeElement(&text, &start); err != nil {
+		return err
+	}
+	return v.UnmarshalXMLAttr(xml.Attr{Name: start.Name, Value: text})
+}
+
+// MarshalXMLAttr encodes the value of the Grain enumerator as an XML
+// attribute. It satisfies the xml.MarshalerAttr interface.
+func (v Grain) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: v.String()}, nil
+}
+
+// UnmarshalXMLAttr decodes the value of the Grain enumerator from an XML
+// attribute. An empty value decodes to the zero value.
+// A string that does not encode a known enumerator decodes to a value that
+// is not valid, but retains the string.
+// It satisfies the xml.UnmarshalerAttr interface.
+func (v *Grain) UnmarshalXMLAttr(attr xml.Attr) error {
+	*v = Grain{}
+	if attr.Value == "" || attr.Value == _str_Grain[0] {
+		return nil
+	}
+	if i, ok := findGrain(attr.Value); ok {
+		*v = Grain{&_ents_Grain[i+1]}
+		return nil
+	}
+	*v = unknownGrain(attr.Value)
+	return nil
+}
+
+// _entry_Grain is an interned entry of the string table of Grain.
+// Each non-zero enumerator of Grain points to its own entry.
+type _entry_Grain struct {
+	str string
+	ord uint8
+}
+
+// ordinal returns the position of e in the string table, or 0 if e is nil.
+func (e *_entry_Grain) ordinal() uint8 {
+	if e == nil {
+		return 0
+	}
+	return e.ord
+}
+
+// unknownGrain returns a Grain that is not valid, but whose string is
+// text, for strings that do not encode a known enumerator. Values with the same
+// string are equal, since their entries are interned in
+// _unknown_Grain.
+func unknownGrain(text string) Grain {
+	_unknown_Grain.Lock()
+	defer _unknown_Grain.Unlock()
+	ent, ok := _unknown_Grain.ents[text]
+	if !ok {
+		if _unknown_Grain.ents == nil {
+			_unknown_Grain.ents = make(map[string]*_entry_Grain)
+		}
+		ent = &_entry_Grain{str: text}
+		_unknown_Grain.ents[text] = ent
+	}
+	return Grain{ent}
+}
+
+// _unknown_Grain holds the entries for the unknown strings of Grain.
+// Their ordinal is 0, so apart from their strings they behave as the zero value.
+var _unknown_Grain struct {
+	sync.Mutex
+	ents map[string]*_entry_Grain
+}
+var (
+	_str_Grain  = []string{"<invalid>", "Fine", "Coarse"}
+	_text_Grain = [][]byte{[]byte("<invalid>"), []byte("Fine"), []byte("Coarse")}
+	_ents_Grain = []_entry_Grain{{"<invalid>", 0}, {"Fine", 1}, {"Coarse", 2}}
+
+	Fine   = Grain{&_ents_Grain[1]}
+	Coarse = Grain{&_ents_Grain[2]}
+)
+
+type Alias struct{ _Alias uint8 }
+
+// Enum returns the name of the enumeration type for Alias.
+func (Alias) Enum() string { return "Alias" }
+
+// String returns the string representation of Alias v.
+func (v Alias) String() string { return _str_Alias[v._Alias] }
+
+// Valid reports whether v is a valid non-zero Alias value.
+func (v Alias) Valid() bool { return v._Alias > 0 && int(v._Alias) < len(_str_Alias) }
+
+// Index returns the integer index of Alias v.
+func (v Alias) Index() int { return int(v._Alias) }
+
+// Name returns the Go identifier of the Alias enumerator v, or "" if v
+// is not a named enumerator.
+func (v Alias) Name() string {
+	switch v {
+	case Gray:
+		return "Gray"
+	case Grey:
+		return "Grey"
+	}
+	return ""
+}
+
+// AliasByName returns the enumerator of Alias whose Go identifier is
+// name, or the zero enumerator if there is none. Unlike parsing, this does not
+// consider the strings of the enumerators.
+func AliasByName(name string) Alias {
+	switch name {
+	case "Gray":
+		return Gray
+	case "Grey":
+		return Grey
+	}
+	return Alias{}
+}
+
+// newAlias returns the first enumerator of Alias whose string is a
+// case-insensitive match for s. If no enumerator matches, it returns the
+// zero enumerator.
+func newAlias(s string) Alias {
+	for i, opt := range _str_Alias[1:] {
+		if strings.EqualFold(opt, s) {
+			return Alias{uint8(i + 1)}
+		}
+	}
+	return Alias{}
+}
+
+// findAlias returns the position of the enumerator of Alias whose string
+// is text, less one, and reports whether there is one.
+func findAlias(text string) (int, bool) {
+	for i, opt := range _str_Alias[1:] {
+		if opt == text {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// ParseAlias returns the first enumerator of Alias whose string is a
+// case-insensitive match for s. If no enumerator matches, it reports an error.
+func ParseAlias(s string) (Alias, error) {
+	if e := newAlias(s); e.Valid() {
+		return e, nil
+	}
+	return Alias{}, fmt.Errorf("invalid value for Alias: %q", s)
+}
+
+// AppendText appends the text encoding of the Alias enumerator to b.
+// It does not allocate if b has enough capacity.
+// It satisfies the encoding.TextAppender interface.
+func (v Alias) AppendText(b []byte) ([]byte, error) { return append(b, v.String()...), nil }
+
+// MarshalText encodes the value of the Alias enumerator as text.
+// It does not allocate. The result is a slice of a shared table, which the
+// caller must not modify.
+// It satisfies the encoding.TextMarshaler interface.
+func (v Alias) MarshalText() ([]byte, error) {
+	b := _text_Alias[v._Alias]
+	return b[:len(b):len(b)], nil
+}
+
+// UnarshalText decodes the value of the Alias enumerator from a string.
+// It reports an error if data does not encode a known enumerator.
+// An empty slice decodes to the zero value.
+// This method satisfies the encoding.TextUnmarshaler interface.
+func (v *Alias) UnmarshalText(data []byte) error {
+	*v = Alias{}
+	text := string(data)
+	if text == "" || text == _str_Alias[0] {
+		return nil
+	}
+	if i, ok := findAlias(text); ok {
+		*v = Alias{uint8(i + 1)}
+		return nil
+	}
+	return fmt.Errorf("invalid value for Alias: %q", text)
+}
+
+var (
+	_str_Alias  = []string{"<invalid>", "gray", "gray"}
+	_text_Alias = [][]byte{[]byte("<invalid>"), []byte("gray"), []byte("gray")}
+
+	Gray = Alias{1}
+	Grey = Alias{2}
+)
+
+// Quoted returns the string of v as a quoted Go string literal.
+func (v Alias) Quoted() string { return strconv.Quote(v.String()) }
+
+// Enums maps the name of each enumeration type generated in this file to the
+// strings of its valid enumerators, in order. It is intended for programs that
+// need to discover the available enumerations at runtime.
+var Enums = map[string][]string{
+	"E1":      {"alpha", "bravo", "C"},
+	"E2":      {"A", "B"},
+	"E3":      {"foo", "bar"},
+	"Count":   {"lonely", "tango"},
+	"Hashed":  {"H1", "H2"},
+	"Grouped": {"first", "second"},
+	"Status":  {"OK", "NotFound", "Teapot"},
+	"secret":  {"Hidden", "Private"},
+	"Sorted":  {"xigua", "yam", "zucchini"},
+	"Shade":   {"Light", "Dark"},
+	"Phase":   {"Waxing", "Waning"},
+	"Port":    {"HTTP", "HTTPS"},
+	"Tide":    {"Ebb", "Flood"},
+	"Grain":   {"Fine", "Coarse"},
+	"Alias":   {"gray", "gray"},
+}
+
+// EnumType is the interface satisfied by the enumeration types generated
+// in this file. Code that accepts any of these enumerations may use it.
+type EnumType interface {
+	Enum() string   // return the enumeration type name
+	Index() int     // return the integer index of the enumerator
+	String() string // return the string representation of an enumerator
+	Valid() bool    // report whether the receiver is a valid nonzero enumerator
+}
+
+// Check that the enumeration types satisfy EnumType.
+var (
+	_ EnumType = E1{}
+	_ EnumType = E2{}
+	_ EnumType = E3{}
+	_ EnumType = Count{}
+	_ EnumType = Hashed{}
+	_ EnumType = Grouped{}
+	_ EnumType = secret{}
+	_ EnumType = Sorted{}
+	_ EnumType = Shade{}
+	_ EnumType = Phase{}
+	_ EnumType = Port{}
+	_ EnumType = Tide{}
+	_ EnumType = Grain{}
+	_ EnumType = Alias{}
+)
+
+// InvalidEnumError is the error reported when a string does not match any
+// enumerator of a type.
+type InvalidEnumError struct {
+	Type  string // the name of the enumeration type
+	Input string // the string that did not match
+}
+
+// Error satisfies the error interface.
+func (e *InvalidEnumError) Error() string {
+	return fmt.Sprintf("invalid value for %s: %q", e.Type, e.Input)
+}
+
 // GeneratorHash is used by the tests to verify that the testdata
 // package is updated when the code generator changes.
-const GeneratorHash = "000bf82ce1799a01ca82260be52d9f145bf153ebb7a46a75b1baa3d8667415c9"
+const GeneratorHash = "18b000349887511d9cf288f6e664b44fb2d4baabedc6579a1580ef5e75662e25"
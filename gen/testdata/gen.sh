@@ -6,9 +6,10 @@
 # detect if the source was modified without updating the tests.
 #
 set -euo pipefail
+export LC_ALL=C
 
 readonly tool='github.com/creachadair/enumgen'
-readonly gen='../gen.go ../config.go'
+readonly gen="$(ls ../*.go | grep -v '_test\.go$') $(ls ../templates/*.tmpl)"
 readonly yaml='gentest.yml'
 readonly gofile='testdata.go'
 
@@ -18,8 +19,8 @@ else
     hash="$(cat $gen $yaml $gofile | sha256sum | cut -d' ' -f1)"
 fi
 
-rm -f -- enums.go gofile.go
-go run "$tool" -config "$yaml" -output enums.go
+rm -f -- enums.go enums_test.go enums_jsonv2.go gofile.go
+go run "$tool" -provenance -config "$yaml" -output enums.go
 go run "$tool" -config "$gofile" -output gofile.go
 echo "
 // GeneratorHash is used by the tests to verify that the testdata
@@ -1,8 +1,10 @@
 // Code generated by enumgen. DO NOT EDIT.
+//enumgen:provenance {"hash":"sha256:496dfaeae77826a89d6bf8006527e6c9dd0e34ce1cffd681262364b7dee382ab"}
 
 package testdata
 
 import (
+	"flag"
 	"fmt"
 	"strings"
 )
@@ -97,7 +99,7 @@ func NewColor(s string) Color {
 			return Color{uint8(i + 1)}
 		}
 	}
-	return Color{0}
+	return Color{}
 }
 
 // Set implements part of the flag.Value interface for Color.
@@ -110,6 +112,15 @@ func (v *Color) Set(s string) error {
 	return fmt.Errorf("invalid value for Color: %q", s)
 }
 
+// ColorFlag defines a Color flag in fs with the specified name, default
+// value, and usage string, and returns a pointer to the variable that stores
+// the value of the flag. The usage string is followed by the valid strings.
+func ColorFlag(fs *flag.FlagSet, name string, def Color, usage string) *Color {
+	v := def
+	fs.Var(&v, name, usage+" (one of: fire-engine-red, scummy-green, azure-sky-blue)")
+	return &v
+}
+
 // The names of the colours supported here.
 var (
 	_str_Color = []string{"<invalid>", "fire-engine-red", "scummy-green", "azure-sky-blue"}
@@ -35,6 +35,7 @@ values:
 // doc: |
 //   A Color is a source of joy for all who behold it.
 // flag-value: true
+// flag-func: true
 // constructor: true
 // val-doc: The names of the colours supported here.
 // values:
@@ -0,0 +1,104 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A TSStyle identifies a style of TypeScript definitions for GenerateTS.
+type TSStyle int
+
+// Constants defining the supported TypeScript styles.
+const (
+	// TSUnion defines each enumeration as a union of string literal types,
+	// with a constant array <Name>Values listing the strings in order:
+	//
+	//	export type Color = "red" | "green";
+	//	export const ColorValues: readonly Color[] = ["red", "green"];
+	TSUnion TSStyle = iota
+
+	// TSConst defines each enumeration as a constant object mapping the
+	// names of the enumerators to their strings, with a type of the same name
+	// denoting the union of the strings:
+	//
+	//	export const Color = {
+	//	  Red: "red",
+	//	  Green: "green",
+	//	} as const;
+	//	export type Color = (typeof Color)[keyof typeof Color];
+	TSConst
+)
+
+// String returns a human-readable name for s.
+func (s TSStyle) String() string {
+	switch s {
+	case TSUnion:
+		return "union"
+	case TSConst:
+		return "const"
+	default:
+		return fmt.Sprintf("TSStyle(%d)", s)
+	}
+}
+
+// TSOptions are options for GenerateTS. A zero value is ready for use.
+type TSOptions struct {
+	Style TSStyle // the style of the definitions (default TSUnion)
+}
+
+// GenerateTS writes TypeScript definitions for the enumerations of c to w, so
+// that a web frontend can share the enumerations with Go code. The values of
+// each enumeration are the strings of its valid enumerators, which are the
+// strings used by the generated text and JSON encodings. Doc comments of the
+// enumerations and enumerators are copied to the output.
+func (c *Config) GenerateTS(w io.Writer, opts TSOptions) error {
+	if opts.Style != TSUnion && opts.Style != TSConst {
+		return fmt.Errorf("unknown TypeScript style %v", opts.Style)
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "// Code generated by enumgen. DO NOT EDIT.\n")
+	for _, e := range c.Enum {
+		ed := e.enumData()
+		sb.WriteString("\n")
+		if ed.Comment != "" {
+			sb.WriteString(ed.Comment + "\n")
+		}
+		switch opts.Style {
+		case TSUnion:
+			strs := make([]string, len(ed.Enumerators))
+			for i, v := range ed.Enumerators {
+				strs[i] = tsString(v.Label)
+			}
+			fmt.Fprintf(&sb, "export type %s = %s;\n", e.Type, strings.Join(strs, " | "))
+			fmt.Fprintf(&sb, "export const %[1]sValues: readonly %[1]s[] = [%[2]s];\n",
+				e.Type, strings.Join(strs, ", "))
+		case TSConst:
+			fmt.Fprintf(&sb, "export const %s = {\n", e.Type)
+			for _, v := range ed.Enumerators {
+				if v.Comment != "" {
+					sb.WriteString("  " + strings.ReplaceAll(v.Comment, "\n", "\n  ") + "\n")
+				}
+				fmt.Fprintf(&sb, "  %s: %s,\n", v.Value.Name, tsString(v.Label))
+			}
+			sb.WriteString("} as const;\n")
+			fmt.Fprintf(&sb, "export type %[1]s = (typeof %[1]s)[keyof typeof %[1]s];\n", e.Type)
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// tsString returns s as a TypeScript string literal.
+func tsString(s string) string {
+	var sb strings.Builder
+	enc := json.NewEncoder(&sb)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s) // cannot fail
+	return strings.TrimSuffix(sb.String(), "\n")
+}
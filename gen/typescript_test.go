@@ -0,0 +1,69 @@
+package gen_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestGenerateTS(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{{
+			Type:   "Color",
+			Doc:    "{name} is a color.",
+			Zero:   "None",
+			Values: []*gen.Value{{Name: "Red", Text: "red", Doc: "Like a rose."}, {Name: "None"}, {Name: "Blue", Text: `"blue" & <true>`}},
+		}, {
+			Type:   "Mood",
+			Prefix: "M",
+			Values: []*gen.Value{{Name: "Happy"}},
+		}},
+	}
+
+	tests := []struct {
+		style gen.TSStyle
+		want  string
+	}{
+		{gen.TSUnion, `// Code generated by enumgen. DO NOT EDIT.
+
+// Color is a color.
+export type Color = "red" | "\"blue\" & <true>";
+export const ColorValues: readonly Color[] = ["red", "\"blue\" & <true>"];
+
+export type Mood = "Happy";
+export const MoodValues: readonly Mood[] = ["Happy"];
+`},
+		{gen.TSConst, `// Code generated by enumgen. DO NOT EDIT.
+
+// Color is a color.
+export const Color = {
+  // Like a rose.
+  Red: "red",
+  Blue: "\"blue\" & <true>",
+} as const;
+export type Color = (typeof Color)[keyof typeof Color];
+
+export const Mood = {
+  Happy: "Happy",
+} as const;
+export type Mood = (typeof Mood)[keyof typeof Mood];
+`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.style.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := cfg.GenerateTS(&buf, gen.TSOptions{Style: tc.style}); err != nil {
+				t.Fatalf("GenerateTS: unexpected error: %v", err)
+			}
+			if got := buf.String(); got != tc.want {
+				t.Errorf("GenerateTS: got\n%s\nwant\n%s", got, tc.want)
+			}
+		})
+	}
+
+	if err := cfg.GenerateTS(&bytes.Buffer{}, gen.TSOptions{Style: 5}); err == nil {
+		t.Error("GenerateTS with an unknown style did not report an error")
+	}
+}
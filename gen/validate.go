@@ -0,0 +1,818 @@
+package gen
+
+import (
+	"cmp"
+	"fmt"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/creachadair/mds/mapset"
+)
+
+// A ValidationError describes a single problem with a Config, as reported by
+// the Validate method.
+type ValidationError struct {
+	// The 1-based position of the package in the packages of the config, or
+	// 0 if the problem is in the top-level config.
+	Package int
+
+	// The 1-based position of the enumeration in the config, or 0 if the
+	// problem applies to the config as a whole.
+	Enum int
+
+	// The type name of the enumeration, if it is known to be valid.
+	Type string
+
+	// The position of the enumeration in the file it was read from, if
+	// known: the start of its entry in a YAML file, or of the comment that
+	// defines it in a Go source file. If the file name is known, the error
+	// message begins with the position.
+	Pos token.Position
+
+	// The 1-based position of the enumerator in the values of the
+	// enumeration, or 0 if the problem applies to the enumeration as a whole.
+	Value int
+
+	// The name of the config field at fault, as spelled in YAML.
+	Field string
+
+	// A description of the problem.
+	Message string
+}
+
+// Error satisfies the error interface. The message identifies the position of
+// the problem in the config.
+func (v *ValidationError) Error() string {
+	var sb strings.Builder
+	if v.Pos.IsValid() && v.Pos.Filename != "" {
+		fmt.Fprintf(&sb, "%s: ", v.Pos)
+	}
+	if v.Package > 0 {
+		fmt.Fprintf(&sb, "package %d: ", v.Package)
+	}
+	if v.Enum == 0 {
+		sb.WriteString(v.Message)
+		return sb.String()
+	}
+	if v.Type != "" {
+		fmt.Fprintf(&sb, "enum %q", v.Type)
+	} else {
+		fmt.Fprintf(&sb, "enum %d", v.Enum)
+	}
+	if v.Value > 0 {
+		fmt.Fprintf(&sb, " value %d", v.Value)
+	}
+	sb.WriteString(": ")
+	sb.WriteString(v.Message)
+	return sb.String()
+}
+
+// ValidationErrors is the concrete type of a non-nil error reported by the
+// Validate method. It contains one entry for each problem found.
+type ValidationErrors []*ValidationError
+
+// Error satisfies the error interface. The message contains the messages of
+// the problems found, one per line.
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, e := range v {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the individual errors of v, for use with errors.Is and
+// errors.As.
+func (v ValidationErrors) Unwrap() []error {
+	out := make([]error, len(v))
+	for i, e := range v {
+		out[i] = e
+	}
+	return out
+}
+
+// Validate checks whether c is a valid configuration. If not, it reports an
+// error of concrete type [ValidationErrors] describing every problem found,
+// rather than only the first.
+//
+// If c lists other packages, their configs are also checked. In that case,
+// the top-level package name and enumerations may be omitted.
+func (c *Config) Validate() error {
+	var errs ValidationErrors
+	if len(c.Packages) == 0 || c.Package != "" || len(c.Enum) != 0 {
+		errs = c.validate(0)
+	}
+	for i, p := range c.Packages {
+		if p.Output == "" {
+			errs = append(errs, &ValidationError{
+				Package: i + 1, Field: "output", Message: "output path not defined",
+			})
+		}
+		if len(p.Packages) != 0 {
+			errs = append(errs, &ValidationError{
+				Package: i + 1, Field: "packages", Message: "nested packages are not supported",
+			})
+		}
+		pc := p.Config
+		if pc.NamePolicy == nil {
+			pc.NamePolicy = c.NamePolicy // inherited, as by PackageConfigs
+		}
+		errs = append(errs, pc.validate(i+1)...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// validate reports the problems with the enumerations of c, attributing them
+// to the specified package position.
+func (c *Config) validate(pkg int) ValidationErrors {
+	var errs ValidationErrors
+	report := func(pos ValidationError, msg string, args ...any) {
+		pos.Package = pkg
+		if pos.Enum > 0 && pos.Enum <= len(c.Enum) {
+			pos.Pos = c.Enum[pos.Enum-1].pos
+		}
+		pos.Message = fmt.Sprintf(msg, args...)
+		errs = append(errs, &pos)
+	}
+
+	if c.Package == "" {
+		report(ValidationError{Field: "package"}, "package name not defined")
+	}
+	if c.MapThreshold < 0 {
+		report(ValidationError{Field: "map-threshold"}, "invalid map threshold %d", c.MapThreshold)
+	}
+	if c.InterfaceName != "" && !c.EmitInterface {
+		report(ValidationError{Field: "interface-name"}, "interface-name requires emit-interface")
+	} else if name := c.interfaceName(); c.EmitInterface {
+		if !token.IsIdentifier(name) {
+			report(ValidationError{Field: "interface-name"}, "invalid interface name %q", name)
+		} else if slices.ContainsFunc(c.Enum, func(e *Enum) bool { return e.goType() == name }) {
+			report(ValidationError{Field: "interface-name"}, "interface %q conflicts with enumeration type", name)
+		}
+	}
+	if c.Registry && slices.ContainsFunc(c.Enum, func(e *Enum) bool { return e.goType() == "Enums" }) {
+		report(ValidationError{Field: "registry"}, `registry conflicts with enumeration type "Enums"`)
+	}
+	if c.BuildTags != "" {
+		if _, err := constraint.Parse("//go:build " + c.BuildTags); err != nil {
+			report(ValidationError{Field: "build-tags"}, "invalid build tags: %v", err)
+		}
+	}
+	if len(c.Include) != 0 {
+		report(ValidationError{Field: "include"}, "includes are not resolved")
+	}
+	if c.NamePolicy != nil {
+		if _, err := c.NamePolicy.compile(); err != nil {
+			report(ValidationError{Field: "name-policy"}, "%v", err)
+		}
+	}
+	if len(c.Enum) == 0 {
+		report(ValidationError{Field: "enum"}, "no enumerations defined")
+	}
+	var imp importTracker
+	generated := c.generatedNames()
+	checkVar := func(pos ValidationError, e *Enum, name string) {
+		switch full := e.valueName(name); {
+		case !token.IsIdentifier(full) || full == "_":
+			report(pos, "invalid variable name %q for %q", full, name)
+		case e.Unexported && token.IsExported(full):
+			report(pos, "invalid unexported variable name %q for %q", full, name)
+		case types.Universe.Lookup(full) != nil:
+			report(pos, "variable name %q for %q shadows a predeclared identifier", full, name)
+		case generated[full] != "":
+			report(pos, "variable name %q for %q conflicts with %s", full, name, generated[full])
+		}
+	}
+	enumSeen := mapset.New[string]()
+	valueSeen := make(map[string]string)
+	for i, e := range c.Enum {
+		at := func(field string) ValidationError {
+			return ValidationError{Enum: i + 1, Type: e.Type, Field: field}
+		}
+		if e.Type == "" {
+			report(ValidationError{Enum: i + 1, Field: "type"}, "type name not defined")
+		} else if enumSeen.Has(e.goType()) {
+			report(ValidationError{Enum: i + 1, Field: "type"}, "duplicate type name %q", e.goType())
+		} else if name := e.goType(); e.Unexported && (!token.IsIdentifier(name) || token.IsExported(name)) {
+			report(at("unexported"), "invalid unexported type name %q", name)
+		} else if !token.IsIdentifier(name) || name == "_" {
+			report(at("type"), "invalid type name %q", name)
+		} else if types.Universe.Lookup(name) != nil {
+			report(at("type"), "type name %q shadows a predeclared identifier", name)
+		}
+		enumSeen.Add(e.goType())
+		if len(e.Values) == 0 {
+			report(ValidationError{Enum: i + 1, Field: "values"}, "no enumerators defined")
+		}
+		if e.FixedWidth != "" {
+			bits, ok := fixedWidths[e.FixedWidth]
+			_, rest := e.extractZero()
+			if !ok {
+				report(at("fixed-width"), "unknown fixed-width type %q", e.FixedWidth)
+			} else if bits < 64 && uint64(len(rest)) >= 1<<bits {
+				report(at("fixed-width"), "fixed-width %s cannot represent %d enumerators", e.FixedWidth, len(rest))
+			} else {
+				for j, v := range e.Values {
+					if v.Index != nil && (*v.Index < 0 || bits < 64 && uint64(*v.Index) >= 1<<bits) {
+						pos := at("index")
+						pos.Value = j + 1
+						report(pos, "index %d of %q does not fit in fixed-width %s", *v.Index, v.Name, e.FixedWidth)
+					}
+				}
+			}
+		}
+		if e.hasCodes() || e.CodeType != "" {
+			validateCodes(e, report, at)
+		}
+		if len(e.Attrs) != 0 || slices.ContainsFunc(e.Values, func(v *Value) bool { return len(v.Attrs) != 0 }) {
+			validateAttrs(e, report, at)
+		}
+		if len(e.Methods) != 0 {
+			validateMethods(e, report, at)
+		}
+		if e.ValuesFrom != "" {
+			report(at("values-from"), "values-from is not resolved")
+		}
+		if e.Base != "" {
+			report(at("base"), "base is not expanded")
+		}
+		if e.DocFile != "" {
+			if e.Doc != "" {
+				report(at("doc-file"), "doc-file conflicts with doc")
+			} else {
+				report(at("doc-file"), "doc-file is not resolved")
+			}
+		}
+		if !slices.Contains(nameStyles, e.NameStyle) && !isNameTemplate(e.NameStyle) {
+			report(at("name-style"), "unknown name style %q", e.NameStyle)
+		}
+		if p := e.NamePolicy; p != nil {
+			if _, err := p.compile(); err != nil {
+				report(at("name-policy"), "%v", err)
+			}
+		}
+		if p := cmp.Or(e.NamePolicy, c.NamePolicy); p != nil {
+			validateNamePolicy(e, p, report, at)
+		}
+		switch e.Representation {
+		case "", "index":
+		case "pointer":
+			if e.FixedWidth != "" {
+				report(at("representation"), "fixed-width conflicts with pointer representation")
+			}
+		default:
+			report(at("representation"), "unknown representation %q", e.Representation)
+		}
+		switch e.StringTable {
+		case "", "slice", "packed":
+		default:
+			report(at("string-table"), "unknown string table %q", e.StringTable)
+		}
+		switch e.Naming {
+		case "", "default", "hashed", "grouped":
+		default:
+			report(at("naming"), "unknown naming scheme %q", e.Naming)
+		}
+		switch e.Sort {
+		case "", "none", "name", "text", "index":
+		default:
+			report(at("sort"), "unknown sort order %q", e.Sort)
+		}
+		switch e.UnmarshalUnknown {
+		case "", "error":
+		case "zero", "keep-text":
+			if !e.TextMarshal && !e.XML && !e.JSONv2 {
+				report(at("unmarshal-unknown"), "unmarshal-unknown requires text-marshal, xml, or json-v2")
+			}
+			if e.UnmarshalUnknown == "keep-text" && e.Representation != "pointer" {
+				report(at("unmarshal-unknown"), "unmarshal-unknown keep-text requires the pointer representation")
+			}
+		default:
+			report(at("unmarshal-unknown"), "unknown unmarshal-unknown policy %q", e.UnmarshalUnknown)
+		}
+		if e.ListUnique && !e.ParseList {
+			report(at("list-unique"), "list-unique requires parse-list")
+		}
+		if zero, _ := e.extractZero(); zero != nil {
+			if zero.Text != "" && e.InvalidText != "" {
+				report(at("invalid-text"), "invalid-text conflicts with text of zero enumerator %q", zero.Name)
+			}
+			if zero.Index != nil && *zero.Index != e.indexBase()-1 {
+				report(at("index"), "cannot override index of zero enumerator %q", zero.Name)
+			}
+		}
+		if e.Examples && e.Unexported {
+			report(at("examples"), "examples requires an exported type")
+		}
+		if e.QuickCheck && e.parseFunc() == "" && !e.TextMarshal && !e.FlagValue && !e.Constructors.Parse {
+			report(at("quickcheck"), "quickcheck requires a parsing function")
+		}
+		for _, old := range slices.Sorted(maps.Keys(e.Migrate)) {
+			if name := e.Migrate[old]; old < e.indexBase() {
+				report(at("migrate"), "invalid former index %d", old)
+			} else if name == e.Zero || !e.hasValue(name) {
+				report(at("migrate"), "former index %d maps to unknown enumerator %q", old, name)
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(e.Groups)) {
+			if !token.IsIdentifier(name) || !token.IsExported(name) {
+				report(at("groups"), "invalid group name %q", name)
+			} else if len(e.Groups[name]) == 0 {
+				report(at("groups"), "group %q is empty", name)
+			}
+			var seen mapset.Set[string]
+			for _, m := range e.Groups[name] {
+				if m == e.Zero || !e.hasValue(m) {
+					report(at("groups"), "group %q has unknown enumerator %q", name, m)
+				} else if seen.Has(m) {
+					report(at("groups"), "group %q has duplicate enumerator %q", name, m)
+				}
+				seen.Add(m)
+			}
+		}
+		if e.Predicates {
+			validatePredicates(e, report, at)
+		}
+		if b := e.IndexBase; b != nil && *b != 0 && *b != 1 {
+			report(at("index-base"), "index-base must be 0 or 1, not %d", *b)
+		}
+		switch e.ParseError {
+		case "":
+		case "sentinel", "struct":
+			if e.Runtime {
+				report(at("parse-error"), "parse-error conflicts with runtime")
+			} else if !e.Constructors.Parse && !e.FlagValue && !e.TextMarshal && !e.ParseList && !e.XML && !e.JSONv2 && !e.SQL && !e.GORM {
+				report(at("parse-error"), "parse-error requires a function that reports parse errors")
+			}
+		default:
+			report(at("parse-error"), "unknown parse-error kind %q", e.ParseError)
+		}
+		if e.Receiver != "" {
+			validateReceiver(e, report, at)
+		}
+		if e.ByName {
+			for _, key := range slices.Sorted(maps.Keys(methodUsers)) {
+				if e.methodName(key) == "Name" {
+					report(at("by-name"), "by-name conflicts with method %q", key)
+				}
+			}
+			for _, name := range slices.Sorted(maps.Keys(e.Attrs)) {
+				if attrMethod(name) == "Name" {
+					report(at("by-name"), "by-name conflicts with attribute %q", name)
+				}
+			}
+		}
+		if e.FlagFunc && !e.FlagValue {
+			report(at("flag-func"), "flag-func requires flag-value")
+		}
+		if e.FlagFunc && e.methodName("string") != "String" {
+			report(at("flag-func"), "flag-func requires a method named String")
+		}
+		if e.Query && !e.TextMarshal {
+			report(at("query"), "query requires text-marshal")
+		}
+		if e.CBORCode && !e.CBOR {
+			report(at("cbor-code"), "cbor-code requires cbor")
+		} else if e.CBORCode && !e.hasCodes() {
+			report(at("cbor-code"), "cbor-code requires enumerator codes")
+		}
+		if e.LogGroup && !e.LogValue {
+			report(at("log-group"), "log-group requires log-value")
+		}
+		if e.DBType != "" && !e.GORM {
+			report(at("db-type"), "db-type requires gorm")
+		}
+		for _, spec := range e.ExtraImports {
+			if _, _, ok := parseImport(spec); !ok {
+				report(at("extra-imports"), "invalid import %q", spec)
+			}
+		}
+		if e.ExtraCode != "" {
+			// Parse the code as the body of a file, on the line of the package
+			// clause so that the line numbers of errors match the code.
+			src := "package p; " + e.ExtraCode
+			if _, err := parser.ParseFile(token.NewFileSet(), "extra-code", src, parser.SkipObjectResolution); err != nil {
+				report(at("extra-code"), "invalid Go declarations: %v", err)
+			}
+		}
+		if e.DisplayDefault != "" && !e.hasDisplay() {
+			report(at("display-default"), "display-default requires display names")
+		}
+		if e.CodeConsts && !e.hasCodes() {
+			report(at("code-consts"), "code-consts requires enumerator codes")
+		}
+		if e.CodeConsts && (e.SQL || e.GORM) {
+			report(at("code-consts"), "code-consts conflicts with sql, which also defines a Value method")
+		}
+		if e.Lazy && !e.hasCodes() && !c.mapLookup(e) {
+			report(at("lazy"), "lazy requires enumerator codes or lookup maps")
+		}
+		if p := e.Providers; p != nil {
+			if p.Env == "" && p.Default == "" {
+				report(at("providers"), "providers must set env or default")
+			} else if p.Default != "" && !e.hasValue(p.Default) {
+				report(at("providers"), "default provider %q is not an enumerator", p.Default)
+			}
+		}
+		if p := e.Proto; p != nil {
+			if p.Type == "" {
+				report(at("proto"), "proto type not defined")
+			} else if p.Import != "" && p.qualifier() == "" {
+				report(at("proto"), "proto type %q must have a package qualifier", p.Type)
+			}
+			for _, name := range slices.Sorted(maps.Keys(p.Values)) {
+				if !e.hasValue(name) {
+					report(at("proto"), "proto value %q is not an enumerator", name)
+				}
+			}
+			_, rest := e.extractZero()
+			protoSeen := make(map[string]string) // proto constant → enumerator
+			for _, v := range rest {
+				c, _ := p.constant(v.Name, true)
+				if old, ok := protoSeen[c]; ok {
+					report(at("proto"), "proto constant %s of %q duplicates %q", c, v.Name, old)
+				} else {
+					protoSeen[c] = v.Name
+				}
+			}
+		}
+		if imp.err == nil {
+			if e.addImports(&imp); imp.err != nil {
+				report(at("proto"), "%v", imp.err)
+			}
+		}
+		if zero := e.valueName(e.Zero); e.Zero != "" {
+			if valueSeen[zero] != "" && valueSeen[zero] != e.Type {
+				report(at("zero"), "default %q duplicated in %q", zero, valueSeen[zero])
+			}
+			valueSeen[zero] = e.Type
+		}
+
+		// It is OK for the zero enumerator to be duplicated in its own value
+		// list, but other names must not be duplicated within the list. This map
+		// keeps track of just the names in this group to prevent that.
+
+		var thisName mapset.Set[string]
+
+		// Unless duplicates are allowed, the strings of the enumerators must be
+		// distinct, so that parsing a string is not ambiguous. This map records
+		// the enumerator that first has each string.
+		zeroVal, _ := e.extractZero()
+		textSeen := map[string]string{e.zeroLabel(zeroVal): "the zero value"}
+		for j, v := range e.Values {
+			pos := at("name")
+			pos.Value = j + 1
+			if v.Name == "" {
+				report(pos, "name not defined")
+				continue
+			} else if thisName.Has(v.Name) {
+				report(pos, "name %q duplicated in %q", v.Name, e.Type)
+				continue
+			}
+			thisName.Add(v.Name)
+			if v.Name != e.Zero && !e.AllowDuplicateText {
+				tpos := pos
+				tpos.Field = "text"
+				if prev, ok := textSeen[v.label()]; ok {
+					report(tpos, "text %q of %q duplicates %s (see allow-duplicate-text)", v.label(), v.Name, prev)
+				} else {
+					textSeen[v.label()] = fmt.Sprintf("%q", v.Name)
+				}
+			}
+			checkVar(pos, e, v.Name)
+			if v.When != "" {
+				wpos := pos
+				wpos.Field = "when"
+				if _, err := parseWhen(v.When); err != nil {
+					report(wpos, "%v", err)
+				} else if v.Name == e.Zero {
+					report(wpos, "zero enumerator %q cannot have a when condition", v.Name)
+				} else {
+					report(wpos, "when is not resolved")
+				}
+			}
+			if v.GRPCCode != "" && !slices.Contains(grpcCodes, v.GRPCCode) {
+				gpos := pos
+				gpos.Field = "grpc-code"
+				report(gpos, "unknown gRPC code %q for %q", v.GRPCCode, v.Name)
+			}
+			if _, ok := v.Display[""]; ok {
+				dpos := pos
+				dpos.Field = "display"
+				report(dpos, "empty display language for %q", v.Name)
+			}
+			if v.DocFile != "" {
+				dpos := pos
+				dpos.Field = "doc-file"
+				if v.Doc != "" {
+					report(dpos, "doc-file conflicts with doc")
+				} else {
+					report(dpos, "doc-file is not resolved")
+				}
+			}
+
+			full := e.valueName(v.Name)
+			if valueSeen[full] != "" {
+				// If this enumerator is "my" zero value, it's OK to repeat it in
+				// the values list to provide text and documentation.
+				if valueSeen[full] != e.Type || e.Zero == "" || e.Zero != v.Name {
+					report(pos, "name %q duplicated in %q", full, valueSeen[full])
+				}
+			}
+			valueSeen[full] = e.Type
+		}
+		if e.Zero != "" && !slices.ContainsFunc(e.Values, func(v *Value) bool { return v.Name == e.Zero }) {
+			checkVar(at("zero"), e, e.Zero)
+		}
+	}
+	return errs
+}
+
+// generatedNames returns the names of the package-level symbols, other than
+// the enumerators, that the generated code for c may declare, mapped to a
+// description of their origin. The names of each enumeration are included
+// whether or not the options that generate them are enabled, so that enabling
+// an option does not invalidate the names of existing enumerators.
+func (c *Config) generatedNames() map[string]string {
+	names := make(map[string]string)
+	if c.Registry {
+		names["Enums"] = "the registry"
+	}
+	if c.EmitInterface {
+		names[c.interfaceName()] = "the interface"
+	}
+	if slices.ContainsFunc(c.Enum, func(e *Enum) bool { return e.ParseError == "struct" }) {
+		names["InvalidEnumError"] = "the InvalidEnumError type"
+	}
+	for _, e := range c.Enum {
+		if e.Type == "" {
+			continue // reported separately
+		}
+		typ := e.goType()
+		what := fmt.Sprintf("a name generated for %q", e.Type)
+		for _, s := range generatedSuffixes {
+			names[typ+s] = what
+		}
+		for group := range e.Groups {
+			names[typ+group] = what
+		}
+		for _, p := range generatedPrefixes {
+			names[e.ident(p)] = what
+		}
+		names[e.ident("Provide")+"FromEnv"] = what
+		names[e.ident("MigrateOld")+"Index"] = what
+		names[e.ident("Parse")+"List"] = what
+		for _, v := range e.Values {
+			if e.CodeConsts && v.Code != nil {
+				names[e.valueName(v.Name)+"Code"] = fmt.Sprintf("the code constant of %q", v.Name)
+			}
+		}
+		if e.CodeConsts {
+			names[typ+"FromValue"] = what
+		}
+		for _, kind := range generatedTables {
+			if name := e.tableName(kind); token.IsIdentifier(name) {
+				names[name] = what // grouped tables are fields of one variable
+			}
+		}
+		for _, kind := range []string{"ctxkey", "entry", "ents", "unknown"} {
+			names[e.typeName(kind)] = what
+		}
+		names[groupName(e.Type)] = what
+		names[typ] = fmt.Sprintf("enumeration type %q", typ)
+	}
+	return names
+}
+
+var (
+	// generatedSuffixes are the words appended to the type name of an
+	// enumeration to name the package-level symbols generated for it.
+	generatedSuffixes = []string{
+		"ByName", "Choice", "Choices", "Completions", "Deprecations", "Docs",
+		"Fingerprint", "Flag", "FromCode", "FromContext", "FromIndex",
+		"FromProto", "FromQuery", "JSONSchemaFragment", "Names", "Strings",
+	}
+
+	// generatedPrefixes are the words prefixed to the type name of an
+	// enumeration by Enum.ident to name the package-level symbols generated
+	// for it.
+	generatedPrefixes = []string{
+		"ErrInvalid", "Must", "New", "Num", "Parse", "ProvideDefault", "With",
+		"appendCBOR", "find", "invalid", "new", "readCBOR", "unknown",
+	}
+
+	// generatedTables are the kinds of the tables generated for an
+	// enumeration, as named by Enum.tableName.
+	generatedTables = []string{
+		"str", "idx", "vals", "code", "bycode", "display", "bystr", "byfold", "stroff",
+		"text",
+	}
+)
+
+// namePolicyStyles lists the supported styles of a NamePolicy.
+var namePolicyStyles = []string{"", "go-identifier", "mixed-caps", "go-exported"}
+
+// compile checks the settings of p, and returns its compiled pattern, or nil
+// if it has none.
+func (p *NamePolicy) compile() (*regexp.Regexp, error) {
+	if !slices.Contains(namePolicyStyles, p.Style) {
+		return nil, fmt.Errorf("unknown name-policy style %q", p.Style)
+	} else if p.Pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(`^(?:` + p.Pattern + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name-policy pattern: %w", err)
+	}
+	return re, nil
+}
+
+// validateNamePolicy reports the enumerators of e, including a zero
+// enumerator that is not listed in its values, whose names violate p. If p
+// itself is invalid, it reports nothing, since that is reported separately.
+func validateNamePolicy(e *Enum, p *NamePolicy, report func(ValidationError, string, ...any), at func(string) ValidationError) {
+	re, err := p.compile()
+	if err != nil {
+		return
+	}
+	check := func(pos ValidationError, name string) {
+		if slices.ContainsFunc(p.Reserved, func(r string) bool { return strings.EqualFold(r, name) }) {
+			report(pos, "name %q is reserved by name-policy", name)
+		}
+		if re != nil && !re.MatchString(name) {
+			report(pos, "name %q does not match name-policy pattern %q", name, p.Pattern)
+		}
+		if p.Style == "" {
+			return
+		}
+		switch full := e.valueName(name); {
+		case !token.IsIdentifier(full) || types.Universe.Lookup(full) != nil:
+			// Reported for every enumerator, regardless of the policy.
+		case p.Style != "go-identifier" && strings.Contains(full, "_"):
+			report(pos, "variable name %q for %q contains an underscore", full, name)
+		case p.Style == "go-exported" && !token.IsExported(full):
+			report(pos, "variable name %q for %q is not exported", full, name)
+		}
+	}
+	for j, v := range e.Values {
+		if v.Name == "" {
+			continue // reported separately
+		}
+		pos := at("name")
+		pos.Value = j + 1
+		check(pos, v.Name)
+	}
+	if e.Zero != "" && !slices.ContainsFunc(e.Values, func(v *Value) bool { return v.Name == e.Zero }) {
+		check(at("zero"), e.Zero)
+	}
+}
+
+// grpcCodes lists the names of the gRPC status codes.
+var grpcCodes = []string{
+	"OK", "Canceled", "Unknown", "InvalidArgument", "DeadlineExceeded",
+	"NotFound", "AlreadyExists", "PermissionDenied", "ResourceExhausted",
+	"FailedPrecondition", "Aborted", "OutOfRange", "Unimplemented",
+	"Internal", "Unavailable", "DataLoss", "Unauthenticated",
+}
+
+// codeRanges maps the permitted code types to the ranges of their values.
+var codeRanges = map[string][2]int64{
+	"int": {math.MinInt64, math.MaxInt64}, "int8": {math.MinInt8, math.MaxInt8},
+	"int16": {math.MinInt16, math.MaxInt16}, "int32": {math.MinInt32, math.MaxInt32},
+	"int64": {math.MinInt64, math.MaxInt64}, "uint": {0, math.MaxInt64},
+	"uint8": {0, math.MaxUint8}, "uint16": {0, math.MaxUint16},
+	"uint32": {0, math.MaxUint32}, "uint64": {0, math.MaxInt64},
+}
+
+// validateCodes reports problems with the codes of the enumerators of e.
+func validateCodes(e *Enum, report func(ValidationError, string, ...any), at func(string) ValidationError) {
+	ctype := cmp.Or(e.CodeType, "int")
+	rng, known := codeRanges[ctype]
+	if !known {
+		report(at("code-type"), "unknown code type %q", ctype)
+	}
+	if !e.hasCodes() {
+		report(at("code-type"), "code-type requires enumerator codes")
+		return
+	}
+	seen := make(map[int]string)
+	for j, v := range e.Values {
+		pos := at("code")
+		pos.Value = j + 1
+		if v.Name == e.Zero {
+			if v.Code != nil {
+				report(pos, "zero enumerator %q cannot have a code", v.Name)
+			}
+			continue
+		} else if v.Code == nil {
+			report(pos, "code not defined for %q", v.Name)
+			continue
+		}
+		c := *v.Code
+		if old, ok := seen[c]; ok {
+			report(pos, "code %d of %q duplicates %q", c, v.Name, old)
+		} else {
+			seen[c] = v.Name
+		}
+		if known && (int64(c) < rng[0] || int64(c) > rng[1]) {
+			report(pos, "code %d of %q overflows %s", c, v.Name, ctype)
+		}
+	}
+}
+
+// fixedWidths maps the permitted fixed-width types to their sizes in bits.
+var fixedWidths = map[string]int{"uint8": 8, "uint16": 16, "uint32": 32, "uint64": 64}
+
+// hasValue reports whether name is the name of an enumerator of e, including
+// the zero enumerator.
+func (e *Enum) hasValue(name string) bool {
+	return name == e.Zero || slices.ContainsFunc(e.Values, func(v *Value) bool { return v.Name == name })
+}
+
+// receiverConflicts lists the names of the parameters, local variables, and
+// packages that the optional generated methods refer to, which a receiver must
+// not shadow, with the options that enable those methods. The methods that
+// every enumeration has refer to none.
+var receiverConflicts = []struct {
+	uses  func(*Enum) bool
+	names []string
+}{
+	{func(e *Enum) bool { return e.TextMarshal }, []string{"b", "data", "enum", "err", "fmt", "i", "ok", "opt", "text"}},
+	{func(e *Enum) bool { return e.FlagValue }, []string{"e", "enum", "err", "fmt", "s"}},
+	{func(e *Enum) bool { return e.XML }, []string{"attr", "d", "e", "err", "fmt", "i", "name", "ok", "opt", "start", "text", "xml"}},
+	{func(e *Enum) bool { return e.JSONv2 }, []string{"dec", "enc", "err", "fmt", "i", "jsontext", "ok", "opt", "text", "tok"}},
+	{func(e *Enum) bool { return e.CBOR }, []string{"c", "data", "e", "err", "fmt", "i", "major", "math", "n", "ok", "opt", "rest", "s", "text"}},
+	{func(e *Enum) bool { return e.SQL || e.GORM }, []string{"driver", "fmt", "i", "ok", "opt", "src", "t", "text"}},
+	{func(e *Enum) bool { return e.Formatter }, []string{"f", "fmt", "verb"}},
+	{func(e *Enum) bool { return e.LogValue }, []string{"slog"}},
+	{func(e *Enum) bool { return e.Query }, []string{"key", "q", "url"}},
+	{func(e *Enum) bool { return e.hasDisplay() }, []string{"base", "lang", "names", "ok", "s", "strings"}},
+	{func(e *Enum) bool { return e.hasGRPC() }, []string{"codes", "msg", "status"}},
+}
+
+// validateReceiver reports whether the receiver name of e is a valid
+// identifier that does not conflict with other names in the generated methods.
+func validateReceiver(e *Enum, report func(ValidationError, string, ...any), at func(string) ValidationError) {
+	recv := e.Receiver
+	if !token.IsIdentifier(recv) || recv == "_" {
+		report(at("receiver"), "invalid receiver name %q", recv)
+		return
+	}
+	conflict := types.Universe.Lookup(recv) != nil || recv == e.goType()
+	for _, rc := range receiverConflicts {
+		conflict = conflict || (rc.uses(e) && slices.Contains(rc.names, recv))
+	}
+	if p := e.Proto; p != nil && recv == p.qualifier() {
+		conflict = true
+	}
+	if e.Zero != "" && recv == e.valueName(e.Zero) {
+		conflict = true
+	}
+	for _, v := range e.Values {
+		conflict = conflict || recv == e.valueName(v.Name)
+	}
+	if conflict {
+		report(at("receiver"), "receiver name %q conflicts with a name in the generated code", recv)
+	}
+}
+
+// validatePredicates reports predicate methods of e whose names conflict with
+// other methods of the type, or with each other.
+func validatePredicates(e *Enum, report func(ValidationError, string, ...any), at func(string) ValidationError) {
+	methods := make(map[string]string) // method name → what defines it
+	for _, name := range slices.Sorted(maps.Keys(e.Groups)) {
+		methods["Is"+name] = fmt.Sprintf("group %q", name)
+	}
+	for _, name := range slices.Sorted(maps.Keys(e.Attrs)) {
+		methods[attrMethod(name)] = fmt.Sprintf("attribute %q", name)
+	}
+	for _, key := range slices.Sorted(maps.Keys(methodUsers)) {
+		if name := e.methodName(key); name != "" {
+			methods[name] = fmt.Sprintf("method %q", key)
+		}
+	}
+	names := make([]string, 0, len(e.Values)+1)
+	if e.Zero != "" && !slices.ContainsFunc(e.Values, func(v *Value) bool { return v.Name == e.Zero }) {
+		names = append(names, e.Zero)
+	}
+	for _, v := range e.Values {
+		names = append(names, v.Name)
+	}
+	for _, name := range names {
+		p := predicateName(name)
+		if old, ok := methods[p]; ok {
+			report(at("predicates"), "predicate %s for %q conflicts with %s", p, name, old)
+		}
+		methods[p] = fmt.Sprintf("enumerator %q", name)
+	}
+}
@@ -0,0 +1,154 @@
+package gen_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestValidate(t *testing.T) {
+	zero := 0
+	five := 5
+	cfg := &gen.Config{
+		Enum: []*gen.Enum{{
+			Type:   "Color",
+			Zero:   "Unknown",
+			Naming: "bogus",
+			Values: []*gen.Value{
+				{Name: "Red"},
+				{Name: "Unknown", Index: &five},
+				{Name: ""},
+				{Name: "Red"},
+			},
+		}, {
+			Type:       "Size",
+			ListUnique: true,
+			Values:     []*gen.Value{{Name: "Small", Index: &zero}},
+		}, {
+			Values: []*gen.Value{{Name: "Red"}},
+		}},
+	}
+
+	err := cfg.Validate()
+	var verr gen.ValidationErrors
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate: got %v, want ValidationErrors", err)
+	}
+	type pos struct {
+		Enum  int
+		Value int
+		Field string
+	}
+	var got []pos
+	for _, e := range verr {
+		got = append(got, pos{e.Enum, e.Value, e.Field})
+	}
+	want := []pos{
+		{0, 0, "package"},
+		{1, 0, "naming"},
+		{1, 0, "index"},
+		{1, 3, "name"},
+		{1, 4, "name"},
+		{2, 0, "list-unique"},
+		{3, 0, "type"},
+		{3, 1, "name"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Validate error positions:\ngot  %+v\nwant %+v\n%v", got, want, err)
+	}
+
+	var first *gen.ValidationError
+	if !errors.As(err, &first) {
+		t.Errorf("Validate: error %v does not wrap a ValidationError", err)
+	} else if got, want := first.Error(), "package name not defined"; got != want {
+		t.Errorf("First error: got %q, want %q", got, want)
+	}
+	if got, want := verr[3].Error(), `enum "Color" value 3: name not defined`; got != want {
+		t.Errorf("Value error: got %q, want %q", got, want)
+	}
+
+	cfg.Package = "ok"
+	cfg.Enum = cfg.Enum[1:2]
+	cfg.Enum[0].ListUnique = false
+	cfg.Enum[0].Values[0].Index = nil
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: unexpected error: %v", err)
+	}
+}
+
+func TestNamePolicy(t *testing.T) {
+	cfg := &gen.Config{
+		NamePolicy: &gen.NamePolicy{Style: "go-exported", Reserved: []string{"None"}},
+		Packages: []*gen.PackageConfig{{
+			Output: "a/enums.go",
+			Config: gen.Config{
+				Package: "a",
+				Enum: []*gen.Enum{{
+					Type:   "Color",
+					Values: []*gen.Value{{Name: "Red"}, {Name: "none"}},
+				}, {
+					Type:       "size",
+					NamePolicy: &gen.NamePolicy{Style: "go-identifier"},
+					Values:     []*gen.Value{{Name: "small"}, {Name: "None"}},
+				}},
+			},
+		}},
+	}
+
+	// The package inherits the policy of the config, which the second
+	// enumeration replaces with its own.
+	err := cfg.Validate()
+	var verr gen.ValidationErrors
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate: got %v, want ValidationErrors", err)
+	}
+	type pos struct {
+		Package, Enum, Value int
+		Message              string
+	}
+	var got []pos
+	for _, e := range verr {
+		got = append(got, pos{e.Package, e.Enum, e.Value, e.Message})
+	}
+	want := []pos{
+		{1, 1, 2, `name "none" is reserved by name-policy`},
+		{1, 1, 2, `variable name "none" for "none" is not exported`},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Validate errors:\ngot  %+v\nwant %+v", got, want)
+	}
+
+	cfg.Packages[0].Enum[0].Values[1].Name = "Blue"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: unexpected error: %v", err)
+	}
+	if p := cfg.PackageConfigs()[0].NamePolicy; p != cfg.NamePolicy {
+		t.Errorf("PackageConfigs: got name policy %+v, want %+v", p, cfg.NamePolicy)
+	}
+}
+
+func TestValidateCodeConsts(t *testing.T) {
+	one, two := 1, 2
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{{
+			Type:   "Bar",
+			Values: []*gen.Value{{Name: "Foo", Code: &one}, {Name: "FooCode", Code: &two}},
+		}},
+	}
+
+	// Without code-consts, the name of a code constant is free for use.
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: unexpected error: %v", err)
+	}
+
+	cfg.Enum[0].CodeConsts = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate with code-consts: got nil, want error")
+	} else if !strings.Contains(err.Error(), `conflicts with the code constant of "Foo"`) {
+		t.Errorf("Validate with code-consts: got %v, want a code constant conflict", err)
+	}
+}
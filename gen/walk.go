@@ -0,0 +1,27 @@
+package gen
+
+// Walk calls f for each enumeration of c, and of the packages listed by c, in
+// order. For each enumeration e, it first calls f(e, nil), and then calls
+// f(e, v) for each enumerator v of e, in order, including its zero enumerator
+// if one is defined.
+//
+// Walk is intended for programs that generate other outputs from the same
+// config as enumgen, such as database migrations. Call it after the config
+// has been loaded and its enumerators selected (see SelectValues), so that it
+// visits the enumerators that will be generated. The function f may modify
+// the enumerations and enumerators it visits, but must not add or remove any.
+func (c *Config) Walk(f func(*Enum, *Value)) {
+	walkEnums(c.Enum, f)
+	for _, p := range c.Packages {
+		walkEnums(p.Enum, f)
+	}
+}
+
+func walkEnums(enums []*Enum, f func(*Enum, *Value)) {
+	for _, e := range enums {
+		f(e, nil)
+		for _, v := range e.Values {
+			f(e, v)
+		}
+	}
+}
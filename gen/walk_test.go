@@ -0,0 +1,37 @@
+package gen_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestWalk(t *testing.T) {
+	cfg := &gen.Config{
+		Package: "foo",
+		Enum: []*gen.Enum{
+			{Type: "Mood", Zero: "None", Values: []*gen.Value{{Name: "None"}, {Name: "Happy"}, {Name: "Sad"}}},
+			{Type: "Empty"},
+		},
+		Packages: []*gen.PackageConfig{{
+			Config: gen.Config{
+				Package: "bar",
+				Enum:    []*gen.Enum{{Type: "Color", Values: []*gen.Value{{Name: "Red"}}}},
+			},
+			Output: "bar/enums.go",
+		}},
+	}
+	var got []string
+	cfg.Walk(func(e *gen.Enum, v *gen.Value) {
+		if v == nil {
+			got = append(got, e.Type)
+		} else {
+			got = append(got, e.Type+"."+v.Name)
+		}
+	})
+	want := []string{"Mood", "Mood.None", "Mood.Happy", "Mood.Sad", "Empty", "Color", "Color.Red"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Walk: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+package gen
+
+import (
+	"fmt"
+	"go/build/constraint"
+	"slices"
+)
+
+// SelectValues removes from c the enumerators whose when conditions are not
+// satisfied by the specified tags, and clears the conditions of those that
+// remain. The enumerators of the packages listed by c are also selected.
+//
+// A when condition is a boolean expression over tags, in the syntax of a Go
+// build constraint, for example "experimental && !release". A tag is satisfied
+// if it is one of the given tags. Enumerators without a condition are always
+// kept. It is an error if a condition is not well-formed, or if the zero
+// enumerator has a condition.
+func (c *Config) SelectValues(tags ...string) error {
+	if err := selectValues(c, tags); err != nil {
+		return err
+	}
+	for _, p := range c.Packages {
+		if err := selectValues(&p.Config, tags); err != nil {
+			return fmt.Errorf("package %q: %w", p.Package, err)
+		}
+	}
+	return nil
+}
+
+func selectValues(c *Config, tags []string) error {
+	for _, e := range c.Enum {
+		var keep []*Value
+		for _, v := range e.Values {
+			if v.When == "" {
+				keep = append(keep, v)
+				continue
+			} else if v.Name == e.Zero {
+				return fmt.Errorf("enum %q: zero enumerator %q cannot have a when condition", e.Type, v.Name)
+			}
+			expr, err := parseWhen(v.When)
+			if err != nil {
+				return fmt.Errorf("enum %q: value %q: %w", e.Type, v.Name, err)
+			}
+			if expr.Eval(func(tag string) bool { return slices.Contains(tags, tag) }) {
+				v.When = ""
+				keep = append(keep, v)
+			}
+		}
+		e.Values = keep
+	}
+	return nil
+}
+
+// parseWhen parses the text of a when condition.
+func parseWhen(s string) (constraint.Expr, error) {
+	expr, err := constraint.Parse("//go:build " + s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid when condition %q: %w", s, err)
+	}
+	return expr, nil
+}
@@ -0,0 +1,84 @@
+package gen_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/enumgen/gen"
+)
+
+func TestSelectValues(t *testing.T) {
+	const input = `package: foo
+enum:
+  - type: Feature
+    zero: None
+    values:
+      - name: Search
+      - name: Chat
+        when: experimental
+      - name: Voice
+        when: experimental && !release
+      - name: Legacy
+        when: "!release"
+packages:
+  - package: bar
+    output: bar/enums.go
+    enum:
+      - type: Mode
+        values:
+          - name: Fast
+            when: release
+          - name: Slow
+`
+	tests := []struct {
+		tags []string
+		want []string
+	}{
+		{nil, []string{"Search", "Legacy", "Slow"}},
+		{[]string{"experimental"}, []string{"Search", "Chat", "Voice", "Legacy", "Slow"}},
+		{[]string{"experimental", "release"}, []string{"Search", "Chat", "Fast", "Slow"}},
+		{[]string{"release"}, []string{"Search", "Fast", "Slow"}},
+	}
+	for _, tc := range tests {
+		cfg, err := gen.ParseConfig(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("Parse config: %v", err)
+		}
+		if err := cfg.SelectValues(tc.tags...); err != nil {
+			t.Fatalf("SelectValues(%q): unexpected error: %v", tc.tags, err)
+		}
+		var got []string
+		for _, c := range []*gen.Config{cfg, &cfg.Packages[0].Config} {
+			for _, v := range c.Enum[0].Values {
+				got = append(got, v.Name)
+				if v.When != "" {
+					t.Errorf("Value %q: when %q not cleared", v.Name, v.When)
+				}
+			}
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("SelectValues(%q): got %q, want %q", tc.tags, got, tc.want)
+		}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Validate: unexpected error: %v", err)
+		}
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			value *gen.Value
+			want  string
+		}{
+			{&gen.Value{Name: "X", When: "a &&"}, "invalid when condition"},
+			{&gen.Value{Name: "Z", When: "a"}, "cannot have a when condition"},
+		} {
+			cfg := &gen.Config{Package: "foo", Enum: []*gen.Enum{{
+				Type: "bar", Zero: "Z", Values: []*gen.Value{tc.value},
+			}}}
+			if err := cfg.SelectValues("a"); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("SelectValues %+v: got %v, want %q", tc.value, err, tc.want)
+			}
+		}
+	})
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/creachadair/mds/mapset"
+)
+
+// fileStamp records the modification time and size of a watched file.
+type fileStamp struct {
+	mtime time.Time
+	size  int64
+}
+
+// watchAndGenerate generates the outputs selected by the flags, then polls the
+// input files for changes every delay, and regenerates the outputs when a
+// change is followed by a quiet period of at least delay. It runs until the
+// program is interrupted. A failed regeneration is logged, and does not stop
+// the watch.
+func watchAndGenerate(delay time.Duration) error {
+	roots := watchRoots()
+	regenerate := func(changed int) {
+		start := time.Now()
+		if err := generate(); err != nil {
+			log.Printf("Regeneration failed (files changed: %d): %v", changed, err)
+		} else {
+			log.Printf("Regenerated in %v (files changed: %d)", time.Since(start).Round(time.Millisecond), changed)
+		}
+	}
+
+	if err := generate(); err != nil {
+		log.Printf("Generation failed: %v", err)
+	}
+	last, err := scanFiles(roots)
+	if err != nil {
+		return err
+	}
+	log.Printf("Watching %s for changes", strings.Join(roots, ", "))
+	return watchFiles(context.Background(), roots, last, delay, regenerate)
+}
+
+// watchFiles polls the files in roots every delay, and calls regenerate with
+// the number of files changed when a change from last is followed by a quiet
+// period of at least delay. The outputs written by regenerate do not count as
+// changes, but other files changed while it runs do. It runs until ctx ends.
+func watchFiles(ctx context.Context, roots []string, last map[string]fileStamp, delay time.Duration, regenerate func(changed int)) error {
+	sleep := func() bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
+			return true
+		}
+	}
+	for sleep() {
+		cur, err := scanFiles(roots)
+		if err != nil {
+			return err
+		}
+		if maps.Equal(cur, last) {
+			continue
+		}
+
+		// Wait for the files to settle, so that an editor or a tool writing
+		// several files at once triggers a single regeneration.
+		for {
+			if !sleep() {
+				return nil
+			}
+			next, err := scanFiles(roots)
+			if err != nil {
+				return err
+			}
+			if maps.Equal(next, cur) {
+				break
+			}
+			cur = next
+		}
+		written.reset()
+		regenerate(countChanges(last, cur))
+
+		// Rescan after generating, and take the new stamps of the outputs
+		// written by the generator, so that they do not trigger another
+		// regeneration. The other files keep the stamps they had before, so
+		// that an edit saved while generating does.
+		after, err := scanFiles(roots)
+		if err != nil {
+			return err
+		}
+		last = cur
+		for path, s := range after {
+			if written.has(path) {
+				last[path] = s
+			}
+		}
+	}
+	return nil
+}
+
+// written records the output files written by generate, so that -watch can
+// tell them from changes to the inputs.
+var written outputSet
+
+// An outputSet is a set of output file paths. It is safe for concurrent use
+// by multiple goroutines.
+type outputSet struct {
+	mu    sync.Mutex
+	paths mapset.Set[string] // absolute paths
+}
+
+// add adds path to the set.
+func (o *outputSet) add(path string) {
+	if abs, err := filepath.Abs(path); err == nil {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		o.paths.Add(abs)
+	}
+}
+
+// has reports whether path is in the set.
+func (o *outputSet) has(path string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.paths.Has(abs)
+}
+
+// reset removes all the paths from the set.
+func (o *outputSet) reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.paths.Clear()
+}
+
+// createOutput creates or truncates the output file at path, as os.Create,
+// and records it in written.
+func createOutput(path string) (*os.File, error) {
+	written.add(path)
+	return os.Create(path)
+}
+
+// writeOutput writes data to the output file at path, as os.WriteFile, and
+// records it in written.
+func writeOutput(path string, data []byte) error {
+	written.add(path)
+	return os.WriteFile(path, data, 0644)
+}
+
+// watchRoots returns the directories watched by -watch: the directory of the
+// config file (or the tree at -outdir with -recursive, or the current
+// directory if there is no config file), and the -template-dir if set.
+func watchRoots() []string {
+	var roots []string
+	switch {
+	case *recursive:
+		roots = append(roots, *outDir)
+	case *configPath != "":
+		roots = append(roots, filepath.Dir(*configPath))
+	default:
+		roots = append(roots, ".")
+	}
+	if *tmplDir != "" {
+		roots = append(roots, *tmplDir)
+	}
+	return roots
+}
+
+// scanFiles returns the stamps of the regular files in the directories listed
+// in roots, keyed by path. Subdirectories are included only with -recursive,
+// and hidden subdirectories are skipped.
+func scanFiles(roots []string) (map[string]fileStamp, error) {
+	out := make(map[string]fileStamp)
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil // removed while scanning
+				}
+				return err
+			}
+			if d.IsDir() {
+				if path != root && (!*recursive || strings.HasPrefix(d.Name(), ".")) {
+					return filepath.SkipDir
+				}
+				return nil
+			} else if !d.Type().IsRegular() {
+				return nil
+			}
+			fi, err := d.Info()
+			if err != nil {
+				return nil // removed while scanning
+			}
+			out[path] = fileStamp{mtime: fi.ModTime(), size: fi.Size()}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// countChanges returns the number of files that were added, removed, or
+// modified between the old and cur scans.
+func countChanges(old, cur map[string]fileStamp) int {
+	var n int
+	for path, s := range cur {
+		if o, ok := old[path]; !ok || o != s {
+			n++
+		}
+	}
+	for path := range old {
+		if _, ok := cur[path]; !ok {
+			n++
+		}
+	}
+	return n
+}
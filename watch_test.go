@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchFiles(t *testing.T) {
+	const delay = 10 * time.Millisecond
+	dir := t.TempDir()
+	input := filepath.Join(dir, "enums.yml")
+	output := filepath.Join(dir, "enums.go")
+	write := func(path, text string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(input, "v1")
+	write(output, "// old")
+
+	roots := []string{dir}
+	last, err := scanFiles(roots)
+	if err != nil {
+		t.Fatalf("scanFiles: %v", err)
+	}
+
+	// The first regeneration simulates an edit saved while it runs, which
+	// must trigger a second regeneration. The outputs written by each
+	// regeneration must not trigger another.
+	calls := make(chan int, 10)
+	var n int
+	regenerate := func(changed int) {
+		if n++; n == 1 {
+			write(input, "v2, saved while generating")
+		}
+		f, err := createOutput(output)
+		if err != nil {
+			t.Errorf("createOutput: %v", err)
+			return
+		}
+		f.WriteString("// generated " + time.Now().String())
+		f.Close()
+		calls <- changed
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- watchFiles(ctx, roots, last, delay, regenerate) }()
+
+	write(input, "v1, edited")
+	timeout := time.After(10 * time.Second)
+	for i, want := range []int{1, 1} {
+		select {
+		case got := <-calls:
+			if got != want {
+				t.Errorf("Regeneration %d: got %d files changed, want %d", i+1, got, want)
+			}
+		case <-timeout:
+			t.Fatalf("Timed out waiting for regeneration %d", i+1)
+		}
+	}
+
+	// Give the watcher time to notice the output, which it should ignore.
+	time.Sleep(20 * delay)
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("watchFiles: unexpected error: %v", err)
+	}
+	if n := len(calls); n != 0 {
+		t.Errorf("Got %d extra regenerations, want 0", n)
+	}
+}